@@ -10,6 +10,8 @@ require (
 	github.com/nats-io/nats.go v1.42.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.22.0
+	github.com/ronnyp07/SportStream/pkg/articledb v0.0.0-00010101000000-000000000000
+	github.com/ronnyp07/SportStream/pkg/migrate v0.0.0-00010101000000-000000000000
 	github.com/spf13/viper v1.20.1
 	github.com/stretchr/testify v1.10.0
 	github.com/sts-solutions/base-code v0.3.4
@@ -81,3 +83,8 @@ require (
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/ronnyp07/SportStream/pkg/articledb => ../pkg/articledb
+	github.com/ronnyp07/SportStream/pkg/migrate => ../pkg/migrate
+)
@@ -7,18 +7,29 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/ronnyp07/SportStream/api/internal/app/httpserver"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 	portsMetrics "github.com/ronnyp07/SportStream/api/internal/domain/ports/metrics"
+	portsServices "github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/apikeys"
 	services "github.com/ronnyp07/SportStream/api/internal/domain/services/articles"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/auth"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/stream"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/webhooks"
 	"github.com/ronnyp07/SportStream/api/internal/metrics"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/articleevents"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/database/migrations"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/database/repositories"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/jwt"
 
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Connectors struct {
+	js        jetstream.JetStream
 	tracer    trace.Tracer
 	closeFunc func()
 	db        *MongoDB
@@ -44,13 +55,51 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	if err = a.migrate(a.ctx); err != nil {
+		return err
+	}
+
 	metricsHandler := metrics.NewMetricsHandler()
 	metricsHandler.RegisterMetrics()
 
 	appServices := setupServices(a.connectors, metricsHandler)
 
+	if appServices.AuthServ, err = a.setupAuth(a.ctx); err != nil {
+		return err
+	}
+
+	hub := stream.NewHub(
+		articleevents.NewArticleEvents(a.connectors.js, config.App().Nats.Events.Stream, config.App().Nats.Events.Subject),
+		config.App().LiveStream.ClientBuffer,
+	)
+	go hub.Start(a.ctx)
+	appServices.StreamServ = hub
+
+	if cfg := config.App().Webhooks; cfg.Enabled {
+		events := config.App().Nats.Events
+		dispatcher := webhooks.NewDispatcher(
+			repositories.NewWebhookRepository(a.connectors.db.DB, metricsHandler),
+			articleevents.NewConsumer(a.connectors.js, events.Stream, events.Subject, cfg.Consumer, cfg.AckWait),
+			webhooks.DispatcherConfig{
+				Workers:      cfg.Workers,
+				PollInterval: cfg.PollInterval,
+				Timeout:      cfg.Timeout,
+				Lease:        cfg.Lease,
+				MaxAttempts:  cfg.MaxAttempts,
+				BackoffBase:  cfg.BackoffBase,
+				BackoffMax:   cfg.BackoffMax,
+				DisableAfter: cfg.DisableAfter,
+			},
+		)
+		go dispatcher.Start(a.ctx)
+	}
+
 	server := httpserver.NewServerBuilder(httpserver.Services{
 		ArticleService: appServices.ArticleServ,
+		StreamService:  appServices.StreamServ,
+		WebhookService: appServices.WebhookServ,
+		APIKeyService:  appServices.APIKeyServ,
+		AuthService:    appServices.AuthServ,
 	}).
 		WithAddr(config.App().Http.HostAddress).
 		WithReadTimeout(config.App().Http.ReadTimeout).
@@ -95,8 +144,71 @@ func (a *App) Shutdown() {
 func setupServices(c Connectors, metrics portsMetrics.MetricsHandler) Services {
 	articleRepo := repositories.NewArticleRepository(c.db.DB, metrics)
 	articlesServ := services.NewArticleService(articleRepo)
+	webhookServ := webhooks.NewWebhookService(repositories.NewWebhookRepository(c.db.DB, metrics))
+
+	keysCfg := config.App().APIKeys
+	apiKeyServ := apikeys.NewAPIKeyService(repositories.NewAPIKeyRepository(c.db.DB, metrics), apikeys.Config{
+		DefaultRateLimit:  models.RateLimit{PerSecond: keysCfg.RatePerSecond, Burst: keysCfg.Burst},
+		DefaultDailyQuota: keysCfg.DailyQuota,
+		BootstrapAdminKey: keysCfg.BootstrapAdminKey,
+		CacheTTL:          keysCfg.CacheTTL,
+	})
 	// Implement service setup logic
 	return Services{
 		ArticleServ: articlesServ,
+		WebhookServ: webhookServ,
+		APIKeyServ:  apiKeyServ,
+	}
+}
+
+// setupAuth loads the JWKS bearer tokens are checked against, keeping a
+// remote one fresh. It returns nil when JWT auth is off.
+func (a *App) setupAuth(ctx context.Context) (portsServices.IAuthService, error) {
+	cfg := config.App().Auth.JWT
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	roleMapping := make(map[string]models.Role, len(cfg.RoleMapping))
+	for value, name := range cfg.RoleMapping {
+		role, ok := models.ParseRole(name)
+		if !ok {
+			return nil, fmt.Errorf("mapping %q to unknown role %q", value, name)
+		}
+		roleMapping[value] = role
+	}
+
+	keySet, err := jwt.LoadKeySet(ctx, cfg.JWKS, func(err error) {
+		log.Logger().Info(ctx, fmt.Sprintf("loading the JWKS: %v", err))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading the JWKS: %w", err)
+	}
+	go keySet.StartRefresh(ctx, cfg.RefreshInterval, func(err error) {
+		log.Logger().Error(ctx, fmt.Sprintf("refreshing the JWKS: %v", err))
+	})
+
+	return auth.NewAuthenticator(jwt.NewVerifier(keySet, cfg.Issuer, cfg.Audience, cfg.Leeway), auth.Config{
+		RolesClaim:  cfg.RolesClaim,
+		RoleMapping: roleMapping,
+		RateLimit:   models.RateLimit{PerSecond: cfg.RatePerSecond, Burst: cfg.Burst},
+	}), nil
+}
+
+// migrate brings the api's indexes up to date before serving. In dry-run mode
+// the pending migrations are only logged.
+func (a *App) migrate(ctx context.Context) error {
+	cfg := config.App().Migrations
+	applied, err := migrations.NewRunner(a.connectors.db.DB, migrations.Component, migrations.API()).
+		WithDryRun(cfg.DryRun).
+		WithLock(cfg.LockTTL, cfg.LockWait).
+		Run(ctx)
+	if err != nil {
+		return fmt.Errorf("migrating the database: %w", err)
 	}
+
+	if !cfg.DryRun && len(applied) > 0 {
+		log.Logger().Info(ctx, fmt.Sprintf("applied migrations %v", applied))
+	}
+	return nil
 }
@@ -2,9 +2,13 @@ package app
 
 import (
 	"context"
+	"strconv"
 
 	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/config"
+	appnats "github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/nats"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/otel"
 )
 
@@ -24,16 +28,32 @@ func (a *App) connect(ctx context.Context) (cnn Connectors, err error) {
 		return cnn, errors.Wrap(err, "initializing tracer")
 	}
 
+	// The client retries on failed connects and reconnects on its own; the
+	// live stream resubscribes once it's back.
+	natsConn, err := appnats.Connect(ctx, config.Infra().Nats.Host, strconv.Itoa(config.Infra().Nats.Port),
+		nats.ReconnectWait(config.App().Nats.ReconnectWait))
+	if err != nil {
+		return cnn, errors.Wrap(err, "connecting to the message queue")
+	}
+
+	js, err := jetstream.New(natsConn)
+	if err != nil {
+		natsConn.Close()
+		return cnn, errors.Wrap(err, "creating jetstream context")
+	}
+
 	db, err := SetupMongoDB(a.ctx)
 	if err != nil {
+		natsConn.Close()
 		return cnn, errors.Wrap(err, "setting up db")
 	}
 
 	cnn = Connectors{
 		tracer: tracer,
+		js:     js,
 		db:     db,
 		closeFunc: func() {
-
+			natsConn.Close()
 		},
 	}
 
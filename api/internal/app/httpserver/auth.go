@@ -0,0 +1,189 @@
+package httpserver
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	portsServices "github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/auth"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/sts-solutions/base-code/cclogger"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// authMiddleware works out who is calling and what role they have, and lets
+// each route require a role.
+//
+// A bearer token, when JWT auth is on, is checked against the JWKS and its
+// claims give the role; each subject has its own rate limit. Otherwise, with API keys on, the request needs an
+// active API key within its rate limit and daily quota, which reports what's
+// left of them in X-RateLimit-* headers; admin keys are admins and the rest
+// readers. Callers without credentials are anonymous readers at most, so
+// editor and admin routes always need a token or a key, unless the explicit
+// development flag makes them admins.
+type authMiddleware struct {
+	apiKeys       portsServices.IAPIKeysService
+	tokens        portsServices.IAuthService
+	anonymousRole models.Role
+}
+
+// NewAuthMiddleware takes nil for whichever of API keys and JWT auth is off.
+func NewAuthMiddleware(apiKeys portsServices.IAPIKeysService, tokens portsServices.IAuthService) *authMiddleware {
+	return &authMiddleware{apiKeys: apiKeys, tokens: tokens, anonymousRole: models.RoleReader}
+}
+
+// WithAnonymousAdmin makes callers without credentials admins, for local
+// development only.
+func (m *authMiddleware) WithAnonymousAdmin() *authMiddleware {
+	m.anonymousRole = models.RoleAdmin
+	return m
+}
+
+func (m *authMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := bearerToken(r); ok && m.tokens != nil {
+			principal, err := m.tokens.Authenticate(r.Context(), token)
+			if errors.Is(err, models.ErrUnauthorized) {
+				audit(r, nil, models.RoleNone, err.Error())
+				http.Error(w, "Invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			status := m.tokens.Consume(r.Context(), principal)
+			writeRateLimitHeaders(w.Header(), status)
+			if !status.Allowed {
+				w.Header().Set("Retry-After", seconds(status.Reset))
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+			return
+		}
+
+		if m.apiKeys == nil {
+			principal := &models.Principal{Role: m.anonymousRole, Method: models.AuthMethodAnonymous}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+			return
+		}
+
+		// EventSource and WebSocket clients in browsers can't set headers.
+		plain := r.Header.Get(apiKeyHeader)
+		if plain == "" {
+			plain = r.URL.Query().Get("apiKey")
+		}
+
+		key, err := m.apiKeys.Authenticate(r.Context(), plain)
+		if errors.Is(err, models.ErrUnauthorized) {
+			audit(r, nil, models.RoleNone, "missing or invalid API key")
+			http.Error(w, "Missing or invalid API key", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		status, err := m.apiKeys.Consume(r.Context(), key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeRateLimitHeaders(w.Header(), status)
+		if !status.Allowed {
+			w.Header().Set("Retry-After", seconds(status.Reset))
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		principal := &models.Principal{
+			Subject: key.Name,
+			Role:    models.RoleReader,
+			Method:  models.AuthMethodAPIKey,
+			KeyID:   key.ID,
+		}
+		if key.Admin {
+			principal.Role = models.RoleAdmin
+		}
+		next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+	})
+}
+
+// Require admits callers holding the role; it runs after Handler.
+// Anonymous callers are asked to authenticate, the rest are forbidden.
+func (m *authMiddleware) Require(role models.Role, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.FromContext(r.Context())
+		if principal != nil && principal.Role.Allows(role) {
+			handler(w, r)
+			return
+		}
+
+		audit(r, principal, role, "insufficient role")
+		if principal == nil || principal.Method == models.AuthMethodAnonymous {
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Role "+role.String()+" required", http.StatusForbidden)
+	})
+}
+
+// audit logs a denied request with who made it and what it needed.
+func audit(r *http.Request, principal *models.Principal, required models.Role, reason string) {
+	fields := []cclogger.LogField{
+		{Key: "audit", Value: "access_denied"},
+		{Key: "reason", Value: reason},
+		{Key: "method", Value: r.Method},
+		{Key: "path", Value: r.URL.Path},
+		{Key: "remoteAddr", Value: r.RemoteAddr},
+		{Key: "userAgent", Value: r.UserAgent()},
+	}
+	if required != models.RoleNone {
+		fields = append(fields, cclogger.LogField{Key: "requiredRole", Value: required.String()})
+	}
+	if principal != nil {
+		fields = append(fields,
+			cclogger.LogField{Key: "subject", Value: principal.Subject},
+			cclogger.LogField{Key: "role", Value: principal.Role.String()},
+			cclogger.LogField{Key: "authMethod", Value: principal.Method},
+		)
+		if principal.KeyID != "" {
+			fields = append(fields, cclogger.LogField{Key: "apiKeyID", Value: principal.KeyID})
+		}
+	}
+	log.Logger().Warn(r.Context(), "access denied", fields...)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func writeRateLimitHeaders(header http.Header, status models.RateLimitStatus) {
+	if status.Limit > 0 {
+		header.Set("X-RateLimit-Limit", strconv.Itoa(status.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(status.Remaining))
+		header.Set("X-RateLimit-Reset", seconds(status.Reset))
+	}
+	if status.QuotaLimit > 0 {
+		header.Set("X-RateLimit-Quota-Limit", strconv.Itoa(status.QuotaLimit))
+		header.Set("X-RateLimit-Quota-Remaining", strconv.Itoa(status.QuotaRemaining))
+		header.Set("X-RateLimit-Quota-Reset", strconv.FormatInt(status.QuotaReset.Unix(), 10))
+	}
+}
+
+// seconds rounds up, so clients waiting that long find a token.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,132 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("api-test"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// tokenService accepts one token, as an editor, whose subject is over its
+// rate limit when limited.
+type tokenService struct {
+	limited bool
+}
+
+func (tokenService) Authenticate(_ context.Context, token string) (*models.Principal, error) {
+	if token != "editor-token" {
+		return nil, models.ErrUnauthorized
+	}
+	return &models.Principal{Subject: "alice", Role: models.RoleEditor, Method: models.AuthMethodJWT}, nil
+}
+
+func (s tokenService) Consume(context.Context, *models.Principal) models.RateLimitStatus {
+	if s.limited {
+		return models.RateLimitStatus{Limit: 40, Reset: time.Second}
+	}
+	return models.RateLimitStatus{Allowed: true, Limit: 40, Remaining: 39}
+}
+
+func TestAuthMiddleware_Require(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		middleware func() *authMiddleware
+		role       models.Role
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "auth off, anonymous reader",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, nil) },
+			role:       models.RoleReader,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "auth off, editor route denied",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, nil) },
+			role:       models.RoleEditor,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "auth off, admin route denied",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, nil) },
+			role:       models.RoleAdmin,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "JWT only, anonymous admin route denied",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, tokenService{}) },
+			role:       models.RoleAdmin,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "JWT only, editor token on editor route",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, tokenService{}) },
+			role:       models.RoleEditor,
+			token:      "editor-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "JWT only, editor token on admin route",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, tokenService{}) },
+			role:       models.RoleAdmin,
+			token:      "editor-token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "JWT only, invalid token",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, tokenService{}) },
+			role:       models.RoleReader,
+			token:      "forged",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "JWT only, subject over its rate limit",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, tokenService{limited: true}) },
+			role:       models.RoleReader,
+			token:      "editor-token",
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "insecure anonymous admin",
+			middleware: func() *authMiddleware { return NewAuthMiddleware(nil, nil).WithAnonymousAdmin() },
+			role:       models.RoleAdmin,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := tt.middleware()
+			handler := m.Handler(m.Require(tt.role, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/sportstream/admin/keys", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
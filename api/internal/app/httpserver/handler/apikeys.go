@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
+)
+
+type APIKeyHandler struct {
+	service services.IAPIKeysService
+}
+
+func NewAPIKeyHandler(service services.IAPIKeysService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+// IssueKey godoc
+// @Summary Issue an API key
+// @Description Issue a key with its rate limit and daily quota; zero limits take the defaults and a negative quota is unlimited. The key is only returned here
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param key body models.APIKeyRequest true "Name, admin flag and limits"
+// @Success 201 {object} models.IssuedAPIKey
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/keys [post]
+func (h *APIKeyHandler) IssueKey(w http.ResponseWriter, r *http.Request) {
+	var request models.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid API key request", http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.service.IssueKey(r.Context(), request)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+// ListKeys godoc
+// @Summary List API keys
+// @Description List the issued keys, revoked ones included, without their secrets
+// @Tags admin
+// @Produce  json
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/keys [get]
+func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListKeys(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// RotateKey godoc
+// @Summary Rotate an API key
+// @Description Replace a key's secret, keeping its ID and limits. The old secret stops working
+// @Tags admin
+// @Produce  json
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.IssuedAPIKey
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.service.RotateKey(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
+
+// RevokeKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key for good; it stays listed with its revocation time
+// @Tags admin
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RevokeKey(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+// CreateArticle godoc
+// @Summary Write a story
+// @Description Store a story written by an editor, with source "editorial". publishedAt defaults to now. A retry with the same Idempotency-Key, or without one the same story, returns the story already created. Needs the editor role
+// @Tags editorial
+// @Accept  json
+// @Produce  json
+// @Param Idempotency-Key header string false "Identifies the request across retries"
+// @Param article body models.ArticleRequest true "The story; title is required"
+// @Success 201 {object} models.Article
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /articles [post]
+func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
+	var request models.ArticleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid article", http.StatusBadRequest)
+		return
+	}
+	request.IdempotencyKey = r.Header.Get("Idempotency-Key")
+
+	article, err := h.service.CreateArticle(r.Context(), request)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(article)
+}
+
+// UpdateArticle godoc
+// @Summary Override article fields
+// @Description Override the title, description, summary or body of an article. Overrides are kept when the feed sends a new version; list fields in revert to restore the feed's values. Every edit makes a revision recording who made it. Needs the editor role
+// @Tags editorial
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Param patch body models.ArticlePatch true "Fields to override or revert"
+// @Success 200 {object} models.Article
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /articles/{id} [patch]
+func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	var patch models.ArticlePatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, "Invalid patch", http.StatusBadRequest)
+		return
+	}
+
+	article, err := h.service.UpdateArticle(r.Context(), id, patch)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(article)
+}
+
+// DeleteArticle godoc
+// @Summary Hide an article
+// @Description Hide an article from every read. It stays stored, and the feed doesn't bring it back. Needs the editor role
+// @Tags editorial
+// @Param id path int true "Article ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /articles/{id} [delete]
+func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.HideArticle(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,11 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	_ "github.com/ronnyp07/SportStream/api/docs"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 	"github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
 )
 
@@ -95,25 +100,225 @@ func (h *ArticleHandler) GetArticleByExternalID(w http.ResponseWriter, r *http.R
 
 // GetPaginatedArticles godoc
 // @Summary Get paginated articles
-// @Description Get articles with pagination support
+// @Description Get articles with pagination support. Pass cursor (empty for the first page) to page by nextCursor instead of page numbers
 // @Tags articles
 // @Accept  json
 // @Produce  json
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Items per page" default(20)
+// @Param cursor query string false "nextCursor of the previous page, empty to start"
+// @Param includeTotal query bool false "Count the matching articles in cursor mode" default(false)
+// @Param tag query []string false "Tag ID or label, repeatable" collectionFormat(multi)
+// @Param tagMatch query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param from query string false "Published at or after, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Published at or before, RFC 3339 or YYYY-MM-DD (whole day)"
+// @Param mediaType query string false "Lead media type"
+// @Param source query string false "Source the article was polled from"
 // @Success 200 {object} models.PaginatedArticles
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /articles [get]
 func (h *ArticleHandler) GetPaginatedArticles(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
 
-	result, err := h.service.GetPaginatedArticles(r.Context(), page, pageSize)
+	filter, err := articleFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result *models.PaginatedArticles
+	if cursor, ok := r.URL.Query()["cursor"]; ok {
+		includeTotal := false
+		if value := r.URL.Query().Get("includeTotal"); value != "" {
+			if includeTotal, err = strconv.ParseBool(value); err != nil {
+				http.Error(w, "Invalid includeTotal", http.StatusBadRequest)
+				return
+			}
+		}
+		result, err = h.service.GetArticlesByCursor(r.Context(), cursor[0], pageSize, includeTotal, filter)
+	} else {
+		result, err = h.service.GetPaginatedArticles(r.Context(), page, pageSize, filter)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SearchArticles godoc
+// @Summary Search articles
+// @Description Full-text search over title, summary, description and body, most relevant first, with highlighted snippets
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search query; quote phrases, prefix a word with - to exclude it"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Items per page" default(20)
+// @Success 200 {object} models.SearchResults
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /articles/search [get]
+func (h *ArticleHandler) SearchArticles(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	result, err := h.service.SearchArticles(r.Context(), r.URL.Query().Get("q"), page, pageSize)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
+
+// GetArticleRevisions godoc
+// @Summary Get article revisions
+// @Description Get the previous versions of an article with the fields each change touched, newest first
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Success 200 {array} models.ArticleRevision
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /articles/{id}/revisions [get]
+func (h *ArticleHandler) GetArticleRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.service.GetArticleRevisions(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetArticleRevision godoc
+// @Summary Get an article revision
+// @Description Get a previous version of an article by its revision number
+// @Tags articles
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Article ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.ArticleRevision
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /articles/{id}/revisions/{rev} [get]
+func (h *ArticleHandler) GetArticleRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.Atoi(vars["rev"])
+	if err != nil {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	articleRevision, err := h.service.GetArticleRevision(r.Context(), id, revision)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(articleRevision)
+}
+
+// errorStatus answers 400 for rejected parameters, 404 for missing resources
+// and 500 otherwise.
+func errorStatus(err error) int {
+	var validationErr models.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, models.ErrConflict) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, models.ErrUnauthorized) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// articleFilter reads the listing filters from the query string. Numeric tags
+// are tag IDs, anything else a label.
+func articleFilter(query url.Values) (models.ArticleFilter, error) {
+	filter := models.ArticleFilter{
+		TagMatch:  models.TagMatch(query.Get("tagMatch")),
+		MediaType: query.Get("mediaType"),
+		Source:    query.Get("source"),
+	}
+
+	var err error
+	if filter.TagIDs, filter.TagLabels, err = parseTags(query); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseDateParam(query, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDateParam(query, "to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseTags splits the repeatable tag parameter into tag IDs, the numeric
+// values, and labels.
+func parseTags(query url.Values) (ids []int, labels []string, err error) {
+	for _, tag := range query["tag"] {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, nil, models.ValidationError{Field: "tag", Reason: "empty tag"}
+		}
+		if id, err := strconv.Atoi(tag); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		labels = append(labels, tag)
+	}
+	return ids, labels, nil
+}
+
+// parseDateParam accepts an RFC 3339 timestamp or a date. A date covers the
+// whole day, so as an upper bound it means the last instant of that day.
+func parseDateParam(query url.Values, name string, endOfDay bool) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, models.ValidationError{Field: name, Reason: "expected an RFC 3339 timestamp or YYYY-MM-DD"}
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &day, nil
+}
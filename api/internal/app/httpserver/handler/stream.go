@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/websocket"
+)
+
+type StreamHandler struct {
+	service   services.IStreamService
+	heartbeat time.Duration
+	retry     time.Duration
+	upgrader  *websocket.Upgrader
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewStreamHandler(service services.IStreamService, heartbeat time.Duration, retry time.Duration) *StreamHandler {
+	return &StreamHandler{
+		service:   service,
+		heartbeat: heartbeat,
+		retry:     retry,
+		upgrader:  websocket.NewUpgrader(nil),
+		done:      make(chan struct{}),
+	}
+}
+
+// WithAllowedOrigins sets the origins, besides the API's own, that browsers
+// may open the WebSocket stream from.
+func (h *StreamHandler) WithAllowedOrigins(origins []string) *StreamHandler {
+	h.upgrader = websocket.NewUpgrader(origins)
+	return h
+}
+
+// Close ends the open streams, which the server's graceful shutdown would
+// otherwise wait on forever.
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// StreamArticles godoc
+// @Summary Stream article events
+// @Description Server-Sent Events of articles as the worker creates and updates them. Each event's id is its stream sequence; reconnecting with Last-Event-ID (or lastEventId) replays what was missed. An event with only an id moves the resume position past events the filter left out
+// @Tags articles
+// @Produce  text/event-stream
+// @Param tag query []string false "Tag ID or label, repeatable; any of them matches" collectionFormat(multi)
+// @Param source query string false "Source the article was polled from"
+// @Param lastEventId query int false "Resume after this event, when the Last-Event-ID header can't be set"
+// @Param Last-Event-ID header int false "Resume after this event"
+// @Success 200 {object} models.StreamEvent
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /articles/stream [get]
+func (h *StreamHandler) StreamArticles(w http.ResponseWriter, r *http.Request) {
+	filter, lastEventID, err := streamRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's write timeout would cut the stream off.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, err := h.service.Subscribe(r.Context(), filter, lastEventID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", h.retry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				log.Logger().Error(r.Context(), fmt.Sprintf("writing article event %d: %v", event.Sequence, err))
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// StreamArticlesWS godoc
+// @Summary Stream article events over WebSocket
+// @Description The article event stream as WebSocket text messages, one JSON event each. Resume with lastEventId; the server pings on every heartbeat. Browsers may only connect from the API's own origin or an allowed one
+// @Tags articles
+// @Produce  json
+// @Param tag query []string false "Tag ID or label, repeatable; any of them matches" collectionFormat(multi)
+// @Param source query string false "Source the article was polled from"
+// @Param lastEventId query int false "Resume after this event"
+// @Success 101 {object} models.StreamEvent
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /articles/ws [get]
+func (h *StreamHandler) StreamArticlesWS(w http.ResponseWriter, r *http.Request) {
+	if err := h.upgrader.CheckOrigin(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	filter, lastEventID, err := streamRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The hijacked connection doesn't cancel the request context, so the
+	// reader below does when the client goes away.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	events, err := h.service.Subscribe(ctx, filter, lastEventID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r)
+	if err != nil {
+		cancel()
+		status := http.StatusBadRequest
+		if errors.Is(err, websocket.ErrOriginNotAllowed) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	go func() {
+		defer cancel()
+		for {
+			if _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				conn.Close(websocket.CloseGoingAway, "stream ended")
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				conn.Close(websocket.CloseInternalError, "encoding event")
+				return
+			}
+			if err := conn.WriteText(data); err != nil {
+				conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.Ping(); err != nil {
+				conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-ctx.Done():
+			conn.Close(websocket.CloseNormal, "")
+			return
+		case <-h.done:
+			conn.Close(websocket.CloseGoingAway, "server shutting down")
+			return
+		}
+	}
+}
+
+// writeEvent writes a Server-Sent Event. Checkpoints carry only their id,
+// which the client keeps as its resume position without dispatching anything.
+func writeEvent(w http.ResponseWriter, event models.StreamEvent) error {
+	if event.Type == models.StreamCheckpoint {
+		_, err := fmt.Fprintf(w, "id: %d\n\n", event.Sequence)
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+	return err
+}
+
+// streamRequest reads the client's filter and resume position. Browsers send
+// Last-Event-ID on reconnect; lastEventId covers clients that can't set it.
+func streamRequest(r *http.Request) (models.StreamFilter, uint64, error) {
+	query := r.URL.Query()
+	filter := models.StreamFilter{Source: query.Get("source")}
+
+	var err error
+	if filter.TagIDs, filter.TagLabels, err = parseTags(query); err != nil {
+		return filter, 0, err
+	}
+
+	lastEventID, err := parseLastEventID(r.Header.Get("Last-Event-ID"), query)
+	if err != nil {
+		return filter, 0, err
+	}
+	return filter, lastEventID, nil
+}
+
+func parseLastEventID(header string, query url.Values) (uint64, error) {
+	value := header
+	if value == "" {
+		value = query.Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, models.ValidationError{Field: "lastEventId", Reason: "expected a stream sequence number"}
+	}
+	return id, nil
+}
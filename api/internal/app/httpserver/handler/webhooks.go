@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
+)
+
+type WebhookHandler struct {
+	service services.IWebhooksService
+}
+
+func NewWebhookHandler(service services.IWebhooksService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Register a URL to be POSTed the article events matching the filter. Deliveries are signed with HMAC-SHA256 of "<timestamp>.<body>" under the secret, sent in X-SportStream-Signature with the timestamp in X-SportStream-Timestamp
+// @Tags webhooks
+// @Accept  json
+// @Produce  json
+// @Param webhook body models.WebhookRequest true "URL, filter and secret of at least 16 characters"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var request models.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(r.Context(), request)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List the registered webhooks, disabled ones included
+// @Tags webhooks
+// @Produce  json
+// @Success 200 {array} models.Webhook
+// @Failure 500 {object} map[string]string
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Description Get a webhook with its failure count and why it was disabled, if it was
+// @Tags webhooks
+// @Produce  json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := h.service.GetWebhook(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhooks
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteWebhook(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableWebhook godoc
+// @Summary Re-enable a webhook
+// @Description Turn a disabled webhook back on and reset its failure count
+// @Tags webhooks
+// @Produce  json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id}/enable [post]
+func (h *WebhookHandler) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := h.service.EnableWebhook(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get a webhook's delivery log
+// @Description Get the deliveries queued for a webhook with every attempt made, newest first
+// @Tags webhooks
+// @Produce  json
+// @Param id path string true "Webhook ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Items per page" default(20)
+// @Success 200 {object} models.PaginatedDeliveries
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	deliveries, err := h.service.GetDeliveries(r.Context(), mux.Vars(r)["id"], page, pageSize)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	_ "github.com/ronnyp07/SportStream/api/docs"
 	"github.com/ronnyp07/SportStream/api/internal/app/httpserver/handler"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 	portsHandler "github.com/ronnyp07/SportStream/api/internal/domain/ports/handler"
+	portsServices "github.com/ronnyp07/SportStream/api/internal/domain/ports/services"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/config"
 	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -35,6 +37,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the flusher and hijacker the
+// article stream needs.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func NewMetricsMiddleware() *metricsMiddleware {
 	return &metricsMiddleware{
 		requestsTotal: prometheus.NewCounterVec(
@@ -121,7 +129,26 @@ func (s *Server) setupHandler() {
 	prometheus.MustRegister(metricsMiddleware.requestDuration)
 
 	articleHandler := handler.NewArticleHandler(s.Services.ArticleService)
-	router := NewRouter(articleHandler, metricsMiddleware)
+	streamHandler := handler.NewStreamHandler(s.Services.StreamService,
+		config.App().LiveStream.Heartbeat, config.App().LiveStream.Retry).
+		WithAllowedOrigins(config.App().LiveStream.AllowedOrigins)
+	s.httpServer.RegisterOnShutdown(streamHandler.Close)
+
+	webhookHandler := handler.NewWebhookHandler(s.Services.WebhookService)
+	apiKeyHandler := handler.NewAPIKeyHandler(s.Services.APIKeyService)
+
+	var apiKeys portsServices.IAPIKeysService
+	if config.App().APIKeys.Enabled {
+		apiKeys = s.Services.APIKeyService
+	}
+	auth := NewAuthMiddleware(apiKeys, s.Services.AuthService)
+	if config.App().Auth.InsecureAnonymousAdmin {
+		log.Logger().Warn(context.Background(),
+			"AUTH_INSECURE_ANONYMOUS_ADMIN is on: every caller without credentials is an admin. Never run this outside local development")
+		auth.WithAnonymousAdmin()
+	}
+
+	router := NewRouter(articleHandler, streamHandler, webhookHandler, apiKeyHandler, auth, metricsMiddleware)
 	s.Routes = router
 
 }
@@ -134,7 +161,9 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
-func NewRouter(articleHandler portsHandler.IHandler, metrics *metricsMiddleware) *mux.Router {
+func NewRouter(articleHandler portsHandler.IHandler, streamHandler portsHandler.IStreamHandler,
+	webhookHandler portsHandler.IWebhookHandler, apiKeyHandler portsHandler.IAPIKeyHandler,
+	auth *authMiddleware, metrics *metricsMiddleware) *mux.Router {
 	r := mux.NewRouter()
 
 	// Apply metrics middleware to all routes
@@ -146,13 +175,40 @@ func NewRouter(articleHandler portsHandler.IHandler, metrics *metricsMiddleware)
 	// Metrics endpoint
 	r.Handle("/metrics", promhttp.Handler())
 
-	// API versioning
+	// API versioning; everything under it is authenticated and each route
+	// declares the role it needs, while health, metrics and the documentation
+	// stay open.
 	api := r.PathPrefix("/api/v1").Subrouter()
+	api.Use(auth.Handler)
 
 	// Article routes
-	api.HandleFunc("/articles/{id:[0-9]+}", articleHandler.GetArticleByID).Methods("GET")
-	api.HandleFunc("/articles/external/{externalID:[0-9]+}", articleHandler.GetArticleByExternalID).Methods("GET")
-	api.HandleFunc("/articles", articleHandler.GetPaginatedArticles).Methods("GET")
+	api.Handle("/articles/{id:[0-9]+}", auth.Require(models.RoleReader, articleHandler.GetArticleByID)).Methods("GET")
+	api.Handle("/articles/external/{externalID:[0-9]+}", auth.Require(models.RoleReader, articleHandler.GetArticleByExternalID)).Methods("GET")
+	api.Handle("/articles", auth.Require(models.RoleReader, articleHandler.GetPaginatedArticles)).Methods("GET")
+	api.Handle("/articles/search", auth.Require(models.RoleReader, articleHandler.SearchArticles)).Methods("GET")
+	api.Handle("/articles/stream", auth.Require(models.RoleReader, streamHandler.StreamArticles)).Methods("GET")
+	api.Handle("/articles/ws", auth.Require(models.RoleReader, streamHandler.StreamArticlesWS)).Methods("GET")
+	api.Handle("/articles/{id:[0-9]+}/revisions", auth.Require(models.RoleReader, articleHandler.GetArticleRevisions)).Methods("GET")
+	api.Handle("/articles/{id:[0-9]+}/revisions/{rev:[0-9]+}", auth.Require(models.RoleReader, articleHandler.GetArticleRevision)).Methods("GET")
+
+	// Editorial routes
+	api.Handle("/articles", auth.Require(models.RoleEditor, articleHandler.CreateArticle)).Methods("POST")
+	api.Handle("/articles/{id:[0-9]+}", auth.Require(models.RoleEditor, articleHandler.UpdateArticle)).Methods("PATCH")
+	api.Handle("/articles/{id:[0-9]+}", auth.Require(models.RoleEditor, articleHandler.DeleteArticle)).Methods("DELETE")
+
+	// Webhook routes
+	api.Handle("/webhooks", auth.Require(models.RoleAdmin, webhookHandler.CreateWebhook)).Methods("POST")
+	api.Handle("/webhooks", auth.Require(models.RoleAdmin, webhookHandler.ListWebhooks)).Methods("GET")
+	api.Handle("/webhooks/{id:[0-9a-f]+}", auth.Require(models.RoleAdmin, webhookHandler.GetWebhook)).Methods("GET")
+	api.Handle("/webhooks/{id:[0-9a-f]+}", auth.Require(models.RoleAdmin, webhookHandler.DeleteWebhook)).Methods("DELETE")
+	api.Handle("/webhooks/{id:[0-9a-f]+}/enable", auth.Require(models.RoleAdmin, webhookHandler.EnableWebhook)).Methods("POST")
+	api.Handle("/webhooks/{id:[0-9a-f]+}/deliveries", auth.Require(models.RoleAdmin, webhookHandler.GetWebhookDeliveries)).Methods("GET")
+
+	// API key administration
+	api.Handle("/admin/keys", auth.Require(models.RoleAdmin, apiKeyHandler.IssueKey)).Methods("POST")
+	api.Handle("/admin/keys", auth.Require(models.RoleAdmin, apiKeyHandler.ListKeys)).Methods("GET")
+	api.Handle("/admin/keys/{id:[0-9a-f]+}/rotate", auth.Require(models.RoleAdmin, apiKeyHandler.RotateKey)).Methods("POST")
+	api.Handle("/admin/keys/{id:[0-9a-f]+}", auth.Require(models.RoleAdmin, apiKeyHandler.RevokeKey)).Methods("DELETE")
 
 	// Health check
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
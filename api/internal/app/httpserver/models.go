@@ -9,6 +9,11 @@ import (
 
 type Services struct {
 	ArticleService portsServices.IArticlesService
+	StreamService  portsServices.IStreamService
+	WebhookService portsServices.IWebhooksService
+	APIKeyService  portsServices.IAPIKeysService
+	// AuthService is nil without JWT auth.
+	AuthService portsServices.IAuthService
 }
 
 type Server struct {
@@ -6,4 +6,8 @@ import (
 
 type Services struct {
 	ArticleServ services.IArticlesService
+	StreamServ  services.IStreamService
+	WebhookServ services.IWebhooksService
+	APIKeyServ  services.IAPIKeysService
+	AuthServ    services.IAuthService
 }
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// APIKey identifies a client of the API. Only the SHA-256 of the key is
+// stored; the key itself is shown once, when issued or rotated.
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id"`
+	Name       string     `json:"name" bson:"name"`
+	Prefix     string     `json:"prefix" bson:"prefix"`
+	Hash       string     `json:"-" bson:"hash"`
+	Admin      bool       `json:"admin" bson:"admin"`
+	RateLimit  RateLimit  `json:"rateLimit" bson:"rateLimit"`
+	DailyQuota int        `json:"dailyQuota" bson:"dailyQuota"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	RotatedAt  *time.Time `json:"rotatedAt,omitempty" bson:"rotatedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
+
+// RateLimit is a token bucket: Burst requests at once, refilled at
+// PerSecond.
+type RateLimit struct {
+	PerSecond float64 `json:"perSecond" bson:"perSecond"`
+	Burst     int     `json:"burst" bson:"burst"`
+}
+
+// APIKeyRequest issues a key. Zero limits take the configured defaults; a
+// negative quota means unlimited.
+type APIKeyRequest struct {
+	Name       string    `json:"name"`
+	Admin      bool      `json:"admin"`
+	RateLimit  RateLimit `json:"rateLimit"`
+	DailyQuota int       `json:"dailyQuota"`
+}
+
+// IssuedAPIKey carries the plain key, returned only when it's issued or
+// rotated.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// RateLimitStatus is what's left of a key's rate limit and daily quota after
+// a request.
+type RateLimitStatus struct {
+	Allowed        bool
+	Limit          int
+	Remaining      int
+	Reset          time.Duration
+	QuotaLimit     int
+	QuotaRemaining int
+	QuotaReset     time.Time
+}
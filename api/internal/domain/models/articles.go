@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type ArticleResponse struct {
 	Content []Article `json:"content"`
 }
@@ -10,7 +12,8 @@ type UpsertArticle struct {
 }
 
 type Article struct {
-	ExternalID  int    `json:"externalID"`
+	// ExternalID is the feed's ID; stories written by editors have none.
+	ExternalID  int    `json:"externalID" bson:"externalID,omitempty"`
 	ID          int    `json:"id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
@@ -19,12 +22,19 @@ type Article struct {
 	Summary     string `json:"summary"`
 	LeadMedia   Media  `json:"leadMedia"`
 	Tags        []Tag  `json:"tags"`
+	Source      string `json:"source"`
+	// PublishedAt is Date normalized to UTC, absent when the date could not be
+	// parsed. UpdatedAt is when the current revision was stored.
+	PublishedAt *time.Time `json:"publishedAt,omitempty" bson:"publishedAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	Editorial   *Editorial `json:"editorial,omitempty" bson:"editorial,omitempty"`
 }
 
 type Media struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 	Type  string `json:"type"`
+	URL   string `json:"url,omitempty"`
 }
 
 type Tag struct {
@@ -32,14 +42,87 @@ type Tag struct {
 	Label string `json:"label"`
 }
 
+// PaginatedArticles is a page of the listing. Offset pages carry PageInfo;
+// cursor pages carry NextCursor while more articles follow, and Total when
+// it was asked for.
 type PaginatedArticles struct {
-	PageInfo PageInfo  `json:"pageInfo"`
-	Content  []Article `json:"content"`
+	PageInfo   *PageInfo `json:"pageInfo,omitempty"`
+	Content    []Article `json:"content"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Total      *int      `json:"total,omitempty"`
+}
+
+// ArticleCursor is the listing position after an article: its publish date
+// (nil for undated articles, which come last) and ID.
+type ArticleCursor struct {
+	PublishedAt *time.Time
+	ID          int
+}
+
+// ArticleFilter narrows the article listing; empty fields don't filter.
+// Tags are matched by ID or label, all of them or any of them.
+type ArticleFilter struct {
+	TagIDs    []int
+	TagLabels []string
+	TagMatch  TagMatch
+	From      *time.Time
+	To        *time.Time
+	MediaType string
+	Source    string
 }
 
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
 type PageInfo struct {
 	Page       int `json:"page"`
 	NumPages   int `json:"numPages"`
 	PageSize   int `json:"pageSize"`
 	NumEntries int `json:"numEntries"`
 }
+
+// SearchResults is a page of articles matching a search, most relevant first.
+type SearchResults struct {
+	Query    string         `json:"query"`
+	PageInfo PageInfo       `json:"pageInfo"`
+	Content  []SearchResult `json:"content"`
+}
+
+// SearchResult is a matching article with its text search score and the
+// passages of each field that matched.
+type SearchResult struct {
+	Article    `bson:",inline"`
+	Score      float64     `json:"score" bson:"score"`
+	Highlights []Highlight `json:"highlights" bson:"-"`
+}
+
+// Highlight is a passage of a field with the matched terms wrapped in <mark>.
+// The snippet is HTML, with the field's text escaped.
+type Highlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// ArticleRevision is a previous version of an article together with the
+// changes that replaced it.
+type ArticleRevision struct {
+	ArticleID  int           `json:"articleID" bson:"articleID"`
+	ExternalID int           `json:"externalID" bson:"externalID"`
+	Revision   int           `json:"revision" bson:"revision"`
+	Article    Article       `json:"article" bson:"article"`
+	Changes    []FieldChange `json:"changes" bson:"changes"`
+	CreatedAt  time.Time     `json:"createdAt" bson:"createdAt"`
+	// EditedBy is the editor whose edit replaced this version; feed updates
+	// have none.
+	EditedBy *Editor `json:"editedBy,omitempty" bson:"editedBy,omitempty"`
+}
+
+type FieldChange struct {
+	Field    string      `json:"field" bson:"field"`
+	Previous interface{} `json:"previous" bson:"previous"`
+	Current  interface{} `json:"current" bson:"current"`
+}
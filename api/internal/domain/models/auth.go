@@ -0,0 +1,59 @@
+package models
+
+import "strings"
+
+// Role is what a caller may do. Each role includes the ones below it.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleReader
+	RoleEditor
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:   "none",
+	RoleReader: "reader",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "none"
+}
+
+// Allows reports whether the role grants the required one.
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole reads a role name, case insensitively.
+func ParseRole(name string) (Role, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for role, roleName := range roleNames {
+		if role != RoleNone && roleName == name {
+			return role, true
+		}
+	}
+	return RoleNone, false
+}
+
+const (
+	AuthMethodJWT       = "jwt"
+	AuthMethodAPIKey    = "apiKey"
+	AuthMethodAnonymous = "anonymous"
+)
+
+// Principal is the caller of a request: a token's subject, an API key, or
+// nobody when authentication is off.
+type Principal struct {
+	Subject string
+	Role    Role
+	Method  string
+	// KeyID is the API key the request came with, if any.
+	KeyID string
+}
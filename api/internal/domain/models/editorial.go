@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// EditorialSource is the source of the stories editors write.
+const EditorialSource = "editorial"
+
+// EditableFields are the article fields editors can override, by their name
+// in the article document.
+var EditableFields = []string{"title", "description", "summary", "body"}
+
+// Editor is who made an editorial change.
+type Editor struct {
+	Subject string `json:"subject" bson:"subject"`
+	Method  string `json:"method" bson:"method"`
+}
+
+// Editorial is the editors' state of an article, written by the api only.
+// Overrides replace the feed's values, and keep replacing them when the
+// worker stores a new version from the feed.
+type Editorial struct {
+	// Manual marks a story written by editors rather than polled.
+	Manual    bool              `json:"manual,omitempty" bson:"manual,omitempty"`
+	Overrides map[string]string `json:"overrides,omitempty" bson:"overrides,omitempty"`
+	// Hidden articles are left out of every read.
+	Hidden   bool      `json:"hidden,omitempty" bson:"hidden,omitempty"`
+	EditedBy Editor    `json:"editedBy" bson:"editedBy"`
+	EditedAt time.Time `json:"editedAt" bson:"editedAt"`
+}
+
+// ArticleRequest is a story written by an editor. PublishedAt defaults to
+// when it's created.
+type ArticleRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Summary     string     `json:"summary"`
+	Body        string     `json:"body"`
+	LeadMedia   Media      `json:"leadMedia"`
+	Tags        []Tag      `json:"tags"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	// IdempotencyKey comes from the Idempotency-Key header. A retried request
+	// with the same key returns the story the first one created.
+	IdempotencyKey string `json:"-"`
+}
+
+// ArticlePatch overrides the given fields of an article; absent fields are
+// left alone. Revert drops overrides, restoring the feed's values.
+type ArticlePatch struct {
+	Title       *string  `json:"title,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Summary     *string  `json:"summary,omitempty"`
+	Body        *string  `json:"body,omitempty"`
+	Revert      []string `json:"revert,omitempty"`
+}
+
+// Fields returns the patched fields by their name in the article document.
+func (p ArticlePatch) Fields() map[string]string {
+	fields := map[string]string{}
+	for name, value := range map[string]*string{
+		"title":       p.Title,
+		"description": p.Description,
+		"summary":     p.Summary,
+		"body":        p.Body,
+	} {
+		if value != nil {
+			fields[name] = *value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is wrapped by lookups of a resource that doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized rejects a request without valid credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrConflict rejects a write to a resource that changed since it was
+	// read; the write can be retried.
+	ErrConflict = errors.New("conflict")
+)
+
+// ValidationError reports a request parameter the service rejected, so
+// handlers can answer 400 rather than blaming the backend.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
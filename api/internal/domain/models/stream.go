@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// StreamCheckpoint is the type of stream events that carry no article and
+// only move the client's resume position past events it was not sent.
+const StreamCheckpoint = "checkpoint"
+
+// ArticleEvent announces that the worker stored an article, as created or
+// updated.
+type ArticleEvent struct {
+	ID            int      `json:"id"`
+	ExternalID    int      `json:"externalID"`
+	Revision      int      `json:"revision"`
+	ChangedFields []string `json:"changedFields"`
+	Source        string   `json:"source"`
+	Tags          []Tag    `json:"tags"`
+}
+
+// StreamEvent is an article event as sent to live clients. Its sequence is
+// the JetStream stream sequence, which clients resume from.
+type StreamEvent struct {
+	Sequence   uint64        `json:"id"`
+	Type       string        `json:"type"`
+	ProducedAt time.Time     `json:"producedAt"`
+	Article    *ArticleEvent `json:"article,omitempty"`
+}
+
+// StreamFilter selects the events a live client receives: articles with any
+// of the tags, from the source. Tag labels match regardless of case. Empty
+// fields don't filter.
+type StreamFilter struct {
+	TagIDs    []int    `json:"tagIDs,omitempty" bson:"tagIDs,omitempty"`
+	TagLabels []string `json:"tagLabels,omitempty" bson:"tagLabels,omitempty"`
+	Source    string   `json:"source,omitempty" bson:"source,omitempty"`
+}
+
+func (f StreamFilter) Matches(article ArticleEvent) bool {
+	if f.Source != "" && article.Source != f.Source {
+		return false
+	}
+	if len(f.TagIDs) == 0 && len(f.TagLabels) == 0 {
+		return true
+	}
+
+	for _, tag := range article.Tags {
+		for _, id := range f.TagIDs {
+			if tag.ID == id {
+				return true
+			}
+		}
+		for _, label := range f.TagLabels {
+			if strings.EqualFold(tag.Label, label) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Webhook is a partner endpoint that is POSTed the article events matching
+// its filter. The secret signs the deliveries and is never returned.
+type Webhook struct {
+	ID                  string       `json:"id" bson:"_id"`
+	URL                 string       `json:"url" bson:"url"`
+	Filter              StreamFilter `json:"filter" bson:"filter"`
+	Secret              string       `json:"-" bson:"secret"`
+	Active              bool         `json:"active" bson:"active"`
+	ConsecutiveFailures int          `json:"consecutiveFailures" bson:"consecutiveFailures"`
+	DisabledAt          *time.Time   `json:"disabledAt,omitempty" bson:"disabledAt,omitempty"`
+	DisabledReason      string       `json:"disabledReason,omitempty" bson:"disabledReason,omitempty"`
+	CreatedAt           time.Time    `json:"createdAt" bson:"createdAt"`
+}
+
+// WebhookRequest registers a webhook.
+type WebhookRequest struct {
+	URL    string       `json:"url"`
+	Filter StreamFilter `json:"filter"`
+	Secret string       `json:"secret"`
+}
+
+// WebhookDelivery is an article event owed to a webhook, with every attempt
+// made to deliver it. Its ID is derived from the webhook and the event
+// sequence, so an event redelivered by NATS is queued once.
+type WebhookDelivery struct {
+	ID            string            `json:"id" bson:"_id"`
+	WebhookID     string            `json:"webhookID" bson:"webhookID"`
+	Event         StreamEvent       `json:"event" bson:"event"`
+	Status        DeliveryStatus    `json:"status" bson:"status"`
+	Attempts      []DeliveryAttempt `json:"attempts" bson:"attempts"`
+	NextAttemptAt *time.Time        `json:"nextAttemptAt,omitempty" bson:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt" bson:"createdAt"`
+	DeliveredAt   *time.Time        `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
+
+type DeliveryAttempt struct {
+	At         time.Time `json:"at" bson:"at"`
+	StatusCode int       `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64     `json:"durationMs" bson:"durationMs"`
+}
+
+// PaginatedDeliveries is a page of a webhook's delivery log, newest first.
+type PaginatedDeliveries struct {
+	PageInfo PageInfo          `json:"pageInfo"`
+	Content  []WebhookDelivery `json:"content"`
+}
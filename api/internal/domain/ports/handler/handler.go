@@ -6,4 +6,32 @@ type IHandler interface {
 	GetArticleByID(w http.ResponseWriter, r *http.Request)
 	GetArticleByExternalID(w http.ResponseWriter, r *http.Request)
 	GetPaginatedArticles(w http.ResponseWriter, r *http.Request)
+	SearchArticles(w http.ResponseWriter, r *http.Request)
+	GetArticleRevisions(w http.ResponseWriter, r *http.Request)
+	GetArticleRevision(w http.ResponseWriter, r *http.Request)
+	CreateArticle(w http.ResponseWriter, r *http.Request)
+	UpdateArticle(w http.ResponseWriter, r *http.Request)
+	DeleteArticle(w http.ResponseWriter, r *http.Request)
+}
+
+type IStreamHandler interface {
+	StreamArticles(w http.ResponseWriter, r *http.Request)
+	StreamArticlesWS(w http.ResponseWriter, r *http.Request)
+	Close()
+}
+
+type IWebhookHandler interface {
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	ListWebhooks(w http.ResponseWriter, r *http.Request)
+	GetWebhook(w http.ResponseWriter, r *http.Request)
+	DeleteWebhook(w http.ResponseWriter, r *http.Request)
+	EnableWebhook(w http.ResponseWriter, r *http.Request)
+	GetWebhookDeliveries(w http.ResponseWriter, r *http.Request)
+}
+
+type IAPIKeyHandler interface {
+	IssueKey(w http.ResponseWriter, r *http.Request)
+	ListKeys(w http.ResponseWriter, r *http.Request)
+	RotateKey(w http.ResponseWriter, r *http.Request)
+	RevokeKey(w http.ResponseWriter, r *http.Request)
 }
@@ -0,0 +1,22 @@
+package msgqueue
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IArticleEvents interface {
+	// Subscribe hands new article events to handler until ctx is done.
+	Subscribe(ctx context.Context, handler func(event models.StreamEvent)) error
+	// Replay hands the stored article events following afterSequence to
+	// handler, up to the last one stored when it was called.
+	Replay(ctx context.Context, afterSequence uint64, handler func(event models.StreamEvent) error) error
+}
+
+type IArticleEventConsumer interface {
+	// Consume hands every article event to handler once it's acknowledged
+	// by a durable consumer, so none is missed across restarts; events the
+	// handler fails are redelivered.
+	Consume(ctx context.Context, handler func(event models.StreamEvent) error) error
+}
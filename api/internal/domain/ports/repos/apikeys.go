@@ -0,0 +1,20 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IAPIKeysRepos interface {
+	CreateKey(ctx context.Context, key *models.APIKey) error
+	GetKey(ctx context.Context, id string) (*models.APIKey, error)
+	GetKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RotateKey(ctx context.Context, id string, hash string, prefix string, at time.Time) error
+	RevokeKey(ctx context.Context, id string, at time.Time) error
+	// IncrementUsage counts a request against the key's quota for the day
+	// and returns the day's count so far.
+	IncrementUsage(ctx context.Context, id string, day time.Time) (int, error)
+}
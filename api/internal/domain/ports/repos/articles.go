@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 )
@@ -9,5 +10,13 @@ import (
 type IArticlesRepos interface {
 	GetByID(ctx context.Context, id int) (*models.Article, error)
 	GetByExternalID(ctx context.Context, externalID int) (*models.Article, error)
-	GetPaginatedArticles(ctx context.Context, page, pageSize int) (*models.PaginatedArticles, error)
+	GetPaginatedArticles(ctx context.Context, page, pageSize int, filter models.ArticleFilter) (*models.PaginatedArticles, error)
+	GetArticlesAfter(ctx context.Context, after *models.ArticleCursor, limit int, filter models.ArticleFilter) ([]models.Article, error)
+	CountArticles(ctx context.Context, filter models.ArticleFilter) (int, error)
+	SearchArticles(ctx context.Context, query string, page, pageSize int) (*models.SearchResults, error)
+	GetRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error)
+	GetRevision(ctx context.Context, id, revision int) (*models.ArticleRevision, error)
+	CreateArticle(ctx context.Context, article models.Article, editor models.Editor, requestKey string, now time.Time) (*models.Article, error)
+	EditArticle(ctx context.Context, id int, patch models.ArticlePatch, editor models.Editor, now time.Time) (*models.Article, error)
+	HideArticle(ctx context.Context, id int, editor models.Editor, now time.Time) error
 }
@@ -0,0 +1,30 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IWebhooksRepos interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, activeOnly bool) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	EnableWebhook(ctx context.Context, id string) error
+	DisableWebhook(ctx context.Context, id string, reason string, at time.Time) error
+	// RecordWebhookResult resets the webhook's consecutive failures after a
+	// success, or counts one more failure, and returns the new count.
+	RecordWebhookResult(ctx context.Context, id string, success bool) (int, error)
+
+	// InsertDeliveries queues deliveries, skipping those already queued.
+	InsertDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	// ClaimDueDelivery returns a pending delivery due by now and postpones it
+	// by lease, so no other dispatcher picks it up meanwhile. It returns nil
+	// when none is due.
+	ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, id string, attempt models.DeliveryAttempt,
+		status models.DeliveryStatus, nextAttemptAt *time.Time) error
+	GetDeliveries(ctx context.Context, webhookID string, page, pageSize int) (*models.PaginatedDeliveries, error)
+}
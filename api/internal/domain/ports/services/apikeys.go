@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IAPIKeysService interface {
+	IssueKey(ctx context.Context, request models.APIKeyRequest) (*models.IssuedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RotateKey(ctx context.Context, id string) (*models.IssuedAPIKey, error)
+	RevokeKey(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, plain string) (*models.APIKey, error)
+	Consume(ctx context.Context, key *models.APIKey) (models.RateLimitStatus, error)
+}
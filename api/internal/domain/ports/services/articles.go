@@ -9,5 +9,13 @@ import (
 type IArticlesService interface {
 	GetArticleByID(ctx context.Context, id int) (*models.Article, error)
 	GetArticleByExternalID(ctx context.Context, externalID int) (*models.Article, error)
-	GetPaginatedArticles(ctx context.Context, page, pageSize int) (*models.PaginatedArticles, error)
+	GetPaginatedArticles(ctx context.Context, page, pageSize int, filter models.ArticleFilter) (*models.PaginatedArticles, error)
+	GetArticlesByCursor(ctx context.Context, cursor string, pageSize int, includeTotal bool,
+		filter models.ArticleFilter) (*models.PaginatedArticles, error)
+	SearchArticles(ctx context.Context, query string, page, pageSize int) (*models.SearchResults, error)
+	GetArticleRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error)
+	GetArticleRevision(ctx context.Context, id, revision int) (*models.ArticleRevision, error)
+	CreateArticle(ctx context.Context, request models.ArticleRequest) (*models.Article, error)
+	UpdateArticle(ctx context.Context, id int, patch models.ArticlePatch) (*models.Article, error)
+	HideArticle(ctx context.Context, id int) error
 }
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IAuthService interface {
+	Authenticate(ctx context.Context, token string) (*models.Principal, error)
+	Consume(ctx context.Context, principal *models.Principal) models.RateLimitStatus
+}
@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IStreamService interface {
+	Subscribe(ctx context.Context, filter models.StreamFilter, lastEventID uint64) (<-chan models.StreamEvent, error)
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+type IWebhooksService interface {
+	CreateWebhook(ctx context.Context, request models.WebhookRequest) (*models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	EnableWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	GetDeliveries(ctx context.Context, id string, page, pageSize int) (*models.PaginatedDeliveries, error)
+}
@@ -0,0 +1,251 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/repos"
+)
+
+const (
+	keyPrefix    = "ss_"
+	prefixLength = len(keyPrefix) + 6
+
+	// BootstrapKeyID identifies the admin key set in the configuration, which
+	// isn't stored and has no limits.
+	BootstrapKeyID = "bootstrap"
+)
+
+type Config struct {
+	DefaultRateLimit  models.RateLimit
+	DefaultDailyQuota int
+	// BootstrapAdminKey, when set, is an admin key for issuing the first
+	// keys.
+	BootstrapAdminKey string
+	// CacheTTL is how long a looked up key is trusted, and so how long a key
+	// revoked on another instance keeps working here.
+	CacheTTL time.Duration
+}
+
+type cachedKey struct {
+	key     models.APIKey
+	expires time.Time
+}
+
+type APIKeyService struct {
+	repo          repos.IAPIKeysRepos
+	limiter       *Limiter
+	cfg           Config
+	bootstrapHash string
+	now           func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+func NewAPIKeyService(repo repos.IAPIKeysRepos, cfg Config) *APIKeyService {
+	s := &APIKeyService{
+		repo:    repo,
+		limiter: NewLimiter(),
+		cfg:     cfg,
+		now:     time.Now,
+		cache:   make(map[string]cachedKey),
+	}
+	if cfg.BootstrapAdminKey != "" {
+		s.bootstrapHash = hashKey(cfg.BootstrapAdminKey)
+	}
+	return s
+}
+
+// WithClock replaces the service's clock, for tests.
+func (s *APIKeyService) WithClock(now func() time.Time) *APIKeyService {
+	s.now = now
+	return s
+}
+
+func (s *APIKeyService) IssueKey(ctx context.Context, request models.APIKeyRequest) (*models.IssuedAPIKey, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, models.ValidationError{Field: "name", Reason: "required"}
+	}
+	if request.RateLimit.PerSecond < 0 || request.RateLimit.Burst < 0 {
+		return nil, models.ValidationError{Field: "rateLimit", Reason: "must not be negative"}
+	}
+	if request.RateLimit.PerSecond == 0 {
+		request.RateLimit.PerSecond = s.cfg.DefaultRateLimit.PerSecond
+	}
+	if request.RateLimit.Burst == 0 {
+		request.RateLimit.Burst = s.cfg.DefaultRateLimit.Burst
+	}
+	switch {
+	case request.DailyQuota == 0:
+		request.DailyQuota = s.cfg.DefaultDailyQuota
+	case request.DailyQuota < 0:
+		request.DailyQuota = 0
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := models.APIKey{
+		ID:         id,
+		Name:       request.Name,
+		Prefix:     plain[:prefixLength],
+		Hash:       hashKey(plain),
+		Admin:      request.Admin,
+		RateLimit:  request.RateLimit,
+		DailyQuota: request.DailyQuota,
+		CreatedAt:  s.now().UTC(),
+	}
+	if err := s.repo.CreateKey(ctx, &key); err != nil {
+		return nil, err
+	}
+	return &models.IssuedAPIKey{APIKey: key, Key: plain}, nil
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.ListKeys(ctx)
+}
+
+// RotateKey replaces a key's secret, keeping its ID, limits and usage. The
+// old key stops working at once.
+func (s *APIKeyService) RotateKey(ctx context.Context, id string) (*models.IssuedAPIKey, error) {
+	plain, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RotateKey(ctx, id, hashKey(plain), plain[:prefixLength], s.now().UTC()); err != nil {
+		return nil, err
+	}
+	s.forget(id)
+
+	key, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.IssuedAPIKey{APIKey: *key, Key: plain}, nil
+}
+
+func (s *APIKeyService) RevokeKey(ctx context.Context, id string) error {
+	if err := s.repo.RevokeKey(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+	s.forget(id)
+	return nil
+}
+
+// Authenticate returns the active key matching the plain key, or
+// ErrUnauthorized.
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*models.APIKey, error) {
+	if plain == "" {
+		return nil, models.ErrUnauthorized
+	}
+	hash := hashKey(plain)
+	if s.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.bootstrapHash)) == 1 {
+		return &models.APIKey{ID: BootstrapKeyID, Name: BootstrapKeyID, Admin: true}, nil
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[hash]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		key := cached.key
+		return &key, nil
+	}
+
+	key, err := s.repo.GetKeyByHash(ctx, hash)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, models.ErrUnauthorized
+	}
+
+	s.mu.Lock()
+	s.cache[hash] = cachedKey{key: *key, expires: now.Add(s.cfg.CacheTTL)}
+	s.mu.Unlock()
+	return key, nil
+}
+
+// Consume counts a request against the key's rate limit and daily quota.
+// Requests the rate limit turns away don't count against the quota.
+func (s *APIKeyService) Consume(ctx context.Context, key *models.APIKey) (models.RateLimitStatus, error) {
+	status := models.RateLimitStatus{Allowed: true}
+	now := s.now()
+
+	if key.RateLimit.PerSecond > 0 && key.RateLimit.Burst > 0 {
+		status.Limit = key.RateLimit.Burst
+		status.Allowed, status.Remaining, status.Reset = s.limiter.Take(key.ID, key.RateLimit, now)
+		if !status.Allowed {
+			return status, nil
+		}
+	}
+
+	if key.DailyQuota > 0 {
+		count, err := s.repo.IncrementUsage(ctx, key.ID, now)
+		if err != nil {
+			return status, err
+		}
+		status.QuotaLimit = key.DailyQuota
+		status.QuotaRemaining = max(key.DailyQuota-count, 0)
+		status.QuotaReset = now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if count > key.DailyQuota {
+			status.Allowed = false
+			status.Reset = status.QuotaReset.Sub(now)
+		}
+	}
+	return status, nil
+}
+
+func (s *APIKeyService) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, cached := range s.cache {
+		if cached.key.ID == id {
+			delete(s.cache, hash)
+		}
+	}
+}
+
+func newKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashKey is unsalted: keys are random, so a fast hash is enough and lets
+// keys be looked up by it.
+func hashKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,248 @@
+package apikeys_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/apikeys"
+	repomocks "github.com/ronnyp07/SportStream/api/tests/mocks/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var serviceConfig = apikeys.Config{
+	DefaultRateLimit:  models.RateLimit{PerSecond: 5, Burst: 20},
+	DefaultDailyQuota: 1000,
+	BootstrapAdminKey: "bootstrap-secret",
+	CacheTTL:          time.Minute,
+}
+
+func TestAPIKeyService_IssueKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		request       models.APIKeyRequest
+		expectedLimit models.RateLimit
+		expectedQuota int
+		expectedError string
+	}{
+		{
+			name:          "success - configured defaults",
+			request:       models.APIKeyRequest{Name: "partner"},
+			expectedLimit: models.RateLimit{PerSecond: 5, Burst: 20},
+			expectedQuota: 1000,
+		},
+		{
+			name: "success - own limits and unlimited quota",
+			request: models.APIKeyRequest{
+				Name:       "partner",
+				RateLimit:  models.RateLimit{PerSecond: 50, Burst: 100},
+				DailyQuota: -1,
+			},
+			expectedLimit: models.RateLimit{PerSecond: 50, Burst: 100},
+		},
+		{
+			name:          "error - missing name",
+			request:       models.APIKeyRequest{Name: " "},
+			expectedError: "invalid name",
+		},
+		{
+			name:          "error - negative rate",
+			request:       models.APIKeyRequest{Name: "partner", RateLimit: models.RateLimit{PerSecond: -1}},
+			expectedError: "invalid rateLimit",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIAPIKeysRepos(ctrl)
+			var stored *models.APIKey
+			if tt.expectedError == "" {
+				mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key *models.APIKey) error {
+						stored = key
+						return nil
+					})
+			}
+
+			issued, err := apikeys.NewAPIKeyService(mockRepo, serviceConfig).IssueKey(context.Background(), tt.request)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(issued.Key, "ss_"))
+			assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+			assert.NotContains(t, stored.Hash, issued.Key)
+			assert.Len(t, stored.Hash, 64)
+			assert.Equal(t, tt.expectedLimit, stored.RateLimit)
+			assert.Equal(t, tt.expectedQuota, stored.DailyQuota)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	revokedAt := time.Now()
+	tests := []struct {
+		name          string
+		key           string
+		mockSetup     func(*repomocks.MockIAPIKeysRepos)
+		expectedID    string
+		expectedError error
+	}{
+		{
+			name:          "missing key",
+			expectedError: models.ErrUnauthorized,
+		},
+		{
+			name:       "bootstrap admin key",
+			key:        "bootstrap-secret",
+			expectedID: apikeys.BootstrapKeyID,
+		},
+		{
+			name: "active key, looked up once",
+			key:  "ss_active",
+			mockSetup: func(m *repomocks.MockIAPIKeysRepos) {
+				m.EXPECT().GetKeyByHash(gomock.Any(), gomock.Any()).
+					Return(&models.APIKey{ID: "k1"}, nil).Times(1)
+			},
+			expectedID: "k1",
+		},
+		{
+			name: "unknown key",
+			key:  "ss_unknown",
+			mockSetup: func(m *repomocks.MockIAPIKeysRepos) {
+				m.EXPECT().GetKeyByHash(gomock.Any(), gomock.Any()).
+					Return(nil, errors.Wrap(models.ErrNotFound, "api key")).Times(2)
+			},
+			expectedError: models.ErrUnauthorized,
+		},
+		{
+			name: "revoked key",
+			key:  "ss_revoked",
+			mockSetup: func(m *repomocks.MockIAPIKeysRepos) {
+				m.EXPECT().GetKeyByHash(gomock.Any(), gomock.Any()).
+					Return(&models.APIKey{ID: "k2", RevokedAt: &revokedAt}, nil).Times(2)
+			},
+			expectedError: models.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIAPIKeysRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+			service := apikeys.NewAPIKeyService(mockRepo, serviceConfig)
+
+			// Twice, so the second call is answered from the cache when the
+			// key was valid.
+			for i := 0; i < 2; i++ {
+				key, err := service.Authenticate(context.Background(), tt.key)
+				if tt.expectedError != nil {
+					assert.ErrorIs(t, err, tt.expectedError)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedID, key.ID)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Consume(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	key := &models.APIKey{ID: "k1", RateLimit: models.RateLimit{PerSecond: 1, Burst: 2}, DailyQuota: 100}
+
+	tests := []struct {
+		name              string
+		requests          int
+		usage             int
+		expectedAllowed   bool
+		expectedRemaining int
+		expectedReset     time.Duration
+		expectedQuota     int
+	}{
+		{
+			name:              "within limits",
+			requests:          1,
+			usage:             40,
+			expectedAllowed:   true,
+			expectedRemaining: 1,
+			expectedReset:     time.Second,
+			expectedQuota:     60,
+		},
+		{
+			name:            "rate limited",
+			requests:        3,
+			usage:           40,
+			expectedAllowed: false,
+			expectedReset:   time.Second,
+			expectedQuota:   0,
+		},
+		{
+			name:              "quota exhausted until midnight",
+			requests:          1,
+			usage:             101,
+			expectedAllowed:   false,
+			expectedRemaining: 1,
+			expectedReset:     6 * time.Hour,
+			expectedQuota:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIAPIKeysRepos(ctrl)
+			// Requests turned away by the rate limit don't reach the quota.
+			mockRepo.EXPECT().IncrementUsage(gomock.Any(), "k1", now).
+				Return(tt.usage, nil).Times(min(tt.requests, key.RateLimit.Burst))
+
+			service := apikeys.NewAPIKeyService(mockRepo, serviceConfig).
+				WithClock(func() time.Time { return now })
+
+			var status models.RateLimitStatus
+			for i := 0; i < tt.requests; i++ {
+				var err error
+				status, err = service.Consume(context.Background(), key)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedAllowed, status.Allowed)
+			assert.Equal(t, tt.expectedRemaining, status.Remaining)
+			assert.Equal(t, tt.expectedReset, status.Reset)
+			assert.Equal(t, tt.expectedQuota, status.QuotaRemaining)
+		})
+	}
+}
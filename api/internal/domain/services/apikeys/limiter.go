@@ -0,0 +1,71 @@
+package apikeys
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+const (
+	pruneAbove = 1024
+	idleAfter  = 10 * time.Minute
+)
+
+type bucket struct {
+	limit  models.RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a token bucket per key in memory, so each api instance
+// enforces the rate limit on its own share of the traffic.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// Take spends a token of the key's bucket at now. It returns whether one was
+// left, how many remain and how long until the next one.
+func (l *Limiter) Take(id string, limit models.RateLimit, now time.Time) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[id]
+	if !ok || b.limit != limit {
+		// A new key, or its limits changed: start from a full bucket.
+		if len(l.buckets) >= pruneAbove {
+			l.prune(now)
+		}
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[id] = b
+	}
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.PerSecond)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false, 0, untilTokens(1-b.tokens, limit.PerSecond)
+	}
+	b.tokens--
+	return true, int(b.tokens), untilTokens(1-math.Mod(b.tokens, 1), limit.PerSecond)
+}
+
+func (l *Limiter) prune(now time.Time) {
+	for id, b := range l.buckets {
+		if now.Sub(b.last) > idleAfter {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func untilTokens(tokens float64, perSecond float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / perSecond * float64(time.Second)))
+}
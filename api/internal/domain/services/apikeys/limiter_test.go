@@ -0,0 +1,48 @@
+package apikeys_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/apikeys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Take(t *testing.T) {
+	t.Parallel()
+
+	limiter := apikeys.NewLimiter()
+	limit := models.RateLimit{PerSecond: 2, Burst: 3}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// The bucket starts full.
+	for remaining := 2; remaining >= 0; remaining-- {
+		allowed, left, _ := limiter.Take("a", limit, start)
+		assert.True(t, allowed)
+		assert.Equal(t, remaining, left)
+	}
+
+	allowed, left, reset := limiter.Take("a", limit, start)
+	assert.False(t, allowed)
+	assert.Zero(t, left)
+	assert.Equal(t, 500*time.Millisecond, reset)
+
+	// Other keys have their own bucket.
+	allowed, _, _ = limiter.Take("b", limit, start)
+	assert.True(t, allowed)
+
+	// Refilled at PerSecond, up to Burst.
+	allowed, left, _ = limiter.Take("a", limit, start.Add(500*time.Millisecond))
+	assert.True(t, allowed)
+	assert.Zero(t, left)
+
+	allowed, left, _ = limiter.Take("a", limit, start.Add(time.Hour))
+	assert.True(t, allowed)
+	assert.Equal(t, 2, left)
+
+	// New limits start a new bucket.
+	allowed, left, _ = limiter.Take("a", models.RateLimit{PerSecond: 1, Burst: 10}, start.Add(time.Hour))
+	assert.True(t, allowed)
+	assert.Equal(t, 9, left)
+}
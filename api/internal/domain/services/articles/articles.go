@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 	"github.com/ronnyp07/SportStream/api/internal/domain/ports/repos"
@@ -32,12 +34,126 @@ func (s *ArticleService) GetArticleByExternalID(ctx context.Context, externalID
 	return s.repo.GetByExternalID(ctx, externalID)
 }
 
-func (s *ArticleService) GetPaginatedArticles(ctx context.Context, page, pageSize int) (*models.PaginatedArticles, error) {
+func (s *ArticleService) GetPaginatedArticles(ctx context.Context, page, pageSize int,
+	filter models.ArticleFilter) (*models.PaginatedArticles, error) {
 	if page < 1 {
 		page = 1
 	}
 	if pageSize < 1 || pageSize > 100 {
 		pageSize = 20
 	}
-	return s.repo.GetPaginatedArticles(ctx, page, pageSize)
+
+	filter, err := validateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetPaginatedArticles(ctx, page, pageSize, filter)
+}
+
+// GetArticlesByCursor returns the page of the listing following the cursor, an
+// empty cursor starting from the newest article. The total is only counted
+// when asked for, as it costs a scan of every matching article.
+func (s *ArticleService) GetArticlesByCursor(ctx context.Context, cursor string, pageSize int, includeTotal bool,
+	filter models.ArticleFilter) (*models.PaginatedArticles, error) {
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter, err = validateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one article more than the page to know whether another follows.
+	articles, err := s.repo.GetArticlesAfter(ctx, after, pageSize+1, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PaginatedArticles{Content: articles}
+	if len(articles) > pageSize {
+		result.Content = articles[:pageSize]
+		last := result.Content[pageSize-1]
+		result.NextCursor = encodeCursor(models.ArticleCursor{PublishedAt: last.PublishedAt, ID: last.ID})
+	}
+
+	if includeTotal {
+		total, err := s.repo.CountArticles(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+func validateFilter(filter models.ArticleFilter) (models.ArticleFilter, error) {
+	switch filter.TagMatch {
+	case "":
+		filter.TagMatch = models.TagMatchAny
+	case models.TagMatchAny, models.TagMatchAll:
+	default:
+		return filter, models.ValidationError{Field: "tagMatch", Reason: "must be any or all"}
+	}
+	for _, id := range filter.TagIDs {
+		if id <= 0 {
+			return filter, models.ValidationError{Field: "tag", Reason: fmt.Sprintf("tag ID %d is not positive", id)}
+		}
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return filter, models.ValidationError{Field: "to", Reason: "is before from"}
+	}
+	return filter, nil
+}
+
+// SearchArticles runs a full-text search over title, summary, description and
+// body, and highlights where each result matched.
+func (s *ArticleService) SearchArticles(ctx context.Context, query string, page, pageSize int) (*models.SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, models.ValidationError{Field: "q", Reason: "search query is required"}
+	}
+	if len(query) > maxQueryLength {
+		return nil, models.ValidationError{Field: "q", Reason: fmt.Sprintf("longer than %d characters", maxQueryLength)}
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	results, err := s.repo.SearchArticles(ctx, query, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query)
+	for i := range results.Content {
+		results.Content[i].Highlights = highlights(results.Content[i].Article, terms)
+	}
+	return results, nil
+}
+
+func (s *ArticleService) GetArticleRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error) {
+	if id <= 0 {
+		return nil, models.ValidationError{Field: "article ID", Reason: "must be positive"}
+	}
+	return s.repo.GetRevisions(ctx, id)
+}
+
+func (s *ArticleService) GetArticleRevision(ctx context.Context, id, revision int) (*models.ArticleRevision, error) {
+	if id <= 0 {
+		return nil, models.ValidationError{Field: "article ID", Reason: "must be positive"}
+	}
+	if revision <= 0 {
+		return nil, models.ValidationError{Field: "revision", Reason: "must be positive"}
+	}
+	return s.repo.GetRevision(ctx, id, revision)
 }
@@ -2,6 +2,8 @@ package services_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -158,7 +160,7 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 
 	// Test data
 	mockArticles := &models.PaginatedArticles{
-		PageInfo: models.PageInfo{
+		PageInfo: &models.PageInfo{
 			Page:       1,
 			NumPages:   1,
 			PageSize:   20,
@@ -169,11 +171,16 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 		},
 	}
 
+	anyTagFilter := models.ArticleFilter{TagMatch: models.TagMatchAny}
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
 	// Test cases
 	tests := []struct {
 		name          string
 		page          int
 		pageSize      int
+		filter        models.ArticleFilter
 		mockSetup     func(*repomocks.MockIArticlesRepos)
 		expectedPage  int
 		expectedSize  int
@@ -184,7 +191,7 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 			page:     0,
 			pageSize: 0,
 			mockSetup: func(m *repomocks.MockIArticlesRepos) {
-				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20).
+				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20, anyTagFilter).
 					Return(mockArticles, nil)
 			},
 			expectedPage: 1,
@@ -195,7 +202,7 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 			page:     1,
 			pageSize: 20,
 			mockSetup: func(m *repomocks.MockIArticlesRepos) {
-				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20).
+				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20, anyTagFilter).
 					Return(nil, assert.AnError)
 			},
 			expectedError: assert.AnError.Error(),
@@ -207,10 +214,42 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 			expectedPage: 1,
 			expectedSize: 20,
 			mockSetup: func(m *repomocks.MockIArticlesRepos) {
-				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20).
+				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20, anyTagFilter).
 					Return(mockArticles, nil)
 			},
 		},
+		{
+			name:     "success - filters passed to the repository",
+			page:     1,
+			pageSize: 20,
+			filter: models.ArticleFilter{
+				TagIDs: []int{4}, TagLabels: []string{"Ashes"}, TagMatch: models.TagMatchAll,
+				From: &from, To: &to, MediaType: "video", Source: "poller",
+			},
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20, models.ArticleFilter{
+					TagIDs: []int{4}, TagLabels: []string{"Ashes"}, TagMatch: models.TagMatchAll,
+					From: &from, To: &to, MediaType: "video", Source: "poller",
+				}).Return(mockArticles, nil)
+			},
+			expectedPage: 1,
+			expectedSize: 20,
+		},
+		{
+			name:          "error - unknown tag match",
+			filter:        models.ArticleFilter{TagLabels: []string{"Ashes"}, TagMatch: "some"},
+			expectedError: "invalid tagMatch",
+		},
+		{
+			name:          "error - non-positive tag ID",
+			filter:        models.ArticleFilter{TagIDs: []int{-3}},
+			expectedError: "invalid tag",
+		},
+		{
+			name:          "error - to before from",
+			filter:        models.ArticleFilter{From: &to, To: &from},
+			expectedError: "invalid to",
+		},
 		{
 			name:         "invalid - page size too large",
 			page:         1,
@@ -218,7 +257,7 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 			expectedPage: 1,
 			expectedSize: 20,
 			mockSetup: func(m *repomocks.MockIArticlesRepos) {
-				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20).
+				m.EXPECT().GetPaginatedArticles(gomock.Any(), 1, 20, anyTagFilter).
 					Return(mockArticles, nil)
 			},
 		},
@@ -244,7 +283,7 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			result, err := service.GetPaginatedArticles(ctx, tt.page, tt.pageSize)
+			result, err := service.GetPaginatedArticles(ctx, tt.page, tt.pageSize, tt.filter)
 
 			// Verify
 			if tt.expectedError != "" {
@@ -261,3 +300,250 @@ func TestArticleService_GetPaginatedArticles(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleService_GetArticleRevision(t *testing.T) {
+	t.Parallel()
+
+	// Test cases
+	tests := []struct {
+		name          string
+		id            int
+		revision      int
+		mockSetup     func(*repomocks.MockIArticlesRepos)
+		expectedError string
+	}{
+		{
+			name:     "success - valid revision",
+			id:       123,
+			revision: 2,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().GetRevision(gomock.Any(), 123, 2).
+					Return(&models.ArticleRevision{ArticleID: 123, Revision: 2}, nil)
+			},
+		},
+		{
+			name:          "error - invalid ID",
+			id:            0,
+			revision:      2,
+			expectedError: "invalid article ID",
+		},
+		{
+			name:          "error - invalid revision",
+			id:            123,
+			revision:      0,
+			expectedError: "invalid revision",
+		},
+		{
+			name:     "error - repository error",
+			id:       123,
+			revision: 2,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().GetRevision(gomock.Any(), 123, 2).
+					Return(nil, assert.AnError)
+			},
+			expectedError: assert.AnError.Error(),
+		},
+		{
+			name:     "error - revision not found",
+			id:       123,
+			revision: 9,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().GetRevision(gomock.Any(), 123, 9).
+					Return(nil, fmt.Errorf("revision: %w", models.ErrNotFound))
+			},
+			expectedError: models.ErrNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			service := services.NewArticleService(mockRepo)
+
+			// Execute
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			revision, err := service.GetArticleRevision(ctx, tt.id, tt.revision)
+
+			// Verify
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, revision)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.id, revision.ArticleID)
+				assert.Equal(t, tt.revision, revision.Revision)
+			}
+		})
+	}
+}
+
+func TestArticleService_SearchArticles(t *testing.T) {
+	t.Parallel()
+
+	article := models.Article{
+		ID:      7,
+		Title:   "England bowled out before lunch",
+		Summary: "A collapse at Lord's",
+		Body:    "<p>The seamers were <b>Bowling</b> full &amp; straight all morning.</p>",
+	}
+
+	tests := []struct {
+		name               string
+		query              string
+		page               int
+		pageSize           int
+		mockSetup          func(*repomocks.MockIArticlesRepos)
+		expectedHighlights []models.Highlight
+		expectedError      string
+	}{
+		{
+			name:     "success - highlights matching fields",
+			query:    " bowl -rain ",
+			page:     0,
+			pageSize: 500,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().SearchArticles(gomock.Any(), "bowl -rain", 1, 20).
+					Return(&models.SearchResults{Content: []models.SearchResult{{Article: article, Score: 1.5}}}, nil)
+			},
+			expectedHighlights: []models.Highlight{
+				{Field: "title", Snippet: "England <mark>bowled</mark> out before lunch"},
+				{Field: "body", Snippet: "The seamers were <mark>Bowling</mark> full &amp; straight all morning."},
+			},
+		},
+		{
+			name:     "success - markup in highlights is escaped",
+			query:    "script",
+			page:     1,
+			pageSize: 20,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				hostile := models.Article{
+					ID:    8,
+					Title: `Script <img src=x onerror="alert(1)"> kiddies`,
+					Body:  "<p>Never run &lt;script&gt;alert(1)&lt;/script&gt; from a scorecard.</p>",
+				}
+				m.EXPECT().SearchArticles(gomock.Any(), "script", 1, 20).
+					Return(&models.SearchResults{Content: []models.SearchResult{{Article: hostile, Score: 1}}}, nil)
+			},
+			expectedHighlights: []models.Highlight{
+				{Field: "title", Snippet: "<mark>Script</mark> &lt;img src=x onerror=&#34;alert(1)&#34;&gt; kiddies"},
+				{Field: "body", Snippet: "Never run &lt;<mark>script</mark>&gt;alert(1)&lt;/<mark>script</mark>&gt; from a scorecard."},
+			},
+		},
+		{
+			name:          "error - empty query",
+			query:         "   ",
+			expectedError: "invalid q",
+		},
+		{
+			name:          "error - query too long",
+			query:         strings.Repeat("a", 257),
+			expectedError: "invalid q",
+		},
+		{
+			name:     "error - repository error",
+			query:    "ashes",
+			page:     2,
+			pageSize: 10,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().SearchArticles(gomock.Any(), "ashes", 2, 10).
+					Return(nil, assert.AnError)
+			},
+			expectedError: assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Setup
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			service := services.NewArticleService(mockRepo)
+
+			// Execute
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			results, err := service.SearchArticles(ctx, tt.query, tt.page, tt.pageSize)
+
+			// Verify
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, results)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, results.Content, 1)
+				assert.Equal(t, tt.expectedHighlights, results.Content[0].Highlights)
+			}
+		})
+	}
+}
+
+func TestArticleService_GetArticlesByCursor(t *testing.T) {
+	t.Parallel()
+
+	published := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	page := []models.Article{{ID: 3, PublishedAt: &published}, {ID: 2, PublishedAt: &published}, {ID: 1}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+	service := services.NewArticleService(mockRepo)
+	filter := models.ArticleFilter{TagMatch: models.TagMatchAny}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first page reads one article ahead and points past its last article.
+	mockRepo.EXPECT().GetArticlesAfter(gomock.Any(), nil, 3, filter).Return(page, nil)
+
+	first, err := service.GetArticlesByCursor(ctx, "", 2, false, models.ArticleFilter{})
+	require.NoError(t, err)
+	assert.Len(t, first.Content, 2)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Nil(t, first.Total)
+	assert.Nil(t, first.PageInfo)
+
+	// The next cursor resumes after (publishedAt, id) of that article.
+	mockRepo.EXPECT().GetArticlesAfter(gomock.Any(), &models.ArticleCursor{PublishedAt: &published, ID: 2}, 3, filter).
+		Return(page[2:], nil)
+	mockRepo.EXPECT().CountArticles(gomock.Any(), filter).Return(3, nil)
+
+	last, err := service.GetArticlesByCursor(ctx, first.NextCursor, 2, true, models.ArticleFilter{})
+	require.NoError(t, err)
+	assert.Len(t, last.Content, 1)
+	assert.Empty(t, last.NextCursor)
+	require.NotNil(t, last.Total)
+	assert.Equal(t, 3, *last.Total)
+
+	// Malformed cursors are rejected before querying.
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "eyJpIjowfQ"} {
+		_, err := service.GetArticlesByCursor(ctx, cursor, 2, false, models.ArticleFilter{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+// cursorToken is the listing position as serialized in a cursor. Clients
+// treat the cursor as opaque, so its format may change between releases.
+type cursorToken struct {
+	PublishedAt *time.Time `json:"p,omitempty"`
+	ID          int        `json:"i"`
+}
+
+func encodeCursor(cursor models.ArticleCursor) string {
+	data, _ := json.Marshal(cursorToken{PublishedAt: cursor.PublishedAt, ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor reads a cursor handed out as nextCursor. The empty cursor is the
+// start of the listing.
+func decodeCursor(cursor string) (*models.ArticleCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	invalid := models.ValidationError{Field: "cursor", Reason: "not a cursor returned by the listing"}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil || token.ID <= 0 {
+		return nil, invalid
+	}
+
+	return &models.ArticleCursor{PublishedAt: token.PublishedAt, ID: token.ID}, nil
+}
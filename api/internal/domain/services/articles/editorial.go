@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/auth"
+)
+
+const maxTitleLength = 300
+
+// CreateArticle stores a story written by the editor in the context. A retry
+// of the request returns the story it created.
+func (s *ArticleService) CreateArticle(ctx context.Context, request models.ArticleRequest) (*models.Article, error) {
+	editor, err := editorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := validTitle(request.Title)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range request.Tags {
+		if tag.ID <= 0 && strings.TrimSpace(tag.Label) == "" {
+			return nil, models.ValidationError{Field: "tags", Reason: "each tag needs an ID or a label"}
+		}
+	}
+
+	now := time.Now().UTC()
+	publishedAt := now
+	if request.PublishedAt != nil {
+		publishedAt = request.PublishedAt.UTC()
+	}
+
+	article := models.Article{
+		Title:       title,
+		Description: request.Description,
+		Summary:     request.Summary,
+		Body:        request.Body,
+		LeadMedia:   request.LeadMedia,
+		Tags:        request.Tags,
+		Source:      models.EditorialSource,
+		Date:        publishedAt.Format(time.RFC3339),
+		PublishedAt: &publishedAt,
+	}
+	if article.Tags == nil {
+		article.Tags = []models.Tag{}
+	}
+	return s.repo.CreateArticle(ctx, article, editor, requestKey(editor, request), now)
+}
+
+// requestKey identifies a create request of the editor. Without an idempotency
+// key the story itself identifies it, so resending it returns the first copy.
+func requestKey(editor models.Editor, request models.ArticleRequest) string {
+	key := request.IdempotencyKey
+	if key == "" {
+		story, _ := json.Marshal(request)
+		key = string(story)
+	}
+	sum := sha256.Sum256([]byte(editor.Subject + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// UpdateArticle overrides or reverts fields of an article for the editor in
+// the context. Overrides of feed articles outlive the feed's later versions.
+func (s *ArticleService) UpdateArticle(ctx context.Context, id int, patch models.ArticlePatch) (*models.Article, error) {
+	editor, err := editorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, models.ValidationError{Field: "id", Reason: "must be positive"}
+	}
+
+	fields := patch.Fields()
+	if len(fields) == 0 && len(patch.Revert) == 0 {
+		return nil, models.ValidationError{Field: "body", Reason: "no field to change"}
+	}
+	if patch.Title != nil {
+		title, err := validTitle(*patch.Title)
+		if err != nil {
+			return nil, err
+		}
+		patch.Title = &title
+	}
+	for _, name := range patch.Revert {
+		if !slices.Contains(models.EditableFields, name) {
+			return nil, models.ValidationError{Field: "revert",
+				Reason: fmt.Sprintf("%q is not one of %s", name, strings.Join(models.EditableFields, ", "))}
+		}
+		if _, ok := fields[name]; ok {
+			return nil, models.ValidationError{Field: "revert", Reason: fmt.Sprintf("%q is also set", name)}
+		}
+	}
+
+	return s.repo.EditArticle(ctx, id, patch, editor, time.Now().UTC())
+}
+
+// HideArticle removes an article from every read, keeping it stored.
+func (s *ArticleService) HideArticle(ctx context.Context, id int) error {
+	editor, err := editorFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if id <= 0 {
+		return models.ValidationError{Field: "id", Reason: "must be positive"}
+	}
+	return s.repo.HideArticle(ctx, id, editor, time.Now().UTC())
+}
+
+func validTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", models.ValidationError{Field: "title", Reason: "required"}
+	}
+	if len(title) > maxTitleLength {
+		return "", models.ValidationError{Field: "title", Reason: fmt.Sprintf("longer than %d characters", maxTitleLength)}
+	}
+	return title, nil
+}
+
+// editorFrom signs edits with the caller the auth middleware identified.
+func editorFrom(ctx context.Context) (models.Editor, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return models.Editor{}, models.ErrUnauthorized
+	}
+	return models.Editor{Subject: principal.Subject, Method: principal.Method}, nil
+}
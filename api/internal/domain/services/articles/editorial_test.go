@@ -0,0 +1,331 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	services "github.com/ronnyp07/SportStream/api/internal/domain/services/articles"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/auth"
+	repomocks "github.com/ronnyp07/SportStream/api/tests/mocks/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var editor = models.Editor{Subject: "ana@example.com", Method: models.AuthMethodJWT}
+
+func editorContext() context.Context {
+	return auth.NewContext(context.Background(), &models.Principal{
+		Subject: editor.Subject,
+		Role:    models.RoleEditor,
+		Method:  models.AuthMethodJWT,
+	})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestArticleService_CreateArticle(t *testing.T) {
+	t.Parallel()
+
+	publishedAt := time.Date(2024, 5, 1, 18, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		request       models.ArticleRequest
+		mockSetup     func(*repomocks.MockIArticlesRepos)
+		expectedError string
+	}{
+		{
+			name:    "success - stored as an editorial story",
+			ctx:     editorContext(),
+			request: models.ArticleRequest{Title: "  Final report ", Body: "Report", PublishedAt: &publishedAt},
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().CreateArticle(gomock.Any(), gomock.Any(), editor, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, article models.Article, _ models.Editor, _ string,
+						_ time.Time) (*models.Article, error) {
+						assert.Equal(t, "Final report", article.Title)
+						assert.Equal(t, models.EditorialSource, article.Source)
+						assert.Equal(t, publishedAt.UTC(), *article.PublishedAt)
+						assert.Equal(t, "2024-05-01T16:30:00Z", article.Date)
+						assert.Equal(t, []models.Tag{}, article.Tags)
+						article.ID = 7
+						return &article, nil
+					})
+			},
+		},
+		{
+			name:          "error - missing title",
+			ctx:           editorContext(),
+			request:       models.ArticleRequest{Title: " ", Body: "Report"},
+			expectedError: "invalid title",
+		},
+		{
+			name:          "error - title too long",
+			ctx:           editorContext(),
+			request:       models.ArticleRequest{Title: strings.Repeat("a", 301)},
+			expectedError: "invalid title",
+		},
+		{
+			name:          "error - empty tag",
+			ctx:           editorContext(),
+			request:       models.ArticleRequest{Title: "Final", Tags: []models.Tag{{}}},
+			expectedError: "invalid tags",
+		},
+		{
+			name:          "error - no editor",
+			ctx:           context.Background(),
+			request:       models.ArticleRequest{Title: "Final"},
+			expectedError: models.ErrUnauthorized.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			article, err := services.NewArticleService(mockRepo).CreateArticle(tt.ctx, tt.request)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, article)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 7, article.ID)
+			}
+		})
+	}
+}
+
+func TestArticleService_CreateArticleRequestKey(t *testing.T) {
+	t.Parallel()
+
+	story := models.ArticleRequest{Title: "Final", Body: "Report"}
+	withKey := func(key string) models.ArticleRequest {
+		request := story
+		request.IdempotencyKey = key
+		return request
+	}
+	otherEditor := auth.NewContext(context.Background(), &models.Principal{
+		Subject: "bo@example.com",
+		Role:    models.RoleEditor,
+		Method:  models.AuthMethodJWT,
+	})
+
+	tests := []struct {
+		name      string
+		first     models.ArticleRequest
+		second    models.ArticleRequest
+		secondCtx context.Context
+		wantSame  bool
+	}{
+		{name: "same idempotency key", first: withKey("a"), second: withKey("a"), wantSame: true},
+		{name: "other idempotency key", first: withKey("a"), second: withKey("b")},
+		{name: "same story without a key", first: story, second: story, wantSame: true},
+		{name: "other story without a key", first: story, second: models.ArticleRequest{Title: "Other"}},
+		{name: "same key from another editor", first: withKey("a"), second: withKey("a"), secondCtx: otherEditor},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keys := []string{}
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			mockRepo.EXPECT().CreateArticle(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, article models.Article, _ models.Editor, requestKey string,
+					_ time.Time) (*models.Article, error) {
+					keys = append(keys, requestKey)
+					return &article, nil
+				}).Times(2)
+
+			service := services.NewArticleService(mockRepo)
+			secondCtx := tt.secondCtx
+			if secondCtx == nil {
+				secondCtx = editorContext()
+			}
+			_, err := service.CreateArticle(editorContext(), tt.first)
+			require.NoError(t, err)
+			_, err = service.CreateArticle(secondCtx, tt.second)
+			require.NoError(t, err)
+
+			assert.NotEmpty(t, keys[0])
+			assert.Equal(t, tt.wantSame, keys[0] == keys[1])
+		})
+	}
+}
+
+func TestArticleService_UpdateArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		id            int
+		patch         models.ArticlePatch
+		mockSetup     func(*repomocks.MockIArticlesRepos)
+		expectedError string
+	}{
+		{
+			name:  "success - override and revert",
+			ctx:   editorContext(),
+			id:    5,
+			patch: models.ArticlePatch{Title: stringPtr(" Edited "), Revert: []string{"summary"}},
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().EditArticle(gomock.Any(), 5,
+					models.ArticlePatch{Title: stringPtr("Edited"), Revert: []string{"summary"}}, editor, gomock.Any()).
+					Return(&models.Article{ID: 5, Title: "Edited"}, nil)
+			},
+		},
+		{
+			name:          "error - invalid ID",
+			ctx:           editorContext(),
+			id:            0,
+			patch:         models.ArticlePatch{Summary: stringPtr("Close game")},
+			expectedError: "invalid id",
+		},
+		{
+			name:          "error - nothing to change",
+			ctx:           editorContext(),
+			id:            5,
+			expectedError: "no field to change",
+		},
+		{
+			name:          "error - empty title",
+			ctx:           editorContext(),
+			id:            5,
+			patch:         models.ArticlePatch{Title: stringPtr("")},
+			expectedError: "invalid title",
+		},
+		{
+			name:          "error - unknown field reverted",
+			ctx:           editorContext(),
+			id:            5,
+			patch:         models.ArticlePatch{Revert: []string{"tags"}},
+			expectedError: "invalid revert",
+		},
+		{
+			name:          "error - field set and reverted",
+			ctx:           editorContext(),
+			id:            5,
+			patch:         models.ArticlePatch{Body: stringPtr("Report"), Revert: []string{"body"}},
+			expectedError: "is also set",
+		},
+		{
+			name:  "error - repository conflict",
+			ctx:   editorContext(),
+			id:    5,
+			patch: models.ArticlePatch{Summary: stringPtr("Close game")},
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().EditArticle(gomock.Any(), 5, gomock.Any(), editor, gomock.Any()).
+					Return(nil, models.ErrConflict)
+			},
+			expectedError: models.ErrConflict.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			article, err := services.NewArticleService(mockRepo).UpdateArticle(tt.ctx, tt.id, tt.patch)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, article)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.id, article.ID)
+			}
+		})
+	}
+}
+
+func TestArticleService_HideArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		id            int
+		mockSetup     func(*repomocks.MockIArticlesRepos)
+		expectedError string
+	}{
+		{
+			name: "success",
+			ctx:  editorContext(),
+			id:   5,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().HideArticle(gomock.Any(), 5, editor, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error - not found",
+			ctx:  editorContext(),
+			id:   5,
+			mockSetup: func(m *repomocks.MockIArticlesRepos) {
+				m.EXPECT().HideArticle(gomock.Any(), 5, editor, gomock.Any()).Return(models.ErrNotFound)
+			},
+			expectedError: models.ErrNotFound.Error(),
+		},
+		{
+			name:          "error - no editor",
+			ctx:           context.Background(),
+			id:            5,
+			expectedError: models.ErrUnauthorized.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIArticlesRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			err := services.NewArticleService(mockRepo).HideArticle(tt.ctx, tt.id)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+)
+
+const (
+	maxQueryLength = 256
+	// snippetWidth is roughly how many characters of context a highlight shows.
+	snippetWidth = 160
+
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// searchTerms extracts the words to highlight from a text search query,
+// leaving out negated terms.
+func searchTerms(query string) [][]rune {
+	terms := [][]rune{}
+	for _, word := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		word = strings.TrimFunc(word, func(r rune) bool { return !isWordRune(r) })
+		if len([]rune(word)) < 2 {
+			continue
+		}
+		terms = append(terms, []rune(strings.ToLower(word)))
+	}
+	return terms
+}
+
+// highlights returns a snippet for every searched field containing a term.
+func highlights(article models.Article, terms [][]rune) []models.Highlight {
+	fields := []struct {
+		name string
+		text string
+	}{
+		{"title", article.Title},
+		{"summary", article.Summary},
+		{"description", article.Description},
+		{"body", plainText(article.Body)},
+	}
+
+	result := []models.Highlight{}
+	for _, field := range fields {
+		if snippet, ok := highlight(field.text, terms); ok {
+			result = append(result, models.Highlight{Field: field.name, Snippet: snippet})
+		}
+	}
+	return result
+}
+
+type span struct {
+	start, end int
+}
+
+// highlight cuts a passage around the first match and marks every match in
+// it. Terms match case-insensitively at the start of a word, covering the
+// whole word so stemmed matches such as "bowling" for "bowl" are marked too.
+// The snippet is HTML: the text around and inside the marks is escaped, so
+// markup from the feed or from editors shows as text.
+func highlight(text string, terms [][]rune) (string, bool) {
+	runes := []rune(text)
+	matches := findMatches(runes, terms)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start, end := window(runes, matches[0])
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := start
+	for _, match := range matches {
+		if match.start < start || match.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:match.start])))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(string(runes[match.start:match.end])))
+		b.WriteString(markClose)
+		pos = match.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString(ellipsis)
+	}
+	return b.String(), true
+}
+
+func findMatches(runes []rune, terms [][]rune) []span {
+	matches := []span{}
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && isWordRune(runes[i-1]) {
+			continue
+		}
+		for _, term := range terms {
+			if !hasFoldedPrefix(runes[i:], term) {
+				continue
+			}
+			end := i + len(term)
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			matches = append(matches, span{start: i, end: end})
+			i = end - 1
+			break
+		}
+	}
+	return matches
+}
+
+// window centres a passage of about snippetWidth characters on the match,
+// moving its edges to word boundaries.
+func window(runes []rune, match span) (int, int) {
+	start := match.start - snippetWidth/3
+	if start <= 0 {
+		start = 0
+	} else {
+		for start < match.start && !unicode.IsSpace(runes[start-1]) {
+			start++
+		}
+	}
+
+	end := start + snippetWidth
+	if end < match.end {
+		end = match.end
+	}
+	if end >= len(runes) {
+		return start, len(runes)
+	}
+	for end > match.end && !unicode.IsSpace(runes[end]) {
+		end--
+	}
+	return start, end
+}
+
+func hasFoldedPrefix(runes []rune, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if unicode.ToLower(runes[i]) != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// plainText strips the markup article bodies are stored with. Entities are
+// decoded so terms match the text readers see; highlight escapes it again.
+func plainText(body string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(body, " "))), " ")
+}
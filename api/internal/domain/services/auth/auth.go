@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/apikeys"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/jwt"
+)
+
+const DefaultRolesClaim = "roles"
+
+// TokenVerifier checks a bearer token and returns its claims.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+type Config struct {
+	// RolesClaim names the claim listing the user's roles: a string, a space
+	// separated list or an array.
+	RolesClaim string
+	// RoleMapping maps claim values, compared case insensitively, to roles.
+	// Values matching a role name map to it unless mapped otherwise.
+	RoleMapping map[string]models.Role
+	// RateLimit limits the requests of each subject; zero is unlimited.
+	RateLimit models.RateLimit
+}
+
+// Authenticator turns verified bearer tokens into principals.
+type Authenticator struct {
+	verifier TokenVerifier
+	cfg      Config
+	limiter  *apikeys.Limiter
+	now      func() time.Time
+}
+
+func NewAuthenticator(verifier TokenVerifier, cfg Config) *Authenticator {
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = DefaultRolesClaim
+	}
+	// The configuration lowercases map keys.
+	mapping := make(map[string]models.Role, len(cfg.RoleMapping))
+	for value, role := range cfg.RoleMapping {
+		mapping[strings.ToLower(value)] = role
+	}
+	cfg.RoleMapping = mapping
+	return &Authenticator{verifier: verifier, cfg: cfg, limiter: apikeys.NewLimiter(), now: time.Now}
+}
+
+// Authenticate verifies the token. An invalid one is ErrUnauthorized, with the
+// reason. A valid token without a known role gets RoleNone.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*models.Principal, error) {
+	claims, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrUnauthorized, err)
+	}
+	if claims.Subject() == "" {
+		return nil, fmt.Errorf("%w: token without a subject", models.ErrUnauthorized)
+	}
+
+	return &models.Principal{
+		Subject: claims.Subject(),
+		Role:    a.role(claims),
+		Method:  models.AuthMethodJWT,
+	}, nil
+}
+
+// Consume counts a request against the rate limit of the principal's
+// subject, so one user's tokens share a limit however many they hold.
+func (a *Authenticator) Consume(_ context.Context, principal *models.Principal) models.RateLimitStatus {
+	status := models.RateLimitStatus{Allowed: true}
+	limit := a.cfg.RateLimit
+	if limit.PerSecond <= 0 || limit.Burst <= 0 {
+		return status
+	}
+
+	status.Limit = limit.Burst
+	status.Allowed, status.Remaining, status.Reset = a.limiter.Take(principal.Subject, limit, a.now())
+	return status
+}
+
+// role is the highest role any of the claim's values maps to.
+func (a *Authenticator) role(claims jwt.Claims) models.Role {
+	role := models.RoleNone
+	for _, value := range claims.Strings(a.cfg.RolesClaim) {
+		mapped, ok := a.cfg.RoleMapping[strings.ToLower(value)]
+		if !ok {
+			mapped, _ = models.ParseRole(value)
+		}
+		role = max(role, mapped)
+	}
+	return role
+}
+
+type principalContextKey struct{}
+
+// NewContext returns a context carrying the request's principal.
+func NewContext(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// FromContext returns the request's principal, or nil outside an
+// authenticated request.
+func FromContext(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*models.Principal)
+	return principal
+}
@@ -0,0 +1,130 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/auth"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVerifier struct {
+	claims jwt.Claims
+	err    error
+}
+
+func (f fakeVerifier) Verify(context.Context, string) (jwt.Claims, error) {
+	return f.claims, f.err
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		cfg           auth.Config
+		verifier      fakeVerifier
+		expectedRole  models.Role
+		expectedError error
+	}{
+		{
+			name:         "role names",
+			verifier:     fakeVerifier{claims: jwt.Claims{"sub": "ana", "roles": []interface{}{"reader", "editor"}}},
+			expectedRole: models.RoleEditor,
+		},
+		{
+			name: "mapped claim values",
+			cfg: auth.Config{
+				RolesClaim:  "groups",
+				RoleMapping: map[string]models.Role{"newsroom-leads": models.RoleAdmin},
+			},
+			verifier:     fakeVerifier{claims: jwt.Claims{"sub": "ana", "groups": []interface{}{"Newsroom-Leads"}}},
+			expectedRole: models.RoleAdmin,
+		},
+		{
+			name: "mapping overrides role names",
+			cfg: auth.Config{
+				RoleMapping: map[string]models.Role{"admin": models.RoleReader},
+			},
+			verifier:     fakeVerifier{claims: jwt.Claims{"sub": "ana", "roles": "admin"}},
+			expectedRole: models.RoleReader,
+		},
+		{
+			name: "space separated scope",
+			cfg: auth.Config{
+				RolesClaim:  "scope",
+				RoleMapping: map[string]models.Role{"articles:write": models.RoleEditor},
+			},
+			verifier:     fakeVerifier{claims: jwt.Claims{"sub": "ana", "scope": "openid articles:write"}},
+			expectedRole: models.RoleEditor,
+		},
+		{
+			name:         "no known role",
+			verifier:     fakeVerifier{claims: jwt.Claims{"sub": "ana", "roles": []interface{}{"intern"}}},
+			expectedRole: models.RoleNone,
+		},
+		{
+			name:          "invalid token",
+			verifier:      fakeVerifier{err: jwt.ErrExpired},
+			expectedError: models.ErrUnauthorized,
+		},
+		{
+			name:          "no subject",
+			verifier:      fakeVerifier{claims: jwt.Claims{"roles": "admin"}},
+			expectedError: models.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			principal, err := auth.NewAuthenticator(tt.verifier, tt.cfg).Authenticate(context.Background(), "token")
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ana", principal.Subject)
+			assert.Equal(t, tt.expectedRole, principal.Role)
+			assert.Equal(t, models.AuthMethodJWT, principal.Method)
+		})
+	}
+}
+
+func TestAuthenticator_Consume(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		limit     models.RateLimit
+		requests  int
+		wantAllow bool
+	}{
+		{name: "unlimited", requests: 100, wantAllow: true},
+		{name: "within the burst", limit: models.RateLimit{PerSecond: 1, Burst: 3}, requests: 3, wantAllow: true},
+		{name: "over the burst", limit: models.RateLimit{PerSecond: 1, Burst: 3}, requests: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			authenticator := auth.NewAuthenticator(fakeVerifier{}, auth.Config{RateLimit: tt.limit})
+			alice := &models.Principal{Subject: "alice"}
+
+			var status models.RateLimitStatus
+			for i := 0; i < tt.requests; i++ {
+				status = authenticator.Consume(context.Background(), alice)
+			}
+
+			assert.Equal(t, tt.wantAllow, status.Allowed)
+			// Other subjects have limits of their own.
+			assert.True(t, authenticator.Consume(context.Background(), &models.Principal{Subject: "bob"}).Allowed)
+		})
+	}
+}
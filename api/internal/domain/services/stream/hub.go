@@ -0,0 +1,214 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/msgqueue"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+)
+
+const resubscribeDelay = 5 * time.Second
+
+// ErrHubClosed is returned to clients subscribing after the hub stopped.
+var ErrHubClosed = errors.New("article stream is closed")
+
+type subscriber struct {
+	live chan models.StreamEvent
+	// While replaying, live events are held in backlog instead of live, so a
+	// long replay doesn't get the client dropped.
+	replaying bool
+	backlog   []models.StreamEvent
+}
+
+// Hub fans the article events of a single subscription out to the live
+// clients. A client that falls behind by more than its buffer is dropped and
+// resumes from its last event on reconnect; the events arriving while a
+// client replays are held for it however many there are.
+type Hub struct {
+	events      msgqueue.IArticleEvents
+	bufferSize  int
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewHub(events msgqueue.IArticleEvents, bufferSize int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		events:      events,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Start relays article events to the clients until ctx is done, then ends
+// every client stream.
+func (h *Hub) Start(ctx context.Context) {
+	defer h.close()
+
+	for {
+		err := h.events.Subscribe(ctx, h.broadcast)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Logger().Error(ctx, fmt.Sprintf("article events subscription ended: %v", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// Subscribe streams the article events matching the filter until ctx is done
+// or the client is dropped, when the channel is closed. With a lastEventID the
+// stored events after it are replayed first.
+func (h *Hub) Subscribe(ctx context.Context, filter models.StreamFilter,
+	lastEventID uint64) (<-chan models.StreamEvent, error) {
+	sub := &subscriber{live: make(chan models.StreamEvent, h.bufferSize), replaying: lastEventID > 0}
+
+	// Register before replaying so no event falls between the two; live events
+	// already replayed are skipped by sequence.
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil, ErrHubClosed
+	}
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	out := make(chan models.StreamEvent)
+	go func() {
+		defer close(out)
+		defer h.remove(sub)
+
+		last, sent := lastEventID, lastEventID
+		send := func(event models.StreamEvent) bool {
+			if event.Sequence <= last {
+				return true
+			}
+			last = event.Sequence
+			if event.Article == nil || !filter.Matches(*event.Article) {
+				return true
+			}
+			select {
+			case out <- event:
+				sent = event.Sequence
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if lastEventID > 0 {
+			err := h.events.Replay(ctx, lastEventID, func(event models.StreamEvent) error {
+				if !send(event) {
+					return ctx.Err()
+				}
+				return nil
+			})
+			if err != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("replaying article events after %d: %v", lastEventID, err))
+				return
+			}
+			// Move the client's resume position past the replayed events the
+			// filter left out, so a reconnect doesn't scan them again.
+			if last > sent {
+				select {
+				case out <- models.StreamEvent{Sequence: last, Type: models.StreamCheckpoint}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			for {
+				backlog, ok := h.caughtUp(sub)
+				if !ok || len(backlog) == 0 {
+					break
+				}
+				for _, event := range backlog {
+					if !send(event) {
+						return
+					}
+				}
+			}
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.live:
+				if !ok || !send(event) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (h *Hub) broadcast(event models.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if sub.replaying {
+			sub.backlog = append(sub.backlog, event)
+			continue
+		}
+		select {
+		case sub.live <- event:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.live)
+		}
+	}
+}
+
+// caughtUp takes the live events held while the subscriber replayed. Once
+// none are left it goes live, so the events are sent in order; ok is false if
+// the subscriber was removed meanwhile.
+func (h *Hub) caughtUp(sub *subscriber) (backlog []models.StreamEvent, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; !ok {
+		return nil, false
+	}
+	backlog, sub.backlog = sub.backlog, nil
+	if len(backlog) == 0 {
+		sub.replaying = false
+	}
+	return backlog, true
+}
+
+func (h *Hub) remove(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.live)
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		close(sub.live)
+	}
+}
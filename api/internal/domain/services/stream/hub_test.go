@@ -0,0 +1,227 @@
+package stream_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/stream"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("api-test"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeEvents replays the stored events and hands on what's pushed to live. A
+// replay waits for replayGate to close when it's set.
+type fakeEvents struct {
+	stored     []models.StreamEvent
+	live       chan models.StreamEvent
+	replayGate chan struct{}
+}
+
+func newFakeEvents(stored ...models.StreamEvent) *fakeEvents {
+	return &fakeEvents{stored: stored, live: make(chan models.StreamEvent)}
+}
+
+func (f *fakeEvents) Subscribe(ctx context.Context, handler func(event models.StreamEvent)) error {
+	for {
+		select {
+		case event := <-f.live:
+			handler(event)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (f *fakeEvents) Replay(_ context.Context, afterSequence uint64,
+	handler func(event models.StreamEvent) error) error {
+	if f.replayGate != nil {
+		<-f.replayGate
+	}
+	for _, event := range f.stored {
+		if event.Sequence <= afterSequence {
+			continue
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func articleEvent(sequence uint64, tagID int) models.StreamEvent {
+	return models.StreamEvent{
+		Sequence: sequence,
+		Type:     "article.updated",
+		Article: &models.ArticleEvent{
+			ID:   int(sequence),
+			Tags: []models.Tag{{ID: tagID}},
+		},
+	}
+}
+
+func receive(t *testing.T, events <-chan models.StreamEvent) models.StreamEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "stream closed")
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event received")
+		return models.StreamEvent{}
+	}
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		stored      []models.StreamEvent
+		lastEventID uint64
+		live        []models.StreamEvent
+		expected    []uint64
+		checkpoint  uint64
+	}{
+		{
+			name:     "live events matching the filter",
+			live:     []models.StreamEvent{articleEvent(1, 7), articleEvent(2, 8), articleEvent(3, 7)},
+			expected: []uint64{1, 3},
+		},
+		{
+			name:        "replays after the last event",
+			stored:      []models.StreamEvent{articleEvent(1, 7), articleEvent(2, 7), articleEvent(3, 7)},
+			lastEventID: 1,
+			live:        []models.StreamEvent{articleEvent(4, 7)},
+			expected:    []uint64{2, 3, 4},
+		},
+		{
+			name:        "skips live events already replayed",
+			stored:      []models.StreamEvent{articleEvent(1, 7), articleEvent(2, 7)},
+			lastEventID: 1,
+			live:        []models.StreamEvent{articleEvent(2, 7), articleEvent(3, 7)},
+			expected:    []uint64{2, 3},
+		},
+		{
+			name:        "checkpoints past filtered out replays",
+			stored:      []models.StreamEvent{articleEvent(1, 7), articleEvent(2, 7), articleEvent(3, 8)},
+			lastEventID: 1,
+			live:        []models.StreamEvent{articleEvent(4, 7)},
+			expected:    []uint64{2, 4},
+			checkpoint:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := newFakeEvents(tt.stored...)
+			hub := stream.NewHub(events, 8)
+			go hub.Start(ctx)
+
+			sub, err := hub.Subscribe(ctx, models.StreamFilter{TagIDs: []int{7}}, tt.lastEventID)
+			require.NoError(t, err)
+
+			go func() {
+				for _, event := range tt.live {
+					select {
+					case events.live <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			var received []uint64
+			var checkpoint uint64
+			for len(received) < len(tt.expected) {
+				event := receive(t, sub)
+				if event.Type == models.StreamCheckpoint {
+					checkpoint = event.Sequence
+					continue
+				}
+				received = append(received, event.Sequence)
+			}
+			assert.Equal(t, tt.expected, received)
+			assert.Equal(t, tt.checkpoint, checkpoint)
+		})
+	}
+}
+
+func TestHub_SubscribeHoldsLiveEventsDuringReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const bufferSize = 2
+	events := newFakeEvents(articleEvent(1, 7), articleEvent(2, 7))
+	events.replayGate = make(chan struct{})
+	hub := stream.NewHub(events, bufferSize)
+	go hub.Start(ctx)
+
+	sub, err := hub.Subscribe(ctx, models.StreamFilter{}, 1)
+	require.NoError(t, err)
+
+	// Far more live events than the client's buffer arrive while the replay
+	// is still running.
+	expected := []uint64{2}
+	for sequence := uint64(3); sequence <= 4*bufferSize+2; sequence++ {
+		events.live <- articleEvent(sequence, 7)
+		expected = append(expected, sequence)
+	}
+	close(events.replayGate)
+
+	var received []uint64
+	for len(received) < len(expected) {
+		received = append(received, receive(t, sub).Sequence)
+	}
+	assert.Equal(t, expected, received)
+
+	events.live <- articleEvent(100, 7)
+	assert.Equal(t, uint64(100), receive(t, sub).Sequence)
+}
+
+func TestHub_Closed(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := stream.NewHub(newFakeEvents(), 8)
+	stopped := make(chan struct{})
+	go func() {
+		hub.Start(ctx)
+		close(stopped)
+	}()
+
+	events, err := hub.Subscribe(context.Background(), models.StreamFilter{}, 0)
+	require.NoError(t, err)
+
+	cancel()
+	<-stopped
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		require.FailNow(t, "stream not closed")
+	}
+
+	_, err = hub.Subscribe(context.Background(), models.StreamFilter{}, 0)
+	assert.ErrorIs(t, err, stream.ErrHubClosed)
+}
@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/msgqueue"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/repos"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+)
+
+const (
+	resubscribeDelay = 5 * time.Second
+
+	SignatureHeader = "X-SportStream-Signature"
+	TimestampHeader = "X-SportStream-Timestamp"
+	EventHeader     = "X-SportStream-Event"
+	DeliveryHeader  = "X-SportStream-Delivery"
+)
+
+type DispatcherConfig struct {
+	// Workers is how many deliveries are POSTed at once.
+	Workers      int
+	PollInterval time.Duration
+	Timeout      time.Duration
+	// Lease is how long a claimed delivery is hidden from other dispatchers;
+	// it must outlast Timeout.
+	Lease time.Duration
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// DisableAfter is how many failed attempts in a row disable a webhook.
+	DisableAfter int
+}
+
+// Dispatcher delivers article events to the webhooks. Events are queued as
+// deliveries in Mongo when consumed, then claimed and POSTed by the workers,
+// so several api instances share the work and retries survive restarts.
+type Dispatcher struct {
+	repo   repos.IWebhooksRepos
+	events msgqueue.IArticleEventConsumer
+	client *http.Client
+	cfg    DispatcherConfig
+}
+
+func NewDispatcher(repo repos.IWebhooksRepos, events msgqueue.IArticleEventConsumer,
+	cfg DispatcherConfig) *Dispatcher {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Dispatcher{
+		repo:   repo,
+		events: events,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+}
+
+// Start queues and delivers article events until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.consume(ctx)
+	}()
+
+	for i := 0; i < d.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) consume(ctx context.Context) {
+	for {
+		err := d.events.Consume(ctx, func(event models.StreamEvent) error {
+			return d.Enqueue(ctx, event)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Logger().Error(ctx, fmt.Sprintf("webhook event consumer ended: %v", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			processed, err := d.Process(ctx)
+			if err != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("processing webhook delivery: %v", err))
+			}
+			if !processed {
+				break
+			}
+		}
+	}
+}
+
+// Enqueue queues a delivery of the event to every active webhook whose filter
+// it matches.
+func (d *Dispatcher) Enqueue(ctx context.Context, event models.StreamEvent) error {
+	if event.Article == nil {
+		return nil
+	}
+
+	webhooks, err := d.repo.ListWebhooks(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Filter.Matches(*event.Article) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            fmt.Sprintf("%s-%d", webhook.ID, event.Sequence),
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Status:        models.DeliveryPending,
+			Attempts:      []models.DeliveryAttempt{},
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		})
+	}
+
+	return d.repo.InsertDeliveries(ctx, deliveries)
+}
+
+// Process claims a due delivery and attempts it. It reports whether there was
+// one to attempt.
+func (d *Dispatcher) Process(ctx context.Context) (bool, error) {
+	delivery, err := d.repo.ClaimDueDelivery(ctx, time.Now().UTC(), d.cfg.Lease)
+	if err != nil || delivery == nil {
+		return false, err
+	}
+
+	webhook, err := d.repo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return true, err
+	}
+	if webhook == nil || !webhook.Active {
+		attempt := models.DeliveryAttempt{At: time.Now().UTC(), Error: "webhook disabled"}
+		return true, d.repo.RecordDeliveryAttempt(ctx, delivery.ID, attempt, models.DeliveryFailed, nil)
+	}
+
+	attempt := d.post(ctx, webhook, delivery)
+	if attempt.Error == "" {
+		if err := d.repo.RecordDeliveryAttempt(ctx, delivery.ID, attempt, models.DeliveryDelivered, nil); err != nil {
+			return true, err
+		}
+		_, err := d.repo.RecordWebhookResult(ctx, webhook.ID, true)
+		return true, err
+	}
+
+	status, next := models.DeliveryFailed, (*time.Time)(nil)
+	if attempts := len(delivery.Attempts) + 1; attempts < d.cfg.MaxAttempts {
+		retryAt := attempt.At.Add(Backoff(attempts, d.cfg.BackoffBase, d.cfg.BackoffMax))
+		status, next = models.DeliveryPending, &retryAt
+	}
+	if err := d.repo.RecordDeliveryAttempt(ctx, delivery.ID, attempt, status, next); err != nil {
+		return true, err
+	}
+
+	failures, err := d.repo.RecordWebhookResult(ctx, webhook.ID, false)
+	if err != nil {
+		return true, err
+	}
+	if d.cfg.DisableAfter > 0 && failures >= d.cfg.DisableAfter {
+		reason := fmt.Sprintf("%d consecutive failed deliveries, last: %s", failures, attempt.Error)
+		log.Logger().Info(ctx, fmt.Sprintf("disabling webhook %s: %s", webhook.ID, reason))
+		return true, d.repo.DisableWebhook(ctx, webhook.ID, reason, attempt.At)
+	}
+	return true, nil
+}
+
+// post sends the delivery, signed with the webhook's secret. Any answer but a
+// 2xx is a failure.
+func (d *Dispatcher) post(ctx context.Context, webhook *models.Webhook,
+	delivery *models.WebhookDelivery) (attempt models.DeliveryAttempt) {
+	start := time.Now().UTC()
+	attempt.At = start
+	defer func() {
+		attempt.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SportStream-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = resp.Status
+	}
+	return attempt
+}
+
+// Backoff is the wait before retrying a delivery after its nth failed
+// attempt: base doubled each time, up to max.
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
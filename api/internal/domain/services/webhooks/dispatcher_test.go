@@ -0,0 +1,201 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/webhooks"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	repomocks "github.com/ronnyp07/SportStream/api/tests/mocks/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "0123456789abcdef"
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("api-test"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+var dispatcherConfig = webhooks.DispatcherConfig{
+	Timeout:      time.Second,
+	Lease:        time.Minute,
+	MaxAttempts:  3,
+	BackoffBase:  time.Minute,
+	BackoffMax:   time.Hour,
+	DisableAfter: 5,
+}
+
+func cricketEvent(sequence uint64) models.StreamEvent {
+	return models.StreamEvent{
+		Sequence: sequence,
+		Type:     "article.created",
+		Article: &models.ArticleEvent{
+			ID:     17,
+			Source: "ecb",
+			Tags:   []models.Tag{{ID: 1, Label: "Cricket"}},
+		},
+	}
+}
+
+func TestDispatcher_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repomocks.NewMockIWebhooksRepos(ctrl)
+	mockRepo.EXPECT().ListWebhooks(gomock.Any(), true).Return([]models.Webhook{
+		{ID: "a1", Filter: models.StreamFilter{TagLabels: []string{"Cricket"}}},
+		{ID: "b2", Filter: models.StreamFilter{Source: "bbc"}},
+		{ID: "c3"},
+	}, nil)
+	mockRepo.EXPECT().InsertDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deliveries []models.WebhookDelivery) error {
+			require.Len(t, deliveries, 2)
+			assert.Equal(t, "a1-42", deliveries[0].ID)
+			assert.Equal(t, "c3-42", deliveries[1].ID)
+			assert.Equal(t, models.DeliveryPending, deliveries[0].Status)
+			assert.NotNil(t, deliveries[0].Attempts)
+			assert.NotNil(t, deliveries[0].NextAttemptAt)
+			return nil
+		})
+
+	dispatcher := webhooks.NewDispatcher(mockRepo, nil, dispatcherConfig)
+	require.NoError(t, dispatcher.Enqueue(context.Background(), cricketEvent(42)))
+}
+
+func TestDispatcher_Process(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		status         int
+		inactive       bool
+		priorAttempts  int
+		failures       int
+		expectedStatus models.DeliveryStatus
+		expectRetry    bool
+		expectDisable  bool
+	}{
+		{
+			name:           "delivered on a 2xx",
+			status:         http.StatusNoContent,
+			expectedStatus: models.DeliveryDelivered,
+		},
+		{
+			name:           "retried with backoff on failure",
+			status:         http.StatusInternalServerError,
+			failures:       1,
+			expectedStatus: models.DeliveryPending,
+			expectRetry:    true,
+		},
+		{
+			name:           "failed after the last attempt",
+			status:         http.StatusBadGateway,
+			priorAttempts:  2,
+			failures:       3,
+			expectedStatus: models.DeliveryFailed,
+		},
+		{
+			name:           "disables the webhook after consecutive failures",
+			status:         http.StatusGone,
+			failures:       5,
+			expectedStatus: models.DeliveryPending,
+			expectRetry:    true,
+			expectDisable:  true,
+		},
+		{
+			name:           "fails deliveries of disabled webhooks",
+			inactive:       true,
+			expectedStatus: models.DeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				timestamp, err := strconv.ParseInt(r.Header.Get(webhooks.TimestampHeader), 10, 64)
+				assert.NoError(t, err)
+				assert.Equal(t, "sha256="+webhooks.Sign(secret, timestamp, body), r.Header.Get(webhooks.SignatureHeader))
+				assert.Equal(t, "article.created", r.Header.Get(webhooks.EventHeader))
+				assert.Equal(t, "a1-42", r.Header.Get(webhooks.DeliveryHeader))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			delivery := &models.WebhookDelivery{
+				ID:        "a1-42",
+				WebhookID: "a1",
+				Event:     cricketEvent(42),
+				Attempts:  make([]models.DeliveryAttempt, tt.priorAttempts),
+			}
+			webhook := &models.Webhook{ID: "a1", URL: server.URL, Secret: secret, Active: !tt.inactive}
+
+			mockRepo := repomocks.NewMockIWebhooksRepos(ctrl)
+			mockRepo.EXPECT().ClaimDueDelivery(gomock.Any(), gomock.Any(), time.Minute).Return(delivery, nil)
+			mockRepo.EXPECT().GetWebhook(gomock.Any(), "a1").Return(webhook, nil)
+			mockRepo.EXPECT().RecordDeliveryAttempt(gomock.Any(), "a1-42", gomock.Any(), tt.expectedStatus, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, attempt models.DeliveryAttempt,
+					_ models.DeliveryStatus, next *time.Time) error {
+					if tt.expectRetry {
+						require.NotNil(t, next)
+						assert.Equal(t, attempt.At.Add(time.Duration(tt.priorAttempts+1)*time.Minute), *next)
+					} else {
+						assert.Nil(t, next)
+					}
+					if !tt.inactive {
+						assert.Equal(t, tt.status, attempt.StatusCode)
+					}
+					return nil
+				})
+			if !tt.inactive {
+				mockRepo.EXPECT().RecordWebhookResult(gomock.Any(), "a1", tt.status < 300).Return(tt.failures, nil)
+			}
+			if tt.expectDisable {
+				mockRepo.EXPECT().DisableWebhook(gomock.Any(), "a1", gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			processed, err := webhooks.NewDispatcher(mockRepo, nil, dispatcherConfig).Process(context.Background())
+
+			require.NoError(t, err)
+			assert.True(t, processed)
+			if tt.inactive {
+				assert.Zero(t, requests)
+			} else {
+				assert.Equal(t, 1, requests)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	base, max := 30*time.Second, 10*time.Minute
+	assert.Equal(t, 30*time.Second, webhooks.Backoff(1, base, max))
+	assert.Equal(t, time.Minute, webhooks.Backoff(2, base, max))
+	assert.Equal(t, 8*time.Minute, webhooks.Backoff(5, base, max))
+	assert.Equal(t, max, webhooks.Backoff(6, base, max))
+	assert.Equal(t, max, webhooks.Backoff(100, base, max))
+}
@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/repos"
+)
+
+const minSecretLength = 16
+
+type WebhookService struct {
+	repo repos.IWebhooksRepos
+}
+
+func NewWebhookService(repo repos.IWebhooksRepos) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, request models.WebhookRequest) (*models.Webhook, error) {
+	if err := validateURL(request.URL); err != nil {
+		return nil, err
+	}
+	if len(request.Secret) < minSecretLength {
+		return nil, models.ValidationError{Field: "secret", Reason: "must be at least 16 characters"}
+	}
+	filter, err := validateFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		ID:        id,
+		URL:       request.URL,
+		Filter:    filter,
+		Secret:    request.Secret,
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	return s.repo.GetWebhook(ctx, id)
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return s.repo.ListWebhooks(ctx, false)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.repo.DeleteWebhook(ctx, id)
+}
+
+// EnableWebhook turns a disabled webhook back on with a clean failure count.
+// Deliveries that failed while it was off are not retried.
+func (s *WebhookService) EnableWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	if err := s.repo.EnableWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetWebhook(ctx, id)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, id string, page, pageSize int) (*models.PaginatedDeliveries, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	// Answer not found for an unknown webhook rather than an empty log.
+	if _, err := s.repo.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDeliveries(ctx, id, page, pageSize)
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.ValidationError{Field: "url", Reason: "expected an absolute http or https URL"}
+	}
+	return nil
+}
+
+func validateFilter(filter models.StreamFilter) (models.StreamFilter, error) {
+	labels := make([]string, 0, len(filter.TagLabels))
+	for _, label := range filter.TagLabels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return filter, models.ValidationError{Field: "filter.tagLabels", Reason: "empty tag"}
+		}
+		labels = append(labels, label)
+	}
+	filter.TagLabels = labels
+	filter.Source = strings.TrimSpace(filter.Source)
+	return filter, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,101 @@
+package webhooks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/services/webhooks"
+	repomocks "github.com/ronnyp07/SportStream/api/tests/mocks/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		request       models.WebhookRequest
+		mockSetup     func(*repomocks.MockIWebhooksRepos)
+		expectedError string
+	}{
+		{
+			name: "success - active webhook with trimmed filter",
+			request: models.WebhookRequest{
+				URL:    "https://partner.example.com/hooks/articles",
+				Filter: models.StreamFilter{TagLabels: []string{" Cricket "}, Source: "ecb"},
+				Secret: "0123456789abcdef",
+			},
+			mockSetup: func(m *repomocks.MockIWebhooksRepos) {
+				m.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, webhook *models.Webhook) error {
+						assert.Len(t, webhook.ID, 32)
+						assert.True(t, webhook.Active)
+						assert.Equal(t, []string{"Cricket"}, webhook.Filter.TagLabels)
+						assert.Equal(t, "0123456789abcdef", webhook.Secret)
+						return nil
+					})
+			},
+		},
+		{
+			name:          "error - relative URL",
+			request:       models.WebhookRequest{URL: "/hooks", Secret: "0123456789abcdef"},
+			expectedError: "invalid url",
+		},
+		{
+			name:          "error - unsupported scheme",
+			request:       models.WebhookRequest{URL: "ftp://partner.example.com", Secret: "0123456789abcdef"},
+			expectedError: "invalid url",
+		},
+		{
+			name:          "error - short secret",
+			request:       models.WebhookRequest{URL: "https://partner.example.com", Secret: "short"},
+			expectedError: "invalid secret",
+		},
+		{
+			name: "error - empty tag",
+			request: models.WebhookRequest{
+				URL:    "https://partner.example.com",
+				Filter: models.StreamFilter{TagLabels: []string{" "}},
+				Secret: "0123456789abcdef",
+			},
+			expectedError: "invalid filter.tagLabels",
+		},
+		{
+			name:    "error - repository error",
+			request: models.WebhookRequest{URL: "https://partner.example.com", Secret: "0123456789abcdef"},
+			mockSetup: func(m *repomocks.MockIWebhooksRepos) {
+				m.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+			expectedError: assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repomocks.NewMockIWebhooksRepos(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			webhook, err := webhooks.NewWebhookService(mockRepo).CreateWebhook(context.Background(), tt.request)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, webhook)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.request.URL, webhook.URL)
+		})
+	}
+}
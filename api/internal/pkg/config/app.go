@@ -20,6 +20,85 @@ type AppConfig struct {
 	Env           Environment   `mapstructure:"ENVIRONMENT"`
 	Observability Observability `mapstructure:"OBSERVABILITY"`
 	Http          Http          `mapstructure:"HTTP"`
+	Migrations    Migrations    `mapstructure:"MIGRATIONS"`
+	Nats          Nats          `mapstructure:"NATS"`
+	LiveStream    LiveStream    `mapstructure:"LIVE_STREAM"`
+	Webhooks      Webhooks      `mapstructure:"WEBHOOKS"`
+	APIKeys       APIKeys       `mapstructure:"API_KEYS"`
+	Auth          Auth          `mapstructure:"AUTH"`
+}
+
+type Auth struct {
+	JWT JWT `mapstructure:"JWT"`
+	// InsecureAnonymousAdmin makes callers without credentials admins. It's
+	// for local development only and is logged loudly at startup.
+	InsecureAnonymousAdmin bool `mapstructure:"INSECURE_ANONYMOUS_ADMIN"`
+}
+
+type JWT struct {
+	Enabled bool `mapstructure:"ENABLED"`
+	// JWKS is a file path or an http(s) URL; URLs are refetched every
+	// RefreshInterval.
+	JWKS            string        `mapstructure:"JWKS"`
+	RefreshInterval time.Duration `mapstructure:"REFRESH_INTERVAL"`
+	Issuer          string        `mapstructure:"ISSUER"`
+	Audience        string        `mapstructure:"AUDIENCE"`
+	Leeway          time.Duration `mapstructure:"LEEWAY"`
+	RolesClaim      string        `mapstructure:"ROLES_CLAIM"`
+	// RoleMapping maps claim values to reader, editor or admin.
+	RoleMapping map[string]string `mapstructure:"ROLE_MAPPING"`
+	// RatePerSecond and Burst limit the requests of each token subject.
+	RatePerSecond float64 `mapstructure:"RATE_PER_SECOND"`
+	Burst         int     `mapstructure:"BURST"`
+}
+
+type APIKeys struct {
+	Enabled           bool          `mapstructure:"ENABLED"`
+	BootstrapAdminKey string        `mapstructure:"BOOTSTRAP_ADMIN_KEY"`
+	CacheTTL          time.Duration `mapstructure:"CACHE_TTL"`
+	RatePerSecond     float64       `mapstructure:"RATE_PER_SECOND"`
+	Burst             int           `mapstructure:"BURST"`
+	DailyQuota        int           `mapstructure:"DAILY_QUOTA"`
+}
+
+type Webhooks struct {
+	Enabled      bool          `mapstructure:"ENABLED"`
+	Consumer     string        `mapstructure:"CONSUMER_NAME"`
+	AckWait      time.Duration `mapstructure:"ACK_WAIT"`
+	Workers      int           `mapstructure:"WORKERS"`
+	PollInterval time.Duration `mapstructure:"POLL_INTERVAL"`
+	Timeout      time.Duration `mapstructure:"TIMEOUT"`
+	Lease        time.Duration `mapstructure:"LEASE"`
+	MaxAttempts  int           `mapstructure:"MAX_ATTEMPTS"`
+	BackoffBase  time.Duration `mapstructure:"BACKOFF_BASE"`
+	BackoffMax   time.Duration `mapstructure:"BACKOFF_MAX"`
+	DisableAfter int           `mapstructure:"DISABLE_AFTER"`
+}
+
+type Nats struct {
+	ReconnectWait time.Duration `mapstructure:"RECONNECT_WAIT"`
+	Events        Events        `mapstructure:"EVENTS"`
+}
+
+// Events is where the worker publishes article events.
+type Events struct {
+	Stream  string `mapstructure:"STREAM"`
+	Subject string `mapstructure:"SUBJECT"`
+}
+
+type LiveStream struct {
+	Heartbeat    time.Duration `mapstructure:"HEARTBEAT"`
+	Retry        time.Duration `mapstructure:"RETRY"`
+	ClientBuffer int           `mapstructure:"CLIENT_BUFFER"`
+	// AllowedOrigins are the origins, besides the API's own, browsers may
+	// open the WebSocket stream from; "*" allows any.
+	AllowedOrigins []string `mapstructure:"ALLOWED_ORIGINS"`
+}
+
+type Migrations struct {
+	DryRun   bool          `mapstructure:"DRY_RUN"`
+	LockTTL  time.Duration `mapstructure:"LOCK_TTL"`
+	LockWait time.Duration `mapstructure:"LOCK_WAIT"`
 }
 
 type Environment struct {
@@ -0,0 +1,189 @@
+package articleevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+)
+
+const replayFetchWait = 2 * time.Second
+
+// envelope is the wrapper the worker publishes article events in.
+type envelope struct {
+	Type       string          `json:"type"`
+	ProducedAt time.Time       `json:"producedAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// ArticleEvents reads the article events the worker publishes to JetStream
+// with ordered consumers, which need no durable state on the server.
+type ArticleEvents struct {
+	js      jetstream.JetStream
+	stream  string
+	subject string
+}
+
+func NewArticleEvents(js jetstream.JetStream, stream string, subject string) *ArticleEvents {
+	return &ArticleEvents{
+		js:      js,
+		stream:  stream,
+		subject: subject,
+	}
+}
+
+func (e *ArticleEvents) Subscribe(ctx context.Context, handler func(event models.StreamEvent)) error {
+	consumer, err := e.js.OrderedConsumer(ctx, e.stream, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{e.subject},
+		DeliverPolicy:  jetstream.DeliverNewPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("creating article events consumer: %w", err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		event, err := decode(msg)
+		if err != nil {
+			log.Logger().Error(ctx, fmt.Sprintf("skipping article event on %s: %v", msg.Subject(), err))
+			return
+		}
+		handler(event)
+	})
+	if err != nil {
+		return fmt.Errorf("consuming article events: %w", err)
+	}
+	defer consumeCtx.Stop()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (e *ArticleEvents) Replay(ctx context.Context, afterSequence uint64,
+	handler func(event models.StreamEvent) error) error {
+	consumer, err := e.js.OrderedConsumer(ctx, e.stream, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{e.subject},
+		DeliverPolicy:  jetstream.DeliverByStartSequencePolicy,
+		OptStartSeq:    afterSequence + 1,
+	})
+	if err != nil {
+		return fmt.Errorf("creating article events replay consumer: %w", err)
+	}
+	if consumer.CachedInfo().NumPending == 0 {
+		return nil
+	}
+
+	for {
+		msg, err := consumer.Next(jetstream.FetchMaxWait(replayFetchWait))
+		if errors.Is(err, nats.ErrTimeout) || errors.Is(err, jetstream.ErrNoMessages) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("replaying article events: %w", err)
+		}
+
+		metadata, err := msg.Metadata()
+		if err != nil {
+			return fmt.Errorf("reading article event metadata: %w", err)
+		}
+
+		if event, err := decode(msg); err == nil {
+			if err := handler(event); err != nil {
+				return err
+			}
+		} else {
+			log.Logger().Error(ctx, fmt.Sprintf("skipping article event %d: %v", metadata.Sequence.Stream, err))
+		}
+
+		if metadata.NumPending == 0 {
+			return nil
+		}
+	}
+}
+
+func decode(msg jetstream.Msg) (models.StreamEvent, error) {
+	metadata, err := msg.Metadata()
+	if err != nil {
+		return models.StreamEvent{}, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(msg.Data(), &env); err != nil {
+		return models.StreamEvent{}, err
+	}
+	var article models.ArticleEvent
+	if err := json.Unmarshal(env.Payload, &article); err != nil {
+		return models.StreamEvent{}, err
+	}
+
+	return models.StreamEvent{
+		Sequence:   metadata.Sequence.Stream,
+		Type:       env.Type,
+		ProducedAt: env.ProducedAt,
+		Article:    &article,
+	}, nil
+}
+
+// Consumer reads the article events through a durable consumer shared by
+// every api instance, so each event is handled once and none is lost while
+// the api is down.
+type Consumer struct {
+	js      jetstream.JetStream
+	stream  string
+	subject string
+	durable string
+	ackWait time.Duration
+}
+
+func NewConsumer(js jetstream.JetStream, stream string, subject string, durable string,
+	ackWait time.Duration) *Consumer {
+	return &Consumer{
+		js:      js,
+		stream:  stream,
+		subject: subject,
+		durable: durable,
+		ackWait: ackWait,
+	}
+}
+
+func (c *Consumer) Consume(ctx context.Context, handler func(event models.StreamEvent) error) error {
+	// New events only when the consumer is first created; afterwards it
+	// resumes where it left off.
+	consumer, err := c.js.CreateOrUpdateConsumer(ctx, c.stream, jetstream.ConsumerConfig{
+		Durable:       c.durable,
+		FilterSubject: c.subject,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       c.ackWait,
+	})
+	if err != nil {
+		return fmt.Errorf("creating %s consumer: %w", c.durable, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		event, err := decode(msg)
+		if err != nil {
+			log.Logger().Error(ctx, fmt.Sprintf("dropping article event on %s: %v", msg.Subject(), err))
+			msg.Term()
+			return
+		}
+		if err := handler(event); err != nil {
+			log.Logger().Error(ctx, fmt.Sprintf("handling article event %d: %v", event.Sequence, err))
+			msg.NakWithDelay(c.ackWait)
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("consuming article events: %w", err)
+	}
+	defer consumeCtx.Stop()
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Component names the api's migrations in schema_migrations. The worker
+// migrates the collections it writes; the api owns the indexes its reads need.
+const Component = "api"
+
+const (
+	articlesCollectionName   = "articles"
+	deliveriesCollectionName = "webhook_deliveries"
+	apiKeysCollectionName    = "api_keys"
+	usageCollectionName      = "api_key_usage"
+
+	usageRetention = 35 * 24 * time.Hour
+)
+
+// API lists the migrations of the indexes and collections the api owns.
+func API() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "index on articles.date for the paginated listing",
+			Up: createIndex(articlesCollectionName, mongo.IndexModel{
+				Keys:    bson.D{{Key: "date", Value: -1}, {Key: "id", Value: -1}},
+				Options: options.Index().SetName("date_id"),
+			}),
+		},
+		{
+			Version:     2,
+			Description: "order the listing by publishedAt instead of the date string",
+			Up:          replaceDateIndex,
+		},
+		{
+			Version:     3,
+			Description: "text index on articles for search",
+			Up: createIndex(articlesCollectionName, mongo.IndexModel{
+				Keys: bson.D{
+					{Key: "title", Value: "text"},
+					{Key: "summary", Value: "text"},
+					{Key: "description", Value: "text"},
+					{Key: "body", Value: "text"},
+				},
+				Options: options.Index().SetName("articles_text").
+					SetDefaultLanguage("english").
+					SetWeights(bson.D{
+						{Key: "title", Value: 10},
+						{Key: "summary", Value: 5},
+						{Key: "description", Value: 3},
+						{Key: "body", Value: 1},
+					}),
+			}),
+		},
+		{
+			Version:     4,
+			Description: "indexes on articles for the tag, media type and source filters",
+			Up: createIndexes(articlesCollectionName,
+				listingIndex("tags.id"),
+				listingIndex("tags.label"),
+				listingIndex("leadmedia.type"),
+				listingIndex("source"),
+			),
+		},
+		{
+			Version:     5,
+			Description: "indexes on webhook_deliveries for the dispatcher and the delivery log",
+			Up: createIndexes(deliveriesCollectionName,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+					Options: options.Index().SetName("status_nextAttemptAt"),
+				},
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "webhookID", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+					Options: options.Index().SetName("webhookID_createdAt_id"),
+				},
+			),
+		},
+		{
+			Version:     6,
+			Description: "unique index on api_keys.hash and expiry of api_key_usage",
+			Up:          apiKeyIndexes,
+		},
+		{
+			Version:     7,
+			Description: "unique index on the request key of stories written by editors",
+			Up: createIndex(articlesCollectionName, mongo.IndexModel{
+				Keys: bson.D{{Key: "requestKey", Value: 1}},
+				Options: options.Index().SetName("requestKey_unique").SetUnique(true).
+					SetPartialFilterExpression(bson.M{"requestKey": bson.M{"$exists": true}}),
+			}),
+		},
+	}
+}
+
+// createIndex returns a step creating the index; creating an index that
+// already exists with the same options is a no-op.
+func createIndex(collection string, index mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, index)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create index on %s", collection)
+		}
+		return nil
+	}
+}
+
+// createIndexes is createIndex for several indexes at once.
+func createIndexes(collection string, indexes ...mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create indexes on %s", collection)
+		}
+		return nil
+	}
+}
+
+// listingIndex indexes a filtered field followed by the listing order, so a
+// filtered page is read in order without sorting.
+func listingIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}, {Key: "publishedAt", Value: -1}, {Key: "id", Value: -1}},
+		Options: options.Index().SetName(field + "_publishedAt_id"),
+	}
+}
+
+// replaceDateIndex moves the listing index to publishedAt, the date string
+// sorts lexically and is no longer queried.
+func replaceDateIndex(ctx context.Context, db *mongo.Database) error {
+	err := createIndex(articlesCollectionName, mongo.IndexModel{
+		Keys:    bson.D{{Key: "publishedAt", Value: -1}, {Key: "id", Value: -1}},
+		Options: options.Index().SetName("publishedAt_id"),
+	})(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(articlesCollectionName).Indexes().DropOne(ctx, "date_id")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+		return errors.Wrap(err, "failed to drop the date index")
+	}
+	return nil
+}
+
+// apiKeyIndexes looks keys up by hash, which must be unique, and expires the
+// daily usage counters once no quota reads them.
+func apiKeyIndexes(ctx context.Context, db *mongo.Database) error {
+	err := createIndex(apiKeysCollectionName, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetName("hash").SetUnique(true),
+	})(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	return createIndex(usageCollectionName, mongo.IndexModel{
+		Keys:    bson.D{{Key: "day", Value: 1}},
+		Options: options.Index().SetName("day_ttl").SetExpireAfterSeconds(int32(usageRetention.Seconds())),
+	})(ctx, db)
+}
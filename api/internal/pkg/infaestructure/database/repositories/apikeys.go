@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/metrics"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	apiKeysCollectionName = "api_keys"
+	usageCollectionName   = "api_key_usage"
+)
+
+type APIKeyRepository struct {
+	keys    *mongo.Collection
+	usage   *mongo.Collection
+	metrics metrics.MetricsHandler
+}
+
+func NewAPIKeyRepository(db *mongo.Database, metrics metrics.MetricsHandler) *APIKeyRepository {
+	return &APIKeyRepository{
+		keys:    db.Collection(apiKeysCollectionName),
+		usage:   db.Collection(usageCollectionName),
+		metrics: metrics,
+	}
+}
+
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *models.APIKey) error {
+	r.metrics.DBCall("CreateKey")
+
+	if _, err := r.keys.InsertOne(ctx, key); err != nil {
+		r.metrics.DBErrorInc("CreateKey", "insert_error")
+		return errors.Wrap(err, "failed to create api key")
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) GetKey(ctx context.Context, id string) (*models.APIKey, error) {
+	r.metrics.DBCall("GetKey")
+	return r.findKey(ctx, "GetKey", bson.M{"_id": id})
+}
+
+func (r *APIKeyRepository) GetKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	r.metrics.DBCall("GetKeyByHash")
+	return r.findKey(ctx, "GetKeyByHash", bson.M{"hash": hash})
+}
+
+func (r *APIKeyRepository) findKey(ctx context.Context, method string, filter bson.M) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.keys.FindOne(ctx, filter).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc(method, "not_found")
+			return nil, errors.Wrap(models.ErrNotFound, "api key")
+		}
+		r.metrics.DBErrorInc(method, err.Error())
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	r.metrics.DBCall("ListKeys")
+
+	cursor, err := r.keys.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		r.metrics.DBErrorInc("ListKeys", "find_error")
+		return nil, errors.Wrap(err, "failed to find api keys")
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		r.metrics.DBErrorInc("ListKeys", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode api keys")
+	}
+	return keys, nil
+}
+
+// RotateKey replaces the hash of a key that isn't revoked.
+func (r *APIKeyRepository) RotateKey(ctx context.Context, id string, hash string, prefix string, at time.Time) error {
+	r.metrics.DBCall("RotateKey")
+
+	result, err := r.keys.UpdateOne(ctx,
+		bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"hash": hash, "prefix": prefix, "rotatedAt": at}})
+	if err != nil {
+		r.metrics.DBErrorInc("RotateKey", "update_error")
+		return errors.Wrap(err, "failed to rotate api key")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(models.ErrNotFound, "active api key")
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id string, at time.Time) error {
+	r.metrics.DBCall("RevokeKey")
+
+	result, err := r.keys.UpdateOne(ctx,
+		bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": at}})
+	if err != nil {
+		r.metrics.DBErrorInc("RevokeKey", "update_error")
+		return errors.Wrap(err, "failed to revoke api key")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(models.ErrNotFound, "active api key")
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) IncrementUsage(ctx context.Context, id string, day time.Time) (int, error) {
+	r.metrics.DBCall("IncrementUsage")
+
+	day = day.UTC().Truncate(24 * time.Hour)
+	var usage struct {
+		Count int `bson:"count"`
+	}
+	err := r.usage.FindOneAndUpdate(ctx,
+		bson.M{"_id": fmt.Sprintf("%s-%s", id, day.Format(time.DateOnly))},
+		bson.M{
+			"$inc":         bson.M{"count": 1},
+			"$setOnInsert": bson.M{"keyID": id, "day": day},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&usage)
+	if err != nil {
+		r.metrics.DBErrorInc("IncrementUsage", "update_error")
+		return 0, errors.Wrap(err, "failed to count api key usage")
+	}
+	return usage.Count, nil
+}
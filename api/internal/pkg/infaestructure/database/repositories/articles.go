@@ -2,28 +2,35 @@ package repositories
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/pkg/errors"
 	"github.com/ronnyp07/SportStream/api/internal/domain/models"
 	"github.com/ronnyp07/SportStream/api/internal/domain/ports/metrics"
+	"github.com/ronnyp07/SportStream/pkg/articledb"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const (
-	collectionName = "articles"
-)
-
 type ArticleRepository struct {
 	collection *mongo.Collection
+	revisions  *mongo.Collection
+	outbox     *mongo.Collection
 	metrics    metrics.MetricsHandler
 }
 
 func NewArticleRepository(db *mongo.Database,
 	metrics metrics.MetricsHandler) *ArticleRepository {
+	// Revision diffs hold values of any type; decode nested documents as maps
+	// so they serialize as JSON objects.
+	registry := bson.NewRegistry()
+	registry.RegisterTypeMapEntry(bson.TypeEmbeddedDocument, reflect.TypeOf(bson.M{}))
+
 	return &ArticleRepository{
-		collection: db.Collection(collectionName),
+		collection: db.Collection(articledb.ArticlesCollection),
+		revisions:  db.Collection(articledb.RevisionsCollection, options.Collection().SetRegistry(registry)),
+		outbox:     db.Collection(articledb.OutboxCollection),
 		metrics:    metrics,
 	}
 }
@@ -32,7 +39,7 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int) (*models.Articl
 	r.metrics.DBCall("GetByID")
 
 	var article models.Article
-	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&article)
+	err := r.collection.FindOne(ctx, visible(bson.M{"id": id})).Decode(&article)
 
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -50,7 +57,7 @@ func (r *ArticleRepository) GetByExternalID(ctx context.Context, externalID int)
 	r.metrics.DBCall("GetByExternalID")
 
 	var article models.Article
-	err := r.collection.FindOne(ctx, bson.M{"externalID": externalID}).Decode(&article)
+	err := r.collection.FindOne(ctx, visible(bson.M{"externalID": externalID})).Decode(&article)
 
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -64,13 +71,15 @@ func (r *ArticleRepository) GetByExternalID(ctx context.Context, externalID int)
 	return &article, nil
 }
 
-func (r *ArticleRepository) GetPaginatedArticles(ctx context.Context, page, pageSize int) (*models.PaginatedArticles, error) {
+func (r *ArticleRepository) GetPaginatedArticles(ctx context.Context, page, pageSize int,
+	filter models.ArticleFilter) (*models.PaginatedArticles, error) {
 	r.metrics.DBCall("GetPaginatedArticles")
 
 	skip := (page - 1) * pageSize
+	query := articleFilterQuery(filter)
 
 	// Get total count of articles
-	total, err := r.collection.CountDocuments(ctx, bson.M{})
+	total, err := r.collection.CountDocuments(ctx, query)
 	if err != nil {
 		r.metrics.DBErrorInc("GetPaginatedArticles", "count_error")
 		return nil, errors.Wrap(err, "failed to count articles")
@@ -84,10 +93,10 @@ func (r *ArticleRepository) GetPaginatedArticles(ctx context.Context, page, page
 	findOptions := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(pageSize)).
-		SetSort(bson.D{{Key: "date", Value: -1}})
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}, {Key: "id", Value: -1}})
 
 	// Execute query
-	cursor, err := r.collection.Find(ctx, bson.M{}, findOptions)
+	cursor, err := r.collection.Find(ctx, query, findOptions)
 	if err != nil {
 		r.metrics.DBErrorInc("GetPaginatedArticles", "find_error")
 		return nil, errors.Wrap(err, "failed to find articles")
@@ -101,7 +110,7 @@ func (r *ArticleRepository) GetPaginatedArticles(ctx context.Context, page, page
 	}
 
 	return &models.PaginatedArticles{
-		PageInfo: models.PageInfo{
+		PageInfo: &models.PageInfo{
 			Page:       page,
 			NumPages:   totalPages,
 			PageSize:   pageSize,
@@ -110,3 +119,136 @@ func (r *ArticleRepository) GetPaginatedArticles(ctx context.Context, page, page
 		Content: articles,
 	}, nil
 }
+
+// GetArticlesAfter returns up to limit articles following the cursor in the
+// listing order, or from the start without one. Unlike offset pages it reads
+// only what it returns, and articles arriving meanwhile don't shift it.
+func (r *ArticleRepository) GetArticlesAfter(ctx context.Context, after *models.ArticleCursor, limit int,
+	filter models.ArticleFilter) ([]models.Article, error) {
+	r.metrics.DBCall("GetArticlesAfter")
+
+	query := articleFilterQuery(filter)
+	if after != nil {
+		query = bson.M{"$and": bson.A{query, afterCursorQuery(*after)}}
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}, {Key: "id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		r.metrics.DBErrorInc("GetArticlesAfter", "find_error")
+		return nil, errors.Wrap(err, "failed to find articles")
+	}
+	defer cursor.Close(ctx)
+
+	articles := []models.Article{}
+	if err := cursor.All(ctx, &articles); err != nil {
+		r.metrics.DBErrorInc("GetArticlesAfter", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode articles")
+	}
+
+	return articles, nil
+}
+
+func (r *ArticleRepository) CountArticles(ctx context.Context, filter models.ArticleFilter) (int, error) {
+	r.metrics.DBCall("CountArticles")
+
+	total, err := r.collection.CountDocuments(ctx, articleFilterQuery(filter))
+	if err != nil {
+		r.metrics.DBErrorInc("CountArticles", "count_error")
+		return 0, errors.Wrap(err, "failed to count articles")
+	}
+	return int(total), nil
+}
+
+// SearchArticles finds the articles matching a text search, ordered by score
+// and then by date.
+func (r *ArticleRepository) SearchArticles(ctx context.Context, query string, page, pageSize int) (*models.SearchResults, error) {
+	r.metrics.DBCall("SearchArticles")
+
+	filter := visible(bson.M{"$text": bson.M{"$search": query}})
+	skip := (page - 1) * pageSize
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		r.metrics.DBErrorInc("SearchArticles", "count_error")
+		return nil, errors.Wrap(err, "failed to count matching articles")
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize != 0 {
+		totalPages++
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "publishedAt", Value: -1}, {Key: "id", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		r.metrics.DBErrorInc("SearchArticles", "find_error")
+		return nil, errors.Wrap(err, "failed to search articles")
+	}
+	defer cursor.Close(ctx)
+
+	results := []models.SearchResult{}
+	if err := cursor.All(ctx, &results); err != nil {
+		r.metrics.DBErrorInc("SearchArticles", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode matching articles")
+	}
+
+	return &models.SearchResults{
+		Query: query,
+		PageInfo: models.PageInfo{
+			Page:       page,
+			NumPages:   totalPages,
+			PageSize:   pageSize,
+			NumEntries: int(total),
+		},
+		Content: results,
+	}, nil
+}
+
+// GetRevisions returns the previous versions of an article, newest first.
+func (r *ArticleRepository) GetRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error) {
+	r.metrics.DBCall("GetRevisions")
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+	cursor, err := r.revisions.Find(ctx, bson.M{"articleID": id}, findOptions)
+	if err != nil {
+		r.metrics.DBErrorInc("GetRevisions", "find_error")
+		return nil, errors.Wrap(err, "failed to find article revisions")
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []models.ArticleRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		r.metrics.DBErrorInc("GetRevisions", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode article revisions")
+	}
+
+	return revisions, nil
+}
+
+func (r *ArticleRepository) GetRevision(ctx context.Context, id, revision int) (*models.ArticleRevision, error) {
+	r.metrics.DBCall("GetRevision")
+
+	var articleRevision models.ArticleRevision
+	err := r.revisions.FindOne(ctx, bson.M{"articleID": id, "revision": revision}).Decode(&articleRevision)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc("GetRevision", "not_found")
+			return nil, errors.Wrap(models.ErrNotFound, "revision")
+		}
+		r.metrics.DBErrorInc("GetRevision", err.Error())
+		return nil, err
+	}
+
+	return &articleRevision, nil
+}
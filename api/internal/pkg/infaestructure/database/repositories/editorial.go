@@ -0,0 +1,417 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/pkg/articledb"
+	"github.com/sts-solutions/base-code/cccorrelation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// storedArticle is an article document with the fields edits need: its
+// revision, which every edit bumps, the feed's values of the overridden fields,
+// kept by the worker, and the latest revision whose event is queued.
+type storedArticle struct {
+	models.Article `bson:",inline"`
+	Revision       int               `bson:"revision"`
+	Upstream       map[string]string `bson:"upstream,omitempty"`
+	CreatedAt      *time.Time        `bson:"createdAt,omitempty"`
+	QueuedRevision int               `bson:"queuedRevision,omitempty"`
+	// RequestKey identifies the request that created a story written by an
+	// editor.
+	RequestKey string `bson:"requestKey,omitempty"`
+}
+
+func (s storedArticle) revision() int {
+	if s.Revision < 1 {
+		return 1
+	}
+	return s.Revision
+}
+
+func (s storedArticle) manual() bool {
+	return s.Editorial != nil && s.Editorial.Manual
+}
+
+func (s storedArticle) overridden(name string) bool {
+	if s.Editorial == nil {
+		return false
+	}
+	_, ok := s.Editorial.Overrides[name]
+	return ok
+}
+
+var editableFields = map[string]func(*models.Article) *string{
+	"title":       func(a *models.Article) *string { return &a.Title },
+	"description": func(a *models.Article) *string { return &a.Description },
+	"summary":     func(a *models.Article) *string { return &a.Summary },
+	"body":        func(a *models.Article) *string { return &a.Body },
+}
+
+// editPlan is what an edit writes to the stored article.
+type editPlan struct {
+	set     bson.M
+	unset   bson.M
+	changes []models.FieldChange
+}
+
+// planPatch overrides the patched fields and reverts the others asked for.
+// The feed's value of a field is kept when it's first overridden, so it can be
+// reverted to; the stories editors write have no feed and no overrides. Fields
+// patched to their current value aren't changes.
+func planPatch(stored storedArticle, patch models.ArticlePatch) editPlan {
+	plan := editPlan{set: bson.M{}, unset: bson.M{}}
+
+	for name, value := range patch.Fields() {
+		field, ok := editableFields[name]
+		if !ok {
+			continue
+		}
+		current := *field(&stored.Article)
+		if value == current && (stored.manual() || stored.overridden(name)) {
+			continue
+		}
+		if value == current {
+			// Overriding with the feed's own value keeps it as the feed changes.
+			plan.set["editorial.overrides."+name] = value
+			plan.set["upstream."+name] = current
+			continue
+		}
+
+		plan.set[name] = value
+		plan.changes = append(plan.changes, models.FieldChange{Field: name, Previous: current, Current: value})
+		if stored.manual() {
+			continue
+		}
+		plan.set["editorial.overrides."+name] = value
+		if !stored.overridden(name) {
+			plan.set["upstream."+name] = current
+		}
+	}
+
+	for _, name := range patch.Revert {
+		field, ok := editableFields[name]
+		if !ok || !stored.overridden(name) {
+			continue
+		}
+		plan.unset["editorial.overrides."+name] = ""
+		plan.unset["upstream."+name] = ""
+
+		current := *field(&stored.Article)
+		if upstream, ok := stored.Upstream[name]; ok && upstream != current {
+			plan.set[name] = upstream
+			plan.changes = append(plan.changes, models.FieldChange{Field: name, Previous: current, Current: upstream})
+		}
+	}
+
+	return plan
+}
+
+func planHide() editPlan {
+	return editPlan{
+		set:     bson.M{"editorial.hidden": true},
+		unset:   bson.M{},
+		changes: []models.FieldChange{{Field: "hidden", Previous: false, Current: true}},
+	}
+}
+
+// update builds the article update of a plan with changes, which makes the
+// next revision, signed by the editor.
+func (p editPlan) update(stored storedArticle, editor models.Editor, now time.Time) bson.M {
+	set := bson.M{
+		"revision":           stored.revision() + 1,
+		"updatedAt":          now,
+		"editorial.editedBy": editor,
+		"editorial.editedAt": now,
+	}
+	for name, value := range p.set {
+		set[name] = value
+	}
+
+	update := bson.M{"$set": set}
+	if len(p.unset) > 0 {
+		update["$unset"] = p.unset
+	}
+	return update
+}
+
+// overridesUpdate builds the update of a plan that only records overrides,
+// signed by the editor but without a new revision.
+func (p editPlan) overridesUpdate(editor models.Editor, now time.Time) bson.M {
+	set := bson.M{
+		"editorial.editedBy": editor,
+		"editorial.editedAt": now,
+	}
+	for name, value := range p.set {
+		set[name] = value
+	}
+
+	update := bson.M{"$set": set}
+	if len(p.unset) > 0 {
+		update["$unset"] = p.unset
+	}
+	return update
+}
+
+// empty reports whether the plan writes nothing. A plan without changes may
+// still record an override, which is written without a new revision.
+func (p editPlan) empty() bool {
+	return len(p.set) == 0 && len(p.unset) == 0
+}
+
+// CreateArticle stores a story written by an editor, then queues its event.
+// The request key is unique among stories, so a retried request finds the
+// story the first one stored and queues its event if that never happened.
+func (r *ArticleRepository) CreateArticle(ctx context.Context, article models.Article, editor models.Editor,
+	requestKey string, now time.Time) (*models.Article, error) {
+	r.metrics.DBCall("CreateArticle")
+
+	stored, err := r.createdBy(ctx, requestKey)
+	if err != nil {
+		r.metrics.DBErrorInc("CreateArticle", "find_error")
+		return nil, err
+	}
+
+	if stored == nil {
+		id, err := articledb.ReserveIDs(ctx, r.collection.Database(), 1)
+		if err != nil {
+			r.metrics.DBErrorInc("CreateArticle", "sequence_error")
+			return nil, err
+		}
+
+		article.ID = id
+		article.UpdatedAt = &now
+		article.Editorial = &models.Editorial{Manual: true, EditedBy: editor, EditedAt: now}
+		stored = &storedArticle{Article: article, Revision: 1, CreatedAt: &now, RequestKey: requestKey}
+		_, err = r.collection.InsertOne(ctx, stored)
+		if mongo.IsDuplicateKeyError(err) {
+			// A retry of the same request stored it first.
+			stored, err = r.createdBy(ctx, requestKey)
+		}
+		if err != nil || stored == nil {
+			r.metrics.DBErrorInc("CreateArticle", "insert_error")
+			return nil, errors.Wrap(err, "failed to create article")
+		}
+	}
+
+	if stored.QueuedRevision < 1 {
+		if err := r.queueRevision(ctx, "CreateArticle", stored.Article, 1, nil, now); err != nil {
+			return nil, err
+		}
+	}
+	return &stored.Article, nil
+}
+
+// createdBy finds the story a create request stored, if any.
+func (r *ArticleRepository) createdBy(ctx context.Context, requestKey string) (*storedArticle, error) {
+	var stored storedArticle
+	err := r.collection.FindOne(ctx, bson.M{"requestKey": requestKey}).Decode(&stored)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the created article")
+	}
+	return &stored, nil
+}
+
+func (r *ArticleRepository) EditArticle(ctx context.Context, id int, patch models.ArticlePatch, editor models.Editor,
+	now time.Time) (*models.Article, error) {
+	r.metrics.DBCall("EditArticle")
+
+	stored, err := r.getStored(ctx, "EditArticle", id)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planPatch(*stored, patch)
+	if plan.empty() {
+		return &stored.Article, nil
+	}
+	if len(plan.changes) == 0 {
+		// Only overrides of the current values; readers see no new revision.
+		return r.writeOverrides(ctx, stored, plan, editor, now)
+	}
+	return r.applyEdit(ctx, "EditArticle", stored, plan, editor, now)
+}
+
+func (r *ArticleRepository) HideArticle(ctx context.Context, id int, editor models.Editor, now time.Time) error {
+	r.metrics.DBCall("HideArticle")
+
+	stored, err := r.getStored(ctx, "HideArticle", id)
+	if err != nil {
+		return err
+	}
+	_, err = r.applyEdit(ctx, "HideArticle", stored, planHide(), editor, now)
+	return err
+}
+
+// getStored reads a visible article for editing.
+func (r *ArticleRepository) getStored(ctx context.Context, method string, id int) (*storedArticle, error) {
+	var stored storedArticle
+	err := r.collection.FindOne(ctx, visible(bson.M{"id": id})).Decode(&stored)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc(method, "not_found")
+			return nil, errors.Wrap(models.ErrNotFound, "article")
+		}
+		r.metrics.DBErrorInc(method, err.Error())
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// applyEdit keeps the replaced version in the revisions, then writes the next
+// revision of the article if it's still the revision the plan was made from,
+// and only then queues the event announcing it, so the relay never publishes
+// a revision that wasn't stored. The revision is keyed by article and
+// revision, so an edit retried after a failure writes it once. The worker
+// stores new versions from the feed the same way, so neither overwrites the
+// other. An event lost to a failure after the article was written is queued
+// by the next edit, as the article's queued revision stays behind.
+func (r *ArticleRepository) applyEdit(ctx context.Context, method string, stored *storedArticle, plan editPlan,
+	editor models.Editor, now time.Time) (*models.Article, error) {
+	if stored.QueuedRevision < stored.revision() {
+		if err := r.queueRevision(ctx, method, stored.Article, stored.revision(), nil, now); err != nil {
+			return nil, err
+		}
+	}
+
+	revision := models.ArticleRevision{
+		ArticleID:  stored.ID,
+		ExternalID: stored.ExternalID,
+		Revision:   stored.revision(),
+		Article:    stored.Article,
+		Changes:    plan.changes,
+		CreatedAt:  now,
+		EditedBy:   &editor,
+	}
+	_, err := r.revisions.UpdateOne(ctx,
+		bson.M{"articleID": revision.ArticleID, "revision": revision.Revision},
+		bson.M{"$setOnInsert": revision},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		r.metrics.DBErrorInc(method, "revision_error")
+		return nil, errors.Wrap(err, "failed to store article revision")
+	}
+
+	var edited models.Article
+	err = r.collection.FindOneAndUpdate(ctx,
+		bson.M{"id": stored.ID, "revision": stored.Revision},
+		plan.update(*stored, editor, now),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&edited)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc(method, "conflict")
+			r.withdraw(ctx, stored, now)
+			return nil, errors.Wrap(models.ErrConflict, "article changed while editing it")
+		}
+		r.metrics.DBErrorInc(method, "update_error")
+		return nil, errors.Wrap(err, "failed to edit article")
+	}
+
+	changed := make([]string, 0, len(plan.changes))
+	for _, change := range plan.changes {
+		changed = append(changed, change.Field)
+	}
+	if err := r.queueRevision(ctx, method, edited, stored.revision()+1, changed, now); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
+// withdraw removes the revision an edit stored before losing its revision to
+// another writer, which stores its own. It's kept once the other writer has
+// queued its event, as it found the revision in place.
+func (r *ArticleRepository) withdraw(ctx context.Context, stored *storedArticle, now time.Time) {
+	var current storedArticle
+	err := r.collection.FindOne(ctx, bson.M{"id": stored.ID}).Decode(&current)
+	if err != nil || current.QueuedRevision > stored.revision() {
+		return
+	}
+
+	_, err = r.revisions.DeleteOne(ctx, bson.M{
+		"articleID": stored.ID,
+		"revision":  stored.revision(),
+		"createdAt": now,
+	})
+	if err != nil {
+		r.metrics.DBErrorInc("withdrawEdit", err.Error())
+	}
+}
+
+// writeOverrides records overrides that change no value, guarded by the
+// revision like an edit.
+func (r *ArticleRepository) writeOverrides(ctx context.Context, stored *storedArticle, plan editPlan,
+	editor models.Editor, now time.Time) (*models.Article, error) {
+	var edited models.Article
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"id": stored.ID, "revision": stored.Revision},
+		plan.overridesUpdate(editor, now),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&edited)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc("EditArticle", "conflict")
+			return nil, errors.Wrap(models.ErrConflict, "article changed while editing it")
+		}
+		r.metrics.DBErrorInc("EditArticle", "update_error")
+		return nil, errors.Wrap(err, "failed to edit article")
+	}
+	return &edited, nil
+}
+
+// queueRevision queues the event of a stored article revision and records it
+// on the article as queued.
+func (r *ArticleRepository) queueRevision(ctx context.Context, method string, article models.Article, revision int,
+	changed []string, now time.Time) error {
+	eventType := articledb.EventUpdated
+	if revision == 1 {
+		eventType = articledb.EventCreated
+	}
+	if err := r.queueEvent(ctx, eventType, article, revision, changed, now); err != nil {
+		r.metrics.DBErrorInc(method, "outbox_error")
+		return err
+	}
+
+	filter, update := articledb.MarkQueued(article.ID, revision)
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		r.metrics.DBErrorInc(method, "queued_error")
+		return errors.Wrap(err, "failed to mark the article event queued")
+	}
+	return nil
+}
+
+// queueEvent adds the event of an article revision to the outbox, keyed like
+// the worker's so it's never queued twice.
+func (r *ArticleRepository) queueEvent(ctx context.Context, eventType string, article models.Article, revision int,
+	changed []string, now time.Time) error {
+	_, correlationID := cccorrelation.GetCorrelationId(ctx)
+	if changed == nil {
+		changed = []string{}
+	}
+
+	event := articledb.OutboxEvent[models.Tag]{
+		ID:   articledb.EventID(article.ID, revision),
+		Type: eventType,
+		Event: articledb.ArticleEvent[models.Tag]{
+			ID:            article.ID,
+			ExternalID:    article.ExternalID,
+			Revision:      revision,
+			ChangedFields: changed,
+			Source:        article.Source,
+			Tags:          article.Tags,
+		},
+		CorrelationID: correlationID,
+		CreatedAt:     now,
+	}
+	filter, update := event.Upsert()
+	_, err := r.outbox.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return errors.Wrap(err, "failed to queue the article event")
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestPlanPatch(t *testing.T) {
+	feed := storedArticle{
+		Article:  models.Article{ID: 5, Title: "Final", Summary: "Close game"},
+		Revision: 3,
+	}
+	overridden := storedArticle{
+		Article: models.Article{
+			ID:        5,
+			Title:     "Edited",
+			Summary:   "Close game",
+			Editorial: &models.Editorial{Overrides: map[string]string{"title": "Edited"}},
+		},
+		Revision: 4,
+		Upstream: map[string]string{"title": "Final (updated)"},
+	}
+	manual := storedArticle{
+		Article: models.Article{ID: 6, Title: "Story", Editorial: &models.Editorial{Manual: true}},
+	}
+
+	tests := []struct {
+		name    string
+		stored  storedArticle
+		patch   models.ArticlePatch
+		set     bson.M
+		unset   bson.M
+		changes []string
+	}{
+		{
+			name:   "first override keeps the feed's value",
+			stored: feed,
+			patch:  models.ArticlePatch{Title: stringPtr("Edited")},
+			set: bson.M{
+				"title":                     "Edited",
+				"editorial.overrides.title": "Edited",
+				"upstream.title":            "Final",
+			},
+			unset:   bson.M{},
+			changes: []string{"title"},
+		},
+		{
+			name:   "later override keeps the kept value",
+			stored: overridden,
+			patch:  models.ArticlePatch{Title: stringPtr("Edited again")},
+			set: bson.M{
+				"title":                     "Edited again",
+				"editorial.overrides.title": "Edited again",
+			},
+			unset:   bson.M{},
+			changes: []string{"title"},
+		},
+		{
+			name:   "override with the current value is no change",
+			stored: feed,
+			patch:  models.ArticlePatch{Summary: stringPtr("Close game")},
+			set: bson.M{
+				"editorial.overrides.summary": "Close game",
+				"upstream.summary":            "Close game",
+			},
+			unset: bson.M{},
+		},
+		{
+			name:   "revert restores the feed's value",
+			stored: overridden,
+			patch:  models.ArticlePatch{Revert: []string{"title", "summary"}},
+			set:    bson.M{"title": "Final (updated)"},
+			unset: bson.M{
+				"editorial.overrides.title": "",
+				"upstream.title":            "",
+			},
+			changes: []string{"title"},
+		},
+		{
+			name:    "stories written by editors have no overrides",
+			stored:  manual,
+			patch:   models.ArticlePatch{Title: stringPtr("Story (updated)"), Body: stringPtr("Story")},
+			set:     bson.M{"title": "Story (updated)", "body": "Story"},
+			unset:   bson.M{},
+			changes: []string{"body", "title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planPatch(tt.stored, tt.patch)
+
+			if !equalBSON(plan.set, tt.set) || !equalBSON(plan.unset, tt.unset) {
+				t.Errorf("unexpected update set %v unset %v", plan.set, plan.unset)
+			}
+			changed := map[string]bool{}
+			for _, change := range plan.changes {
+				changed[change.Field] = true
+			}
+			if len(changed) != len(tt.changes) {
+				t.Errorf("expected changes to %v, got %+v", tt.changes, plan.changes)
+			}
+			for _, field := range tt.changes {
+				if !changed[field] {
+					t.Errorf("expected %s to change, got %+v", field, plan.changes)
+				}
+			}
+		})
+	}
+}
+
+func TestEditPlan_Update(t *testing.T) {
+	now := time.Now()
+	editor := models.Editor{Subject: "ana@example.com", Method: models.AuthMethodJWT}
+	stored := storedArticle{Article: models.Article{ID: 5}, Revision: 3}
+
+	update := planHide().update(stored, editor, now)
+	set := update["$set"].(bson.M)
+	if set["revision"] != 4 || set["editorial.hidden"] != true || set["editorial.editedBy"] != editor ||
+		set["editorial.editedAt"] != now {
+		t.Errorf("unexpected update %v", update)
+	}
+	if _, ok := update["$unset"]; ok {
+		t.Errorf("expected no $unset, got %v", update)
+	}
+	if _, ok := update["$max"]; ok {
+		t.Errorf("expected the queued revision to be left to the event, got %v", update)
+	}
+}
+
+func TestEditPlan_OverridesUpdate(t *testing.T) {
+	now := time.Now()
+	editor := models.Editor{Subject: "ana@example.com", Method: models.AuthMethodJWT}
+	stored := storedArticle{Article: models.Article{ID: 5, Title: "Final"}, Revision: 3}
+
+	update := planPatch(stored, models.ArticlePatch{Title: stringPtr("Final")}).overridesUpdate(editor, now)
+	set := update["$set"].(bson.M)
+	if set["editorial.overrides.title"] != "Final" || set["editorial.editedBy"] != editor ||
+		set["editorial.editedAt"] != now {
+		t.Errorf("expected the override signed by the editor, got %v", update)
+	}
+	if _, ok := set["revision"]; ok {
+		t.Errorf("expected no new revision, got %v", update)
+	}
+}
+
+func equalBSON(a, b bson.M) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"regexp"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// visible matches the articles editors haven't hidden; every read adds it.
+func visible(query bson.M) bson.M {
+	query["editorial.hidden"] = bson.M{"$ne": true}
+	return query
+}
+
+// articleFilterQuery translates the listing filter into a query on the
+// visible article documents. Tag labels are stored as the source spells them,
+// so they are matched regardless of case.
+func articleFilterQuery(filter models.ArticleFilter) bson.M {
+	conditions := bson.A{visible(bson.M{})}
+
+	var tags bson.A
+	for _, id := range filter.TagIDs {
+		tags = append(tags, bson.M{"tags.id": id})
+	}
+	for _, label := range filter.TagLabels {
+		tags = append(tags, bson.M{"tags.label": labelQuery(label)})
+	}
+	switch {
+	case len(tags) == 1:
+		conditions = append(conditions, tags[0])
+	case len(tags) > 1 && filter.TagMatch == models.TagMatchAll:
+		conditions = append(conditions, tags...)
+	case len(tags) > 1:
+		conditions = append(conditions, bson.M{"$or": tags})
+	}
+
+	published := bson.M{}
+	if filter.From != nil {
+		published["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		published["$lte"] = *filter.To
+	}
+	if len(published) > 0 {
+		conditions = append(conditions, bson.M{"publishedAt": published})
+	}
+
+	if filter.MediaType != "" {
+		conditions = append(conditions, bson.M{"leadmedia.type": filter.MediaType})
+	}
+	if filter.Source != "" {
+		conditions = append(conditions, bson.M{"source": filter.Source})
+	}
+
+	return bson.M{"$and": conditions}
+}
+
+// labelQuery matches the whole label, ignoring case.
+func labelQuery(label string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(label) + "$", "$options": "i"}
+}
+
+// afterCursorQuery matches the articles sorting after the cursor by
+// publishedAt and then id, both descending. Undated articles sort last.
+func afterCursorQuery(after models.ArticleCursor) bson.M {
+	if after.PublishedAt == nil {
+		return bson.M{"publishedAt": nil, "id": bson.M{"$lt": after.ID}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"publishedAt": bson.M{"$lt": *after.PublishedAt}},
+		bson.M{"publishedAt": *after.PublishedAt, "id": bson.M{"$lt": after.ID}},
+		bson.M{"publishedAt": nil},
+	}}
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestArticleFilterQuery_MatchesLabelsRegardlessOfCase(t *testing.T) {
+	query := articleFilterQuery(models.ArticleFilter{TagLabels: []string{"Cricket (T20)"}})
+
+	conditions := query["$and"].(bson.A)
+	label := conditions[len(conditions)-1].(bson.M)["tags.label"].(bson.M)
+	if label["$options"] != "i" {
+		t.Fatalf("expected a case-insensitive match, got %v", label)
+	}
+
+	pattern := regexp.MustCompile("(?i)" + label["$regex"].(string))
+	for _, stored := range []string{"cricket (t20)", "Cricket (T20)", "CRICKET (T20)"} {
+		if !pattern.MatchString(stored) {
+			t.Errorf("expected %q to match", stored)
+		}
+	}
+	for _, stored := range []string{"Cricket", "Women's Cricket (T20)", "Cricket (T20) Final"} {
+		if pattern.MatchString(stored) {
+			t.Errorf("expected %q not to match", stored)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/api/internal/domain/models"
+	"github.com/ronnyp07/SportStream/api/internal/domain/ports/metrics"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	webhooksCollectionName   = "webhooks"
+	deliveriesCollectionName = "webhook_deliveries"
+)
+
+type WebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+	metrics    metrics.MetricsHandler
+}
+
+func NewWebhookRepository(db *mongo.Database, metrics metrics.MetricsHandler) *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:   db.Collection(webhooksCollectionName),
+		deliveries: db.Collection(deliveriesCollectionName),
+		metrics:    metrics,
+	}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	r.metrics.DBCall("CreateWebhook")
+
+	if _, err := r.webhooks.InsertOne(ctx, webhook); err != nil {
+		r.metrics.DBErrorInc("CreateWebhook", "insert_error")
+		return errors.Wrap(err, "failed to create webhook")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	r.metrics.DBCall("GetWebhook")
+
+	var webhook models.Webhook
+	err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			r.metrics.DBErrorInc("GetWebhook", "not_found")
+			return nil, errors.Wrap(models.ErrNotFound, "webhook")
+		}
+		r.metrics.DBErrorInc("GetWebhook", err.Error())
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, activeOnly bool) ([]models.Webhook, error) {
+	r.metrics.DBCall("ListWebhooks")
+
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		r.metrics.DBErrorInc("ListWebhooks", "find_error")
+		return nil, errors.Wrap(err, "failed to find webhooks")
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		r.metrics.DBErrorInc("ListWebhooks", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode webhooks")
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook removes the webhook and its delivery log.
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id string) error {
+	r.metrics.DBCall("DeleteWebhook")
+
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		r.metrics.DBErrorInc("DeleteWebhook", "delete_error")
+		return errors.Wrap(err, "failed to delete webhook")
+	}
+	if result.DeletedCount == 0 {
+		return errors.Wrap(models.ErrNotFound, "webhook")
+	}
+
+	if _, err := r.deliveries.DeleteMany(ctx, bson.M{"webhookID": id}); err != nil {
+		r.metrics.DBErrorInc("DeleteWebhook", "delete_deliveries_error")
+		return errors.Wrap(err, "failed to delete webhook deliveries")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnableWebhook(ctx context.Context, id string) error {
+	r.metrics.DBCall("EnableWebhook")
+
+	return r.updateWebhook(ctx, "EnableWebhook", id, bson.M{
+		"$set":   bson.M{"active": true, "consecutiveFailures": 0},
+		"$unset": bson.M{"disabledAt": "", "disabledReason": ""},
+	})
+}
+
+func (r *WebhookRepository) DisableWebhook(ctx context.Context, id string, reason string, at time.Time) error {
+	r.metrics.DBCall("DisableWebhook")
+
+	return r.updateWebhook(ctx, "DisableWebhook", id, bson.M{
+		"$set": bson.M{"active": false, "disabledAt": at, "disabledReason": reason},
+	})
+}
+
+func (r *WebhookRepository) RecordWebhookResult(ctx context.Context, id string, success bool) (int, error) {
+	r.metrics.DBCall("RecordWebhookResult")
+
+	update := bson.M{"$inc": bson.M{"consecutiveFailures": 1}}
+	if success {
+		update = bson.M{"$set": bson.M{"consecutiveFailures": 0}}
+	}
+
+	var webhook models.Webhook
+	err := r.webhooks.FindOneAndUpdate(ctx, bson.M{"_id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, errors.Wrap(models.ErrNotFound, "webhook")
+		}
+		r.metrics.DBErrorInc("RecordWebhookResult", "update_error")
+		return 0, errors.Wrap(err, "failed to record webhook result")
+	}
+	return webhook.ConsecutiveFailures, nil
+}
+
+func (r *WebhookRepository) updateWebhook(ctx context.Context, method string, id string, update bson.M) error {
+	result, err := r.webhooks.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		r.metrics.DBErrorInc(method, "update_error")
+		return errors.Wrap(err, "failed to update webhook")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(models.ErrNotFound, "webhook")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) InsertDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	r.metrics.DBCall("InsertDeliveries")
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		documents = append(documents, delivery)
+	}
+
+	// Unordered, so the deliveries queued by an earlier copy of the event
+	// don't stop the rest.
+	_, err := r.deliveries.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		r.metrics.DBErrorInc("InsertDeliveries", "insert_error")
+		return errors.Wrap(err, "failed to queue webhook deliveries")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ClaimDueDelivery(ctx context.Context, now time.Time,
+	lease time.Duration) (*models.WebhookDelivery, error) {
+	r.metrics.DBCall("ClaimDueDelivery")
+
+	filter := bson.M{"status": models.DeliveryPending, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		r.metrics.DBErrorInc("ClaimDueDelivery", "update_error")
+		return nil, errors.Wrap(err, "failed to claim webhook delivery")
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) RecordDeliveryAttempt(ctx context.Context, id string, attempt models.DeliveryAttempt,
+	status models.DeliveryStatus, nextAttemptAt *time.Time) error {
+	r.metrics.DBCall("RecordDeliveryAttempt")
+
+	set := bson.M{"status": status}
+	update := bson.M{"$push": bson.M{"attempts": attempt}}
+	if nextAttemptAt != nil {
+		set["nextAttemptAt"] = *nextAttemptAt
+	} else {
+		update["$unset"] = bson.M{"nextAttemptAt": ""}
+	}
+	if status == models.DeliveryDelivered {
+		set["deliveredAt"] = attempt.At
+	}
+	update["$set"] = set
+
+	if _, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		r.metrics.DBErrorInc("RecordDeliveryAttempt", "update_error")
+		return errors.Wrap(err, "failed to record webhook delivery attempt")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID string,
+	page, pageSize int) (*models.PaginatedDeliveries, error) {
+	r.metrics.DBCall("GetDeliveries")
+
+	filter := bson.M{"webhookID": webhookID}
+	total, err := r.deliveries.CountDocuments(ctx, filter)
+	if err != nil {
+		r.metrics.DBErrorInc("GetDeliveries", "count_error")
+		return nil, errors.Wrap(err, "failed to count webhook deliveries")
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize != 0 {
+		totalPages++
+	}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.deliveries.Find(ctx, filter, findOptions)
+	if err != nil {
+		r.metrics.DBErrorInc("GetDeliveries", "find_error")
+		return nil, errors.Wrap(err, "failed to find webhook deliveries")
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		r.metrics.DBErrorInc("GetDeliveries", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode webhook deliveries")
+	}
+
+	return &models.PaginatedDeliveries{
+		PageInfo: models.PageInfo{
+			Page:       page,
+			NumPages:   totalPages,
+			PageSize:   pageSize,
+			NumEntries: int(total),
+		},
+		Content: deliveries,
+	}, nil
+}
+
+// onlyDuplicateKeys reports whether every write in a bulk insert failed on a
+// duplicate key.
+func onlyDuplicateKeys(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
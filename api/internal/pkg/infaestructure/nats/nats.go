@@ -0,0 +1,42 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ronnyp07/SportStream/api/internal/pkg/infaestructure/log"
+	"github.com/sts-solutions/base-code/cclogger"
+)
+
+func Connect(ctx context.Context, host, port string, options ...nats.Option) (*nats.Conn, error) {
+	url := natsConnectionString(host, port)
+
+	options = append([]nats.Option{
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("nats client disconnected %v", err))
+			}
+		}),
+		nats.ReconnectHandler(func(_ *nats.Conn) {
+			log.Logger().Info(ctx, "nats client reconnected")
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			log.Logger().Info(ctx, "nats client closed")
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			log.Logger().Error(ctx, "nats client connected error: %v", cclogger.LogField{
+				Key:   "reason",
+				Value: err.Error(),
+			})
+		}),
+	}, options...)
+
+	return nats.Connect(url, options...)
+}
+
+func natsConnectionString(host, port string) string {
+	connectionString := fmt.Sprintf("nats://%s:%s", host, port)
+	return connectionString
+}
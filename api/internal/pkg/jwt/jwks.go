@@ -0,0 +1,252 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval bounds how often a token with an unknown key ID makes
+// the key set be fetched again.
+const minRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey is a public key and the algorithm tokens signed with it
+// must name. Only keys whose algorithm is known are kept.
+type verificationKey struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// KeySet holds the public keys of a JWKS, read from a file or fetched from an
+// http(s) URL.
+type KeySet struct {
+	source string
+	client *http.Client
+	onSkip func(error)
+
+	mu          sync.RWMutex
+	keys        map[string]verificationKey
+	lastRefresh time.Time
+}
+
+// LoadKeySet reads the JWKS at source, a file path or an http(s) URL. Keys
+// that can't verify tokens, such as EdDSA keys or keys on other curves, are
+// left out of the set and reported to onSkip.
+func LoadKeySet(ctx context.Context, source string, onSkip func(error)) (*KeySet, error) {
+	ks := &KeySet{
+		source: source,
+		client: &http.Client{Timeout: 10 * time.Second},
+		onSkip: onSkip,
+	}
+	if err := ks.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Refresh reads the key set again, keeping the current keys on failure or
+// when none of the new ones can verify tokens.
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	data, err := ks.read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading JWKS from %s: %w", ks.source, err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.verificationKey()
+		if err != nil {
+			if ks.onSkip != nil {
+				ks.onSkip(fmt.Errorf("skipping JWK %q: %w", k.Kid, err))
+			}
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS at %s has no supported signing keys", ks.source)
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.lastRefresh = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// StartRefresh refreshes a key set fetched from a URL every interval until
+// ctx is done, so rotated keys are picked up.
+func (ks *KeySet) StartRefresh(ctx context.Context, interval time.Duration, onError func(error)) {
+	if !ks.remote() || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ks.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// key returns the key with the ID, fetching the set again when it's unknown
+// and the set comes from a URL.
+func (ks *KeySet) key(ctx context.Context, kid string) (verificationKey, bool) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.lastRefresh) > minRefreshInterval
+	ks.mu.RUnlock()
+
+	if ok || !ks.remote() || !stale {
+		return key, ok
+	}
+	if err := ks.Refresh(ctx); err != nil {
+		return verificationKey{}, false
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok = ks.keys[kid]
+	return key, ok
+}
+
+func (ks *KeySet) remote() bool {
+	return strings.HasPrefix(ks.source, "http://") || strings.HasPrefix(ks.source, "https://")
+}
+
+func (ks *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !ks.remote() {
+		return os.ReadFile(ks.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// verificationKey decodes the key and pins the algorithm tokens signed with it
+// must name: the JWK's alg when it has one, otherwise the one its curve
+// implies for EC keys. RSA keys without an alg take any RS algorithm.
+func (k jwk) verificationKey() (verificationKey, error) {
+	if k.Alg != "" {
+		if _, ok := algorithms[k.Alg]; !ok {
+			return verificationKey{}, fmt.Errorf("unsupported algorithm %q", k.Alg)
+		}
+	}
+
+	key, curveAlg, err := k.publicKey()
+	if err != nil {
+		return verificationKey{}, err
+	}
+	switch {
+	case curveAlg == "":
+		if strings.HasPrefix(k.Alg, "ES") {
+			return verificationKey{}, fmt.Errorf("algorithm %q doesn't match key type %q", k.Alg, k.Kty)
+		}
+		return verificationKey{key: key, alg: k.Alg}, nil
+	case k.Alg != "" && k.Alg != curveAlg:
+		return verificationKey{}, fmt.Errorf("algorithm %q doesn't match curve %q", k.Alg, k.Crv)
+	default:
+		return verificationKey{key: key, alg: curveAlg}, nil
+	}
+}
+
+// publicKey decodes the key, along with the algorithm of its curve for EC
+// keys.
+func (k jwk) publicKey() (crypto.PublicKey, string, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, "", err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, "", err
+		}
+		if !e.IsInt64() {
+			return nil, "", fmt.Errorf("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, "", nil
+	case "EC":
+		var curve elliptic.Curve
+		var alg string
+		switch k.Crv {
+		case "P-256":
+			curve, alg = elliptic.P256(), "ES256"
+		case "P-384":
+			curve, alg = elliptic.P384(), "ES384"
+		default:
+			return nil, "", fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, "", err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, "", err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, alg, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,229 @@
+// Package jwt verifies signed JSON Web Tokens against a JWKS. Only the
+// asymmetric RS and ES algorithms are accepted.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed = errors.New("malformed token")
+	ErrSignature = errors.New("invalid token signature")
+	ErrExpired   = errors.New("token expired")
+	ErrClaims    = errors.New("invalid token claims")
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type algorithm struct {
+	hash crypto.Hash
+	// size is the byte length of each of r and s in an ECDSA signature.
+	size int
+}
+
+var algorithms = map[string]algorithm{
+	"RS256": {hash: crypto.SHA256},
+	"RS384": {hash: crypto.SHA384},
+	"RS512": {hash: crypto.SHA512},
+	"ES256": {hash: crypto.SHA256, size: 32},
+	"ES384": {hash: crypto.SHA384, size: 48},
+}
+
+// Claims are the decoded claims of a verified token.
+type Claims map[string]interface{}
+
+func (c Claims) Subject() string {
+	subject, _ := c["sub"].(string)
+	return subject
+}
+
+// Strings reads a claim holding a string, a space separated list such as
+// scope, or an array of strings.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier checks tokens signed by the key set. An empty issuer or
+// audience isn't checked.
+func NewVerifier(keys *KeySet, issuer string, audience string, leeway time.Duration) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		leeway:   leeway,
+		now:      time.Now,
+	}
+}
+
+// WithClock replaces the verifier's clock, for tests.
+func (v *Verifier) WithClock(now func() time.Time) *Verifier {
+	v.now = now
+	return v
+}
+
+// Verify checks the token's signature, lifetime, issuer and audience and
+// returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+	alg, ok := algorithms[h.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrSignature, h.Alg)
+	}
+	key, ok := v.keys.key(ctx, h.Kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrSignature, h.Kid)
+	}
+	if key.alg != "" && key.alg != h.Alg {
+		return nil, fmt.Errorf("%w: key %q is for %s, not %s", ErrSignature, h.Kid, key.alg, h.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if err := verifySignature(alg, key.key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) checkClaims(claims Claims) error {
+	now := v.now()
+
+	exp, ok := numericDate(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrClaims)
+	}
+	if now.After(exp.Add(v.leeway)) {
+		return ErrExpired
+	}
+	if nbf, ok := numericDate(claims, "nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return fmt.Errorf("%w: not valid yet", ErrClaims)
+	}
+
+	if v.issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != v.issuer {
+			return fmt.Errorf("%w: issuer %q", ErrClaims, issuer)
+		}
+	}
+	if v.audience != "" && !contains(claims.Strings("aud"), v.audience) {
+		return fmt.Errorf("%w: audience", ErrClaims)
+	}
+	return nil
+}
+
+func verifySignature(alg algorithm, key crypto.PublicKey, signed string, signature []byte) error {
+	digest := hashOf(alg.hash, signed)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if alg.size != 0 || rsa.VerifyPKCS1v15(key, alg.hash, digest, signature) != nil {
+			return ErrSignature
+		}
+	case *ecdsa.PublicKey:
+		if alg.size == 0 || len(signature) != 2*alg.size {
+			return ErrSignature
+		}
+		r := new(big.Int).SetBytes(signature[:alg.size])
+		s := new(big.Int).SetBytes(signature[alg.size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return ErrSignature
+		}
+	default:
+		return ErrSignature
+	}
+	return nil
+}
+
+// hashOf digests data; the hash packages are imported for their side effect
+// of registering with crypto.
+func hashOf(hash crypto.Hash, data string) []byte {
+	h := hash.New()
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func numericDate(claims Claims, name string) (time.Time, bool) {
+	number, ok := claims[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,303 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/api/internal/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type testKeys struct {
+	rsa  *rsa.PrivateKey
+	ec   *ecdsa.PrivateKey
+	jwks []byte
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa-1", "use": "sig",
+				"n": encode(rsaKey.N.Bytes()),
+				"e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec-1", "crv": "P-256",
+				"x": encode(ecKey.X.FillBytes(make([]byte, 32))),
+				"y": encode(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return testKeys{rsa: rsaKey, ec: ecKey, jwks: jwks}
+}
+
+func (k testKeys) sign(t *testing.T, alg string, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := encode(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "editor@example.com",
+		"iss":   "https://auth.example.com/",
+		"aud":   []string{"sportstream-api"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"roles": []string{"editor"},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keys.jwks, 0o600))
+
+	keySet, err := jwt.LoadKeySet(context.Background(), path, nil)
+	require.NoError(t, err)
+	verifier := jwt.NewVerifier(keySet, "https://auth.example.com/", "sportstream-api", time.Minute).
+		WithClock(func() time.Time { return now })
+
+	with := func(name string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		claims[name] = value
+		return claims
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError error
+	}{
+		{name: "RS256", token: keys.sign(t, "RS256", "rsa-1", validClaims())},
+		{name: "ES256", token: keys.sign(t, "ES256", "ec-1", validClaims())},
+		{
+			name:  "expired within leeway",
+			token: keys.sign(t, "RS256", "rsa-1", with("exp", now.Add(-30*time.Second).Unix())),
+		},
+		{
+			name:          "expired",
+			token:         keys.sign(t, "RS256", "rsa-1", with("exp", now.Add(-time.Hour).Unix())),
+			expectedError: jwt.ErrExpired,
+		},
+		{
+			name:          "not valid yet",
+			token:         keys.sign(t, "RS256", "rsa-1", with("nbf", now.Add(time.Hour).Unix())),
+			expectedError: jwt.ErrClaims,
+		},
+		{
+			name:          "other audience",
+			token:         keys.sign(t, "RS256", "rsa-1", with("aud", "other-api")),
+			expectedError: jwt.ErrClaims,
+		},
+		{
+			name:          "other issuer",
+			token:         keys.sign(t, "RS256", "rsa-1", with("iss", "https://evil.example.com/")),
+			expectedError: jwt.ErrClaims,
+		},
+		{
+			name:          "unknown key",
+			token:         keys.sign(t, "RS256", "rsa-2", validClaims()),
+			expectedError: jwt.ErrSignature,
+		},
+		{
+			name:          "key of another algorithm",
+			token:         keys.sign(t, "ES256", "rsa-1", validClaims()),
+			expectedError: jwt.ErrSignature,
+		},
+		{
+			name:          "unsigned",
+			token:         keys.sign(t, "none", "rsa-1", validClaims()),
+			expectedError: jwt.ErrSignature,
+		},
+		{
+			name:          "tampered claims",
+			token:         keys.sign(t, "RS256", "rsa-1", validClaims())[:20] + "x" + keys.sign(t, "RS256", "rsa-1", validClaims())[21:],
+			expectedError: jwt.ErrMalformed,
+		},
+		{
+			name:          "malformed",
+			token:         "not-a-token",
+			expectedError: jwt.ErrMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "editor@example.com", claims.Subject())
+			assert.Equal(t, []string{"editor"}, claims.Strings("roles"))
+		})
+	}
+}
+
+func TestLoadKeySet_URL(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(keys.jwks)
+	}))
+	defer server.Close()
+
+	keySet, err := jwt.LoadKeySet(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+
+	claims, err := jwt.NewVerifier(keySet, "", "", 0).
+		WithClock(func() time.Time { return now }).
+		Verify(context.Background(), keys.sign(t, "ES256", "ec-1", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "editor@example.com", claims.Subject())
+}
+
+// withKeys returns the test JWKS with the extra keys added, the RSA and EC
+// test keys under further IDs among them.
+func (k testKeys) withKeys(t *testing.T, extra ...map[string]string) []byte {
+	t.Helper()
+
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(k.jwks, &set))
+	for _, key := range extra {
+		switch key["kty"] {
+		case "RSA":
+			key["n"], key["e"] = set.Keys[0]["n"], set.Keys[0]["e"]
+		case "EC":
+			key["x"], key["y"] = set.Keys[1]["x"], set.Keys[1]["y"]
+		}
+		set.Keys = append(set.Keys, key)
+	}
+	jwks, err := json.Marshal(set)
+	require.NoError(t, err)
+	return jwks
+}
+
+func TestLoadKeySet_SkipsUnsupportedKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keys.withKeys(t,
+		map[string]string{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		map[string]string{"kty": "EC", "kid": "ec-521", "crv": "P-521"},
+		map[string]string{"kty": "RSA", "kid": "rsa-pss", "alg": "PS256"},
+		map[string]string{"kty": "EC", "kid": "ec-mismatch", "crv": "P-256", "alg": "ES384"},
+	), 0o600))
+
+	var skipped []string
+	keySet, err := jwt.LoadKeySet(context.Background(), path, func(err error) {
+		skipped = append(skipped, err.Error())
+	})
+	require.NoError(t, err)
+	assert.Len(t, skipped, 4)
+
+	_, err = jwt.NewVerifier(keySet, "", "", 0).
+		WithClock(func() time.Time { return now }).
+		Verify(context.Background(), keys.sign(t, "RS256", "rsa-1", validClaims()))
+	assert.NoError(t, err)
+}
+
+func TestLoadKeySet_NoSupportedKeys(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"keys":[{"kty":"OKP","kid":"ed-1","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`),
+		0o600))
+
+	_, err := jwt.LoadKeySet(context.Background(), path, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifier_PinsKeyAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keys.withKeys(t,
+		map[string]string{"kty": "RSA", "kid": "rsa-256", "alg": "RS256"},
+		map[string]string{"kty": "RSA", "kid": "rsa-512", "alg": "RS512"},
+	), 0o600))
+	keySet, err := jwt.LoadKeySet(context.Background(), path, nil)
+	require.NoError(t, err)
+	verifier := jwt.NewVerifier(keySet, "", "", 0).WithClock(func() time.Time { return now })
+
+	tests := []struct {
+		name          string
+		kid           string
+		expectedError error
+	}{
+		{name: "key's algorithm", kid: "rsa-256"},
+		{name: "key without an algorithm", kid: "rsa-1"},
+		{name: "other algorithm", kid: "rsa-512", expectedError: jwt.ErrSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := verifier.Verify(context.Background(), keys.sign(t, "RS256", tt.kid, validClaims()))
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type frame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+// peer is the client end of a connection, reading the server's frames as
+// they arrive; net.Pipe doesn't buffer, so a server write blocks until then.
+type peer struct {
+	conn   net.Conn
+	frames chan frame
+}
+
+func newPipe(t *testing.T) (*Conn, *peer) {
+	t.Helper()
+
+	server, client := net.Pipe()
+	p := &peer{conn: client, frames: make(chan frame, 16)}
+	go p.readFrames()
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+
+	return &Conn{conn: server, reader: bufio.NewReader(server)}, p
+}
+
+// send writes the frames without waiting on the server to read them.
+func (p *peer) send(frames ...[]byte) {
+	go func() {
+		for _, f := range frames {
+			if _, err := p.conn.Write(f); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func (p *peer) readFrames() {
+	defer close(p.frames)
+	reader := bufio.NewReader(p.conn)
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			return
+		}
+		if header[1]&0x80 != 0 {
+			// Servers never mask; a masked frame fails the test below.
+			p.frames <- frame{opcode: 0xFF}
+			return
+		}
+
+		length := uint64(header[1] & 0x7F)
+		switch length {
+		case 126:
+			var extended [2]byte
+			if _, err := io.ReadFull(reader, extended[:]); err != nil {
+				return
+			}
+			length = uint64(binary.BigEndian.Uint16(extended[:]))
+		case 127:
+			var extended [8]byte
+			if _, err := io.ReadFull(reader, extended[:]); err != nil {
+				return
+			}
+			length = binary.BigEndian.Uint64(extended[:])
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return
+		}
+		p.frames <- frame{fin: header[0]&0x80 != 0, opcode: header[0] & 0x0F, payload: payload}
+	}
+}
+
+// next returns the server's next frame, or fails when the connection closed
+// without one.
+func (p *peer) next(t *testing.T) frame {
+	t.Helper()
+	f, ok := <-p.frames
+	require.True(t, ok, "expected a frame from the server")
+	return f
+}
+
+// clientFrame encodes a frame as a client sends it, masked.
+func clientFrame(fin bool, opcode byte, payload []byte) []byte {
+	f := header(fin, opcode, uint64(len(payload)), true)
+	mask := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+	f = append(f, mask[:]...)
+	for i, b := range payload {
+		f = append(f, b^mask[i%4])
+	}
+	return f
+}
+
+func header(fin bool, opcode byte, length uint64, masked bool) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+
+	switch {
+	case length < 126:
+		return []byte{first, maskBit | byte(length)}
+	case length <= 0xFFFF:
+		h := []byte{first, maskBit | 126, 0, 0}
+		binary.BigEndian.PutUint16(h[2:], uint16(length))
+		return h
+	default:
+		h := []byte{first, maskBit | 127, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(h[2:], length)
+		return h
+	}
+}
+
+func closeCode(t *testing.T, f frame) uint16 {
+	t.Helper()
+	require.Equal(t, byte(opClose), f.opcode)
+	require.GreaterOrEqual(t, len(f.payload), 2)
+	return binary.BigEndian.Uint16(f.payload)
+}
+
+func TestConn_WriteText(t *testing.T) {
+	t.Parallel()
+
+	// The lengths around each of the three length encodings.
+	for _, length := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		conn, client := newPipe(t)
+		payload := bytes.Repeat([]byte("a"), length)
+
+		go conn.WriteText(payload)
+
+		f := client.next(t)
+		assert.True(t, f.fin, "length %d", length)
+		assert.Equal(t, byte(opText), f.opcode, "length %d", length)
+		assert.Equal(t, payload, f.payload, "length %d", length)
+	}
+}
+
+func TestConn_ReadMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		frames [][]byte
+		want   []byte
+	}{
+		{
+			name:   "single frame",
+			frames: [][]byte{clientFrame(true, opText, []byte("hello"))},
+			want:   []byte("hello"),
+		},
+		{
+			name:   "empty frame",
+			frames: [][]byte{clientFrame(true, opBinary, nil)},
+			want:   []byte{},
+		},
+		{
+			name: "fragments joined",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(false, opContinuation, []byte("lo ")),
+				clientFrame(true, opContinuation, []byte("world")),
+			},
+			want: []byte("hello world"),
+		},
+		{
+			name: "pong between fragments",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(true, opPong, nil),
+				clientFrame(true, opContinuation, []byte("lo")),
+			},
+			want: []byte("hello"),
+		},
+		{
+			name:   "long frame",
+			frames: [][]byte{clientFrame(true, opBinary, bytes.Repeat([]byte("a"), maxFramePayload))},
+			want:   bytes.Repeat([]byte("a"), maxFramePayload),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conn, client := newPipe(t)
+			client.send(tt.frames...)
+
+			got, err := conn.ReadMessage()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConn_ReadMessageAnswersPings(t *testing.T) {
+	t.Parallel()
+	conn, client := newPipe(t)
+	client.send(
+		clientFrame(false, opText, []byte("hel")),
+		clientFrame(true, opPing, []byte("are you there")),
+		clientFrame(true, opContinuation, []byte("lo")),
+	)
+
+	got, err := conn.ReadMessage()
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), got)
+	pong := client.next(t)
+	assert.Equal(t, byte(opPong), pong.opcode)
+	assert.Equal(t, []byte("are you there"), pong.payload)
+}
+
+func TestConn_ReadMessageRejects(t *testing.T) {
+	t.Parallel()
+
+	unmasked := append(header(true, opText, 2, false), "hi"...)
+	reserved := clientFrame(true, opText, []byte("hi"))
+	reserved[0] |= 0x40
+
+	tests := []struct {
+		name     string
+		frames   [][]byte
+		wantErr  error
+		wantCode uint16
+	}{
+		{
+			name:     "unmasked frame",
+			frames:   [][]byte{unmasked},
+			wantErr:  ErrUnmasked,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name:     "oversized frame",
+			frames:   [][]byte{header(true, opBinary, maxFramePayload+1, true)},
+			wantErr:  ErrFrameTooLong,
+			wantCode: CloseTooBig,
+		},
+		{
+			name: "oversized message",
+			frames: [][]byte{
+				clientFrame(false, opBinary, bytes.Repeat([]byte("a"), maxFramePayload)),
+				clientFrame(true, opContinuation, []byte("a")),
+			},
+			wantErr:  ErrFrameTooLong,
+			wantCode: CloseTooBig,
+		},
+		{
+			name:     "continuation without a message",
+			frames:   [][]byte{clientFrame(true, opContinuation, []byte("lo"))},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name: "new message before the last one ended",
+			frames: [][]byte{
+				clientFrame(false, opText, []byte("hel")),
+				clientFrame(true, opText, []byte("lo")),
+			},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name:     "fragmented ping",
+			frames:   [][]byte{clientFrame(false, opPing, nil)},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name:     "long ping",
+			frames:   [][]byte{clientFrame(true, opPing, bytes.Repeat([]byte("a"), maxControlPayload+1))},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name:     "reserved bits",
+			frames:   [][]byte{reserved},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+		{
+			name:     "unknown opcode",
+			frames:   [][]byte{clientFrame(true, 0x3, nil)},
+			wantErr:  ErrProtocol,
+			wantCode: CloseProtocolError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conn, client := newPipe(t)
+			client.send(tt.frames...)
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := conn.ReadMessage()
+				done <- err
+			}()
+
+			assert.Equal(t, tt.wantCode, closeCode(t, client.next(t)))
+			assert.ErrorIs(t, <-done, tt.wantErr)
+		})
+	}
+}
+
+func TestConn_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("client closes", func(t *testing.T) {
+		t.Parallel()
+		conn, client := newPipe(t)
+		client.send(clientFrame(true, opClose, []byte{0x03, 0xE9}))
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := conn.ReadMessage()
+			done <- err
+		}()
+
+		assert.Equal(t, uint16(CloseNormal), closeCode(t, client.next(t)))
+		assert.ErrorIs(t, <-done, io.EOF)
+
+		// The close was answered already, so closing sends nothing more.
+		require.NoError(t, conn.Close(CloseGoingAway, "stream ended"))
+		_, ok := <-client.frames
+		assert.False(t, ok, "expected no second close frame")
+		assert.ErrorIs(t, conn.WriteText([]byte("late")), net.ErrClosed)
+	})
+
+	t.Run("server closes", func(t *testing.T) {
+		t.Parallel()
+		conn, client := newPipe(t)
+
+		go conn.Close(CloseGoingAway, "stream ended")
+
+		f := client.next(t)
+		assert.Equal(t, uint16(CloseGoingAway), closeCode(t, f))
+		assert.Equal(t, []byte("stream ended"), f.payload[2:])
+	})
+}
+
+func TestConn_Ping(t *testing.T) {
+	t.Parallel()
+	conn, client := newPipe(t)
+
+	go conn.Ping()
+
+	f := client.next(t)
+	assert.True(t, f.fin)
+	assert.Equal(t, byte(opPing), f.opcode)
+	assert.Empty(t, f.payload)
+}
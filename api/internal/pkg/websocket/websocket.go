@@ -0,0 +1,323 @@
+// Package websocket implements the server side of RFC 6455 as far as the
+// article stream needs it: the handshake from allowed origins, unfragmented
+// text frames out, messages in, fragmented or not, and the control frames in
+// both directions.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	// maxFramePayload bounds what a client may send in a message, however
+	// it's fragmented; the stream expects nothing but control frames from it.
+	maxFramePayload = 64 << 10
+	// maxControlPayload is the longest payload a control frame may carry.
+	maxControlPayload = 125
+
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseTooBig        = 1009
+	CloseInternalError = 1011
+)
+
+var (
+	ErrNotWebSocket     = errors.New("not a websocket handshake")
+	ErrOriginNotAllowed = errors.New("websocket origin not allowed")
+	ErrFrameTooLong     = errors.New("websocket frame too long")
+	ErrUnmasked         = errors.New("websocket client frame is not masked")
+	ErrProtocol         = errors.New("websocket protocol error")
+)
+
+// Conn is an upgraded connection. Writes are safe for concurrent use; reads
+// must come from a single goroutine.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+	closed bool
+}
+
+// Upgrader takes over connections whose handshake comes from an allowed
+// origin: the server's own, or one of the allowed ones. Requests without an
+// Origin header don't come from a browser and are let through; "*" allows
+// every origin.
+type Upgrader struct {
+	origins   map[string]bool
+	anyOrigin bool
+}
+
+func NewUpgrader(allowedOrigins []string) *Upgrader {
+	u := &Upgrader{origins: make(map[string]bool, len(allowedOrigins))}
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			u.anyOrigin = true
+			continue
+		}
+		u.origins[normalizeOrigin(origin)] = true
+	}
+	return u
+}
+
+// CheckOrigin rejects handshakes a browser sent from a page on another origin,
+// which would otherwise ride on the user's credentials.
+func (u *Upgrader) CheckOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || u.anyOrigin || u.origins[normalizeOrigin(origin)] {
+		return nil
+	}
+	if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOriginNotAllowed, origin)
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
+
+// Upgrade answers the opening handshake and takes over the connection.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return nil, ErrNotWebSocket
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrNotWebSocket, r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("%w: missing key", ErrNotWebSocket)
+	}
+	if err := u.CheckOrigin(r); err != nil {
+		return nil, err
+	}
+
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijacking connection: %w", err)
+	}
+	// The server's read and write timeouts don't apply to a long-lived stream.
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: netConn, reader: rw.Reader}, nil
+}
+
+// AcceptKey derives the Sec-WebSocket-Accept value for a client key.
+func AcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// Close sends a close frame with the status code, unless one was sent
+// already, and closes the connection.
+func (c *Conn) Close(code uint16, reason string) error {
+	writeErr := c.writeClose(code, reason)
+	closeErr := c.conn.Close()
+	if writeErr != nil && !errors.Is(writeErr, net.ErrClosed) {
+		return writeErr
+	}
+	return closeErr
+}
+
+// ReadMessage returns the next message from the client, joining its
+// fragments and answering pings on the way. It returns io.EOF once the client
+// closes the connection, after answering its close frame. A client breaking
+// the protocol is sent a close frame saying so.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	message, err := c.readMessage()
+	switch {
+	case errors.Is(err, ErrFrameTooLong):
+		c.writeClose(CloseTooBig, "")
+	case errors.Is(err, ErrProtocol), errors.Is(err, ErrUnmasked):
+		c.writeClose(CloseProtocolError, "")
+	}
+	return message, err
+}
+
+func (c *Conn) readMessage() ([]byte, error) {
+	var message []byte
+	fragmented := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			c.writeClose(CloseNormal, "")
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+			if fragmented == (opcode != opContinuation) {
+				return nil, fmt.Errorf("%w: unexpected opcode %#x", ErrProtocol, opcode)
+			}
+			if len(message)+len(payload) > maxFramePayload {
+				return nil, ErrFrameTooLong
+			}
+			message = append(message, payload...)
+			if fin {
+				if message == nil {
+					message = []byte{}
+				}
+				return message, nil
+			}
+			fragmented = true
+		default:
+			return nil, fmt.Errorf("%w: unknown opcode %#x", ErrProtocol, opcode)
+		}
+	}
+}
+
+// writeClose sends the close frame once; the connection takes no data frames
+// after it.
+func (c *Conn) writeClose(code uint16, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	return c.writeFrame(opClose, payload)
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch length := len(payload); {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+	if opcode == opClose {
+		c.closed = true
+	}
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		// No extension is negotiated, so the reserved bits stay clear.
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", ErrProtocol)
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, ErrUnmasked
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opcode&0x8 != 0 && (!fin || length > maxControlPayload) {
+		return false, 0, nil, fmt.Errorf("%w: fragmented or long control frame", ErrProtocol)
+	}
+	if length > maxFramePayload {
+		return false, 0, nil, ErrFrameTooLong
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package websocket_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ronnyp07/SportStream/api/internal/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	t.Parallel()
+
+	// The example handshake from RFC 6455, section 1.3.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", websocket.AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestUpgrader_CheckOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		wantErr bool
+	}{
+		{name: "no origin header", origin: ""},
+		{name: "the api's own origin", origin: "https://api.example.com"},
+		{name: "listed origin", allowed: []string{"https://dashboard.example.com/"}, origin: "https://Dashboard.example.com"},
+		{name: "any origin", allowed: []string{"*"}, origin: "https://evil.example.net"},
+		{name: "other origin", allowed: []string{"https://dashboard.example.com"}, origin: "https://evil.example.net", wantErr: true},
+		{name: "other origin without a list", origin: "https://evil.example.net", wantErr: true},
+		{name: "own host on another port", origin: "https://api.example.com:8443", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "https://api.example.com/api/v1/articles/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+
+			err := websocket.NewUpgrader(tt.allowed).CheckOrigin(r)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, websocket.ErrOriginNotAllowed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpgrader_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	upgrader := websocket.NewUpgrader([]string{"https://dashboard.example.com"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		conn.WriteText([]byte("hi"))
+		conn.Close(websocket.CloseNormal, "")
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+	}{
+		{name: "allowed origin", origin: "https://dashboard.example.com", wantStatus: http.StatusSwitchingProtocols},
+		{name: "other origin", origin: "https://evil.example.net", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			request := "GET / HTTP/1.1\r\n" +
+				"Host: " + server.Listener.Addr().String() + "\r\n" +
+				"Connection: Upgrade\r\n" +
+				"Upgrade: websocket\r\n" +
+				"Sec-WebSocket-Version: 13\r\n" +
+				"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+				"Origin: " + tt.origin + "\r\n\r\n"
+			_, err = conn.Write([]byte(request))
+			require.NoError(t, err)
+
+			reader := bufio.NewReader(conn)
+			response, err := http.ReadResponse(reader, nil)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+			if tt.wantStatus != http.StatusSwitchingProtocols {
+				return
+			}
+			assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", response.Header.Get("Sec-WebSocket-Accept"))
+			message := make([]byte, 4)
+			_, err = io.ReadFull(reader, message)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x81, 0x02, 'h', 'i'}, message)
+		})
+	}
+}
@@ -7,6 +7,7 @@ package repomocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/ronnyp07/SportStream/api/internal/domain/models"
@@ -35,6 +36,66 @@ func (m *MockIArticlesRepos) EXPECT() *MockIArticlesReposMockRecorder {
 	return m.recorder
 }
 
+// CountArticles mocks base method.
+func (m *MockIArticlesRepos) CountArticles(ctx context.Context, filter models.ArticleFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountArticles", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountArticles indicates an expected call of CountArticles.
+func (mr *MockIArticlesReposMockRecorder) CountArticles(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountArticles", reflect.TypeOf((*MockIArticlesRepos)(nil).CountArticles), ctx, filter)
+}
+
+// CreateArticle mocks base method.
+func (m *MockIArticlesRepos) CreateArticle(ctx context.Context, article models.Article, editor models.Editor, requestKey string, now time.Time) (*models.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateArticle", ctx, article, editor, requestKey, now)
+	ret0, _ := ret[0].(*models.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateArticle indicates an expected call of CreateArticle.
+func (mr *MockIArticlesReposMockRecorder) CreateArticle(ctx, article, editor, requestKey, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArticle", reflect.TypeOf((*MockIArticlesRepos)(nil).CreateArticle), ctx, article, editor, requestKey, now)
+}
+
+// EditArticle mocks base method.
+func (m *MockIArticlesRepos) EditArticle(ctx context.Context, id int, patch models.ArticlePatch, editor models.Editor, now time.Time) (*models.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditArticle", ctx, id, patch, editor, now)
+	ret0, _ := ret[0].(*models.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditArticle indicates an expected call of EditArticle.
+func (mr *MockIArticlesReposMockRecorder) EditArticle(ctx, id, patch, editor, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditArticle", reflect.TypeOf((*MockIArticlesRepos)(nil).EditArticle), ctx, id, patch, editor, now)
+}
+
+// GetArticlesAfter mocks base method.
+func (m *MockIArticlesRepos) GetArticlesAfter(ctx context.Context, after *models.ArticleCursor, limit int, filter models.ArticleFilter) ([]models.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArticlesAfter", ctx, after, limit, filter)
+	ret0, _ := ret[0].([]models.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArticlesAfter indicates an expected call of GetArticlesAfter.
+func (mr *MockIArticlesReposMockRecorder) GetArticlesAfter(ctx, after, limit, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticlesAfter", reflect.TypeOf((*MockIArticlesRepos)(nil).GetArticlesAfter), ctx, after, limit, filter)
+}
+
 // GetByExternalID mocks base method.
 func (m *MockIArticlesRepos) GetByExternalID(ctx context.Context, externalID int) (*models.Article, error) {
 	m.ctrl.T.Helper()
@@ -66,16 +127,75 @@ func (mr *MockIArticlesReposMockRecorder) GetByID(ctx, id interface{}) *gomock.C
 }
 
 // GetPaginatedArticles mocks base method.
-func (m *MockIArticlesRepos) GetPaginatedArticles(ctx context.Context, page, pageSize int) (*models.PaginatedArticles, error) {
+func (m *MockIArticlesRepos) GetPaginatedArticles(ctx context.Context, page, pageSize int, filter models.ArticleFilter) (*models.PaginatedArticles, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPaginatedArticles", ctx, page, pageSize)
+	ret := m.ctrl.Call(m, "GetPaginatedArticles", ctx, page, pageSize, filter)
 	ret0, _ := ret[0].(*models.PaginatedArticles)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPaginatedArticles indicates an expected call of GetPaginatedArticles.
-func (mr *MockIArticlesReposMockRecorder) GetPaginatedArticles(ctx, page, pageSize interface{}) *gomock.Call {
+func (mr *MockIArticlesReposMockRecorder) GetPaginatedArticles(ctx, page, pageSize, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaginatedArticles", reflect.TypeOf((*MockIArticlesRepos)(nil).GetPaginatedArticles), ctx, page, pageSize, filter)
+}
+
+// GetRevision mocks base method.
+func (m *MockIArticlesRepos) GetRevision(ctx context.Context, id, revision int) (*models.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, revision)
+	ret0, _ := ret[0].(*models.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockIArticlesReposMockRecorder) GetRevision(ctx, id, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockIArticlesRepos)(nil).GetRevision), ctx, id, revision)
+}
+
+// GetRevisions mocks base method.
+func (m *MockIArticlesRepos) GetRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, id)
+	ret0, _ := ret[0].([]models.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockIArticlesReposMockRecorder) GetRevisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockIArticlesRepos)(nil).GetRevisions), ctx, id)
+}
+
+// HideArticle mocks base method.
+func (m *MockIArticlesRepos) HideArticle(ctx context.Context, id int, editor models.Editor, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideArticle", ctx, id, editor, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HideArticle indicates an expected call of HideArticle.
+func (mr *MockIArticlesReposMockRecorder) HideArticle(ctx, id, editor, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideArticle", reflect.TypeOf((*MockIArticlesRepos)(nil).HideArticle), ctx, id, editor, now)
+}
+
+// SearchArticles mocks base method.
+func (m *MockIArticlesRepos) SearchArticles(ctx context.Context, query string, page, pageSize int) (*models.SearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchArticles", ctx, query, page, pageSize)
+	ret0, _ := ret[0].(*models.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchArticles indicates an expected call of SearchArticles.
+func (mr *MockIArticlesReposMockRecorder) SearchArticles(ctx, query, page, pageSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaginatedArticles", reflect.TypeOf((*MockIArticlesRepos)(nil).GetPaginatedArticles), ctx, page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchArticles", reflect.TypeOf((*MockIArticlesRepos)(nil).SearchArticles), ctx, query, page, pageSize)
 }
@@ -0,0 +1,99 @@
+// Package articledb is the part of the article collections both the worker
+// and the api write: the collection names, the counter article IDs are taken
+// from, and the outbox events announcing article revisions. The worker owns
+// the schema; the api writes the stories and edits of editors through this
+// package, so both key and shape the documents the same way.
+package articledb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ArticlesCollection  = "articles"
+	RevisionsCollection = "article_revisions"
+	OutboxCollection    = "article_outbox"
+	CountersCollection  = "article_counters"
+)
+
+// The types of the events in the outbox.
+const (
+	EventCreated = "article.created"
+	EventUpdated = "article.updated"
+)
+
+// ArticleEvent announces that an article was stored, as created or updated.
+// T is the tag type of the component writing it.
+type ArticleEvent[T any] struct {
+	ID            int      `json:"id" bson:"id"`
+	ExternalID    int      `json:"externalID" bson:"externalID"`
+	Revision      int      `json:"revision" bson:"revision"`
+	ChangedFields []string `json:"changedFields" bson:"changedFields"`
+	Source        string   `json:"source" bson:"source"`
+	Tags          []T      `json:"tags" bson:"tags"`
+}
+
+// OutboxEvent is an article event waiting in the outbox to be published.
+// Its ID identifies the article revision and is the published message's
+// Nats-Msg-Id, so JetStream drops a message published twice within its
+// duplicate window. Published events are kept for a while to be inspected;
+// keeping them isn't what stops an event being queued again, the article's
+// queued revision is.
+type OutboxEvent[T any] struct {
+	ID            string          `bson:"_id"`
+	Type          string          `bson:"type"`
+	Event         ArticleEvent[T] `bson:"event"`
+	CorrelationID string          `bson:"correlationID"`
+	CreatedAt     time.Time       `bson:"createdAt"`
+	PublishedAt   *time.Time      `bson:"publishedAt,omitempty"`
+	Attempts      int             `bson:"attempts"`
+	LastError     string          `bson:"lastError,omitempty"`
+	// NextAttemptAt holds a failed event back until it's retried.
+	NextAttemptAt *time.Time `bson:"nextAttemptAt,omitempty"`
+}
+
+// EventID keys the event of an article revision in the outbox.
+func EventID(articleID int, revision int) string {
+	return fmt.Sprintf("article-%d-%d", articleID, revision)
+}
+
+// Upsert returns the outbox write of the event. It's only inserted when
+// missing, so writing it again after a failure doesn't duplicate it.
+func (e OutboxEvent[T]) Upsert() (bson.M, bson.M) {
+	return bson.M{"_id": e.ID}, bson.M{"$setOnInsert": e}
+}
+
+// QueuedRevisionField is the article field holding the latest revision whose
+// event is in the outbox. Articles behind it have their event queued again by
+// the next write.
+const QueuedRevisionField = "queuedRevision"
+
+// MarkQueued returns the article update recording that the event of the
+// revision is queued.
+func MarkQueued(articleID int, revision int) (bson.M, bson.M) {
+	return bson.M{"id": articleID}, bson.M{"$max": bson.M{QueuedRevisionField: revision}}
+}
+
+// ReserveIDs reserves count consecutive article IDs with a single counter
+// increment and returns the first one.
+func ReserveIDs(ctx context.Context, db *mongo.Database, count int) (int, error) {
+	var counter struct {
+		Seq int `bson:"seq"`
+	}
+	err := db.Collection(CountersCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": "article_id"},
+		bson.M{"$inc": bson.M{"seq": count}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to reserve article IDs")
+	}
+	return counter.Seq - count + 1, nil
+}
@@ -0,0 +1,39 @@
+package articledb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type tag struct {
+	ID int `bson:"id"`
+}
+
+func TestOutboxEvent_Upsert(t *testing.T) {
+	event := OutboxEvent[tag]{
+		ID:    EventID(7, 2),
+		Type:  EventUpdated,
+		Event: ArticleEvent[tag]{ID: 7, Revision: 2, Tags: []tag{{ID: 1}}},
+	}
+
+	filter, update := event.Upsert()
+
+	if filter["_id"] != "article-7-2" {
+		t.Errorf("expected the event keyed by article and revision, got %v", filter)
+	}
+	if inserted, ok := update["$setOnInsert"].(OutboxEvent[tag]); !ok || inserted.ID != event.ID {
+		t.Errorf("expected the event only inserted when missing, got %v", update)
+	}
+}
+
+func TestMarkQueued(t *testing.T) {
+	filter, update := MarkQueued(7, 2)
+
+	if filter["id"] != 7 {
+		t.Errorf("expected the article matched by ID, got %v", filter)
+	}
+	if update["$max"].(bson.M)[QueuedRevisionField] != 2 {
+		t.Errorf("expected the queued revision raised to 2, got %v", update)
+	}
+}
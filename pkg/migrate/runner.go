@@ -0,0 +1,302 @@
+// Package migrate applies the ordered schema migrations of a component,
+// shared by the api and the worker.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultLockTTL  = time.Minute
+	defaultLockWait = 2 * time.Minute
+	lockRetry       = time.Second
+)
+
+var (
+	// ErrLockTimeout is returned when another instance holds the migration
+	// lock for longer than the runner is willing to wait.
+	ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+	// ErrLockLost is returned when the lock could not be refreshed while
+	// migrating, so another instance may have taken it over.
+	ErrLockLost = errors.New("lost the migration lock")
+)
+
+// Migration is one schema change. Up must be idempotent: a step interrupted
+// halfway is run again in full on the next start.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// AppliedMigration is the record of a migration in schema_migrations.
+type AppliedMigration struct {
+	ID          string        `bson:"_id"`
+	Component   string        `bson:"component"`
+	Version     int           `bson:"version"`
+	Description string        `bson:"description"`
+	AppliedAt   time.Time     `bson:"appliedAt"`
+	Duration    time.Duration `bson:"duration"`
+}
+
+// Logger receives the runner's progress.
+type Logger interface {
+	Info(ctx context.Context, msg string)
+	Error(ctx context.Context, msg string)
+}
+
+// store keeps the applied versions and the lock of each component.
+type store interface {
+	applied(ctx context.Context, component string) (map[int]bool, error)
+	record(ctx context.Context, record AppliedMigration) error
+	// lock takes or extends the lock, failing with errLockHeld while another
+	// owner holds an unexpired one.
+	lock(ctx context.Context, component string, owner string, now time.Time, expiresAt time.Time) error
+	unlock(ctx context.Context, component string, owner string) error
+}
+
+var errLockHeld = errors.New("the migration lock is held by another instance")
+
+// Runner applies the migrations of one component in version order, recording
+// each applied version. A lock keeps concurrent instances from migrating at
+// the same time; it is refreshed while the steps run, and the running step is
+// cancelled if it can't be.
+type Runner struct {
+	db         *mongo.Database
+	store      store
+	logger     Logger
+	component  string
+	migrations []Migration
+	owner      string
+	dryRun     bool
+	lockTTL    time.Duration
+	lockWait   time.Duration
+	lockRetry  time.Duration
+	now        func() time.Time
+}
+
+func NewRunner(db *mongo.Database, component string, migrations []Migration, logger Logger) *Runner {
+	host, _ := os.Hostname()
+	return &Runner{
+		db:         db,
+		store:      mongoStore{db: db},
+		logger:     logger,
+		component:  component,
+		migrations: migrations,
+		owner:      fmt.Sprintf("%s-%d", host, os.Getpid()),
+		lockTTL:    defaultLockTTL,
+		lockWait:   defaultLockWait,
+		lockRetry:  lockRetry,
+		now:        time.Now,
+	}
+}
+
+// WithDryRun makes Run report the pending migrations without applying them.
+func (r *Runner) WithDryRun(dryRun bool) *Runner {
+	r.dryRun = dryRun
+	return r
+}
+
+// WithLock sets how long a lock is held before other instances may take it
+// over, and how long to wait for a lock held by another instance.
+func (r *Runner) WithLock(ttl time.Duration, wait time.Duration) *Runner {
+	if ttl > 0 {
+		r.lockTTL = ttl
+	}
+	if wait > 0 {
+		r.lockWait = wait
+	}
+	return r
+}
+
+// Run applies the pending migrations and returns their versions. In dry-run
+// mode the pending versions are returned without being applied.
+func (r *Runner) Run(ctx context.Context) ([]int, error) {
+	if err := Validate(r.migrations); err != nil {
+		return nil, err
+	}
+
+	if r.dryRun {
+		applied, err := r.store.applied(ctx, r.component)
+		if err != nil {
+			return nil, err
+		}
+		versions := []int{}
+		for _, migration := range pending(r.migrations, applied) {
+			r.logger.Info(ctx, fmt.Sprintf("dry run: would apply %s migration %d: %s",
+				r.component, migration.Version, migration.Description))
+			versions = append(versions, migration.Version)
+		}
+		return versions, nil
+	}
+
+	if err := r.lock(ctx); err != nil {
+		return nil, err
+	}
+
+	migrateCtx, cancel := context.WithCancelCause(ctx)
+	var heartbeat sync.WaitGroup
+	heartbeat.Add(1)
+	go func() {
+		defer heartbeat.Done()
+		r.heartbeat(migrateCtx, cancel)
+	}()
+	defer func() {
+		// Stop refreshing before unlocking, or a late refresh takes the lock again.
+		cancel(nil)
+		heartbeat.Wait()
+		r.unlock(context.WithoutCancel(ctx))
+	}()
+
+	// Read the applied versions under the lock, another instance may just
+	// have finished migrating.
+	applied, err := r.store.applied(migrateCtx, r.component)
+	if err != nil {
+		return nil, r.cause(migrateCtx, err)
+	}
+
+	versions := []int{}
+	for _, migration := range pending(r.migrations, applied) {
+		if err := r.apply(migrateCtx, migration); err != nil {
+			return versions, r.cause(migrateCtx, err)
+		}
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
+
+func (r *Runner) apply(ctx context.Context, migration Migration) error {
+	r.logger.Info(ctx, fmt.Sprintf("applying %s migration %d: %s",
+		r.component, migration.Version, migration.Description))
+
+	if err := r.refreshLock(ctx); err != nil {
+		return errors.Wrap(ErrLockLost, err.Error())
+	}
+
+	started := r.now()
+	if err := migration.Up(ctx, r.db); err != nil {
+		return errors.Wrapf(err, "applying %s migration %d", r.component, migration.Version)
+	}
+
+	record := AppliedMigration{
+		ID:          fmt.Sprintf("%s-%d", r.component, migration.Version),
+		Component:   r.component,
+		Version:     migration.Version,
+		Description: migration.Description,
+		AppliedAt:   r.now().UTC(),
+		Duration:    r.now().Sub(started),
+	}
+	if err := r.store.record(ctx, record); err != nil {
+		return errors.Wrapf(err, "recording %s migration %d", r.component, migration.Version)
+	}
+	return nil
+}
+
+// cause reports a lost lock rather than the cancellation it made a step fail
+// with.
+func (r *Runner) cause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrLockLost) {
+		return errors.Wrapf(cause, "%s migrations", r.component)
+	}
+	return err
+}
+
+// lock takes the component's lock, waiting while it is held by an instance
+// whose lock has not expired.
+func (r *Runner) lock(ctx context.Context) error {
+	deadline := r.now().Add(r.lockWait)
+	for {
+		err := r.refreshLock(ctx)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			return errors.Wrap(err, "failed to take the migration lock")
+		}
+		if r.now().After(deadline) {
+			return ErrLockTimeout
+		}
+
+		r.logger.Info(ctx, fmt.Sprintf("waiting for another instance to finish %s migrations", r.component))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.lockRetry):
+		}
+	}
+}
+
+// heartbeat extends the lock every third of its TTL until ctx is done. When
+// the lock can't be extended it cancels ctx, so the running step stops before
+// another instance takes over.
+func (r *Runner) heartbeat(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(r.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := r.refreshLock(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error(ctx, fmt.Sprintf("refreshing the %s migration lock: %v", r.component, err))
+			cancel(errors.Wrap(ErrLockLost, err.Error()))
+			return
+		}
+	}
+}
+
+func (r *Runner) refreshLock(ctx context.Context) error {
+	now := r.now().UTC()
+	return r.store.lock(ctx, r.component, r.owner, now, now.Add(r.lockTTL))
+}
+
+func (r *Runner) unlock(ctx context.Context) {
+	if err := r.store.unlock(ctx, r.component, r.owner); err != nil {
+		r.logger.Error(ctx, fmt.Sprintf("releasing the migration lock: %v", err))
+	}
+}
+
+// Validate rejects migration lists that would apply in an ambiguous order.
+func Validate(migrations []Migration) error {
+	seen := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("migration version %d must be positive", migration.Version)
+		}
+		if seen[migration.Version] {
+			return fmt.Errorf("migration version %d is declared twice", migration.Version)
+		}
+		if migration.Up == nil {
+			return fmt.Errorf("migration %d has no Up step", migration.Version)
+		}
+		seen[migration.Version] = true
+	}
+	return nil
+}
+
+// pending returns the migrations not yet applied, in version order.
+func pending(migrations []Migration, applied map[int]bool) []Migration {
+	result := []Migration{}
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			result = append(result, migration)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
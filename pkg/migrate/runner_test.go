@@ -0,0 +1,300 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func noop(context.Context, *mongo.Database) error { return nil }
+
+type nopLogger struct{}
+
+func (nopLogger) Info(context.Context, string)  {}
+func (nopLogger) Error(context.Context, string) {}
+
+// memoryStore keeps migrations and locks in memory, shared by the runners of
+// several instances.
+type memoryStore struct {
+	mu        sync.Mutex
+	records   map[int]AppliedMigration
+	owner     string
+	expiresAt time.Time
+	locks     int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[int]AppliedMigration{}}
+}
+
+func (s *memoryStore) applied(_ context.Context, _ string) (map[int]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	applied := map[int]bool{}
+	for version := range s.records {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+func (s *memoryStore) record(_ context.Context, record AppliedMigration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Version] = record
+	return nil
+}
+
+func (s *memoryStore) lock(_ context.Context, _ string, owner string, now time.Time, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owner != "" && s.owner != owner && s.expiresAt.After(now) {
+		return errLockHeld
+	}
+	s.owner, s.expiresAt = owner, expiresAt
+	s.locks++
+	return nil
+}
+
+func (s *memoryStore) unlock(_ context.Context, _ string, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owner == owner {
+		s.owner = ""
+	}
+	return nil
+}
+
+// steal hands the lock to another instance, as if this one's had expired.
+func (s *memoryStore) steal(owner string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owner, s.expiresAt = owner, time.Now().Add(ttl)
+}
+
+func (s *memoryStore) heldBy() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.owner
+}
+
+func newTestRunner(store *memoryStore, owner string, migrations []Migration) *Runner {
+	r := NewRunner(nil, "test", migrations, nopLogger{})
+	r.store = store
+	r.owner = owner
+	r.lockRetry = time.Millisecond
+	return r
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		wantErr    bool
+	}{
+		{name: "ordered", migrations: []Migration{{Version: 1, Up: noop}, {Version: 2, Up: noop}}},
+		{name: "duplicate version", migrations: []Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}}, wantErr: true},
+		{name: "zero version", migrations: []Migration{{Version: 0, Up: noop}}, wantErr: true},
+		{name: "missing step", migrations: []Migration{{Version: 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.migrations); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: 3, Up: noop}, {Version: 1, Up: noop}, {Version: 2, Up: noop}}
+
+	got := pending(migrations, map[int]bool{2: true})
+
+	if len(got) != 2 || got[0].Version != 1 || got[1].Version != 3 {
+		t.Errorf("expected versions [1 3] in order, got %v", got)
+	}
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	store := newMemoryStore()
+	store.records[1] = AppliedMigration{Version: 1}
+	ran := false
+	up := func(context.Context, *mongo.Database) error { ran = true; return nil }
+
+	got, err := newTestRunner(store, "a", []Migration{{Version: 1, Up: up}, {Version: 2, Up: up}}).
+		WithDryRun(true).
+		Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != 2 {
+		t.Errorf("expected pending versions [2], got %v", got)
+	}
+	if ran || len(store.records) != 1 || store.locks != 0 {
+		t.Errorf("expected a dry run to apply, record and lock nothing; ran %v, records %d, locks %d",
+			ran, len(store.records), store.locks)
+	}
+}
+
+func TestRunner_RerunsFailedStep(t *testing.T) {
+	store := newMemoryStore()
+	calls := map[int]int{}
+	fail := true
+	migrations := []Migration{
+		{Version: 1, Up: func(context.Context, *mongo.Database) error { calls[1]++; return nil }},
+		{Version: 2, Up: func(context.Context, *mongo.Database) error {
+			calls[2]++
+			if fail {
+				return errors.New("interrupted")
+			}
+			return nil
+		}},
+		{Version: 3, Up: func(context.Context, *mongo.Database) error { calls[3]++; return nil }},
+	}
+
+	got, err := newTestRunner(store, "a", migrations).Run(context.Background())
+	if err == nil {
+		t.Fatal("expected the failed step's error")
+	}
+	if len(got) != 1 || got[0] != 1 || len(store.records) != 1 {
+		t.Errorf("expected only version 1 applied, got %v with %d records", got, len(store.records))
+	}
+	if store.heldBy() != "" {
+		t.Errorf("expected the lock released after a failure, held by %q", store.heldBy())
+	}
+
+	fail = false
+	got, err = newTestRunner(store, "a", migrations).Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("expected versions [2 3] applied on the rerun, got %v", got)
+	}
+	if calls[1] != 1 || calls[2] != 2 || calls[3] != 1 {
+		t.Errorf("expected the failed step run again and the others once, got %v", calls)
+	}
+}
+
+func TestRunner_Lock(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		wantErr   error
+		wantOwner string
+	}{
+		{name: "held by another instance", expiresIn: time.Hour, wantErr: ErrLockTimeout, wantOwner: "b"},
+		{name: "expired lock taken over", expiresIn: -time.Second, wantOwner: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			store.owner, store.expiresAt = "b", time.Now().Add(tt.expiresIn)
+			ran := false
+			up := func(context.Context, *mongo.Database) error { ran = true; return nil }
+
+			_, err := newTestRunner(store, "a", []Migration{{Version: 1, Up: up}}).
+				WithLock(time.Minute, 10*time.Millisecond).
+				Run(context.Background())
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if ran != (tt.wantErr == nil) {
+				t.Errorf("expected the step run %v, got %v", tt.wantErr == nil, ran)
+			}
+			if store.heldBy() != tt.wantOwner {
+				t.Errorf("expected the lock held by %q, got %q", tt.wantOwner, store.heldBy())
+			}
+		})
+	}
+}
+
+func TestRunner_WaitsForTheOtherInstance(t *testing.T) {
+	store := newMemoryStore()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	migrations := []Migration{{Version: 1, Up: func(context.Context, *mongo.Database) error {
+		calls++
+		close(started)
+		<-release
+		return nil
+	}}}
+
+	first := make(chan error)
+	go func() {
+		_, err := newTestRunner(store, "a", migrations).Run(context.Background())
+		first <- err
+	}()
+	<-started
+
+	second := make(chan []int)
+	go func() {
+		got, _ := newTestRunner(store, "b", migrations).WithLock(time.Minute, time.Minute).Run(context.Background())
+		second <- got
+	}()
+	close(release)
+
+	if err := <-first; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-second; len(got) != 0 || calls != 1 {
+		t.Errorf("expected the waiting instance to find nothing pending, applied %v with %d calls", got, calls)
+	}
+}
+
+func TestRunner_AbortsWhenTheLockIsLost(t *testing.T) {
+	store := newMemoryStore()
+	migrations := []Migration{{Version: 1, Up: func(ctx context.Context, _ *mongo.Database) error {
+		store.steal("b", time.Hour)
+		<-ctx.Done()
+		return ctx.Err()
+	}}}
+
+	done := make(chan error)
+	go func() {
+		_, err := newTestRunner(store, "a", migrations).WithLock(30*time.Millisecond, 0).Run(context.Background())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrLockLost) {
+			t.Errorf("expected %v, got %v", ErrLockLost, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the step cancelled when the lock can't be refreshed")
+	}
+	if len(store.records) != 0 {
+		t.Errorf("expected nothing recorded, got %v", store.records)
+	}
+	if store.heldBy() != "b" {
+		t.Errorf("expected the other instance to keep the lock, got %q", store.heldBy())
+	}
+}
+
+func TestRunner_KeepsTheLockDuringLongSteps(t *testing.T) {
+	store := newMemoryStore()
+	migrations := []Migration{{Version: 1, Up: func(context.Context, *mongo.Database) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}}}
+
+	_, err := newTestRunner(store, "a", migrations).WithLock(30*time.Millisecond, 0).Run(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Taken, refreshed before the step, and at least twice during it.
+	if store.locks < 4 {
+		t.Errorf("expected the lock refreshed while the step ran, got %d lock writes", store.locks)
+	}
+}
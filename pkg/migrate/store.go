@@ -0,0 +1,80 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollectionName = "schema_migrations"
+	locksCollectionName      = "schema_migrations_lock"
+)
+
+type migrationLock struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// mongoStore records migrations in schema_migrations and keeps one lock
+// document per component in schema_migrations_lock.
+type mongoStore struct {
+	db *mongo.Database
+}
+
+func (s mongoStore) applied(ctx context.Context, component string) (map[int]bool, error) {
+	cursor, err := s.db.Collection(migrationsCollectionName).Find(ctx, bson.M{"component": component})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find applied migrations")
+	}
+	defer cursor.Close(ctx)
+
+	var records []AppliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, errors.Wrap(err, "failed to decode applied migrations")
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, record := range records {
+		applied[record.Version] = true
+	}
+	return applied, nil
+}
+
+func (s mongoStore) record(ctx context.Context, record AppliedMigration) error {
+	_, err := s.db.Collection(migrationsCollectionName).ReplaceOne(ctx,
+		bson.M{"_id": record.ID}, record, options.Replace().SetUpsert(true))
+	return err
+}
+
+// lock upserts the lock document of its owner or over an expired one. The
+// upsert fails with a duplicate key error while another owner holds an
+// unexpired lock.
+func (s mongoStore) lock(ctx context.Context, component string, owner string, now time.Time,
+	expiresAt time.Time) error {
+	_, err := s.db.Collection(locksCollectionName).UpdateOne(ctx,
+		bson.M{
+			"_id": component,
+			"$or": bson.A{
+				bson.M{"owner": owner},
+				bson.M{"expiresAt": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"$set": migrationLock{ID: component, Owner: owner, ExpiresAt: expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return errLockHeld
+	}
+	return err
+}
+
+func (s mongoStore) unlock(ctx context.Context, component string, owner string) error {
+	_, err := s.db.Collection(locksCollectionName).DeleteOne(ctx, bson.M{"_id": component, "owner": owner})
+	return err
+}
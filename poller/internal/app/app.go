@@ -7,14 +7,19 @@ import (
 	"os/signal"
 	"syscall"
 
+	"emperror.dev/errors"
 	"github.com/nats-io/nats.go"
+	"github.com/ronnyp07/SportStream/internal/app/httpserver"
+	election_port "github.com/ronnyp07/SportStream/internal/domain/ports/election"
 	portMetrics "github.com/ronnyp07/SportStream/internal/domain/ports/metrics"
 	"github.com/ronnyp07/SportStream/internal/domain/ports/services"
 	"github.com/ronnyp07/SportStream/internal/domain/services/scheduler"
 	"github.com/ronnyp07/SportStream/internal/metrics"
 	"github.com/ronnyp07/SportStream/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/election"
 	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
 	natsQueue "github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/statestore"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -31,7 +36,9 @@ type App struct {
 	ctxCancelFn       func()
 	connectors        Connectors
 	termChan          chan os.Signal
-	shcedulerServ     scheduler.Service
+	shcedulerServ     *scheduler.Service
+	httpServer        *httpserver.Server
+	elector           election_port.Elector
 	msgQueueProcessor services.MessageQueueProcessor
 }
 
@@ -57,9 +64,24 @@ func (a *App) Start(ctx context.Context) error {
 	metricsHandler := metrics.NewSchedulerMetricsHandler()
 	metricsHandler.RegisterMetrics()
 
-	appServices := setupServices(a.connectors)
+	appServices, err := setupServices(a.connectors, metricsHandler)
+	if err != nil {
+		return err
+	}
+
+	a.startScheduler(ctx, metricsHandler, appServices)
 
-	a.startScheduler(ctx, metricsHandler, appServices.MsgQueueService)
+	server := httpserver.NewServerBuilder(httpserver.Services{
+		SchedulerService: a.shcedulerServ,
+	}).
+		WithAddr(config.App().Http.HostAddress).
+		WithReadTimeout(config.App().Http.ReadTimeout).
+		WithWriteTimeout(config.App().Http.WriteTimeout).
+		WithAdminToken(config.App().Http.AdminToken).
+		Build()
+
+	server.Start(a.ctx, a.ctxCancelFn)
+	a.httpServer = server
 
 	//appServices := setupServices(a.connectors)
 	appServices.MsgQueueService.PublishMessage(ctx, "SPORTSTREAM.DOCKER.status.updated", []byte("test nats"))
@@ -94,6 +116,16 @@ func (a *App) Shutdown() {
 	<-a.termChan
 
 	log.Logger().Info(a.ctx, "stopping the server...")
+	if a.httpServer != nil {
+		if err := a.httpServer.Stop(a.ctx); err != nil {
+			log.Logger().Error(a.ctx, err.Error())
+		}
+	}
+	if a.elector != nil {
+		if err := a.elector.Resign(a.ctx); err != nil {
+			log.Logger().Error(a.ctx, err.Error())
+		}
+	}
 	if err := a.shcedulerServ.Shutdown(); err != nil {
 		log.Logger().Error(a.ctx, err.Error())
 	}
@@ -106,19 +138,49 @@ func (a *App) Shutdown() {
 
 func (a *App) startScheduler(ctx context.Context,
 	mectrics portMetrics.SchedulerMetricsHandler,
-	msgService natsQueue.MsgQueueService) {
-	schedulerService, err := scheduler.NewService(config.App().Jobs, mectrics, msgService)
+	appServices Services) {
+	if appServices.Elector != nil {
+		appServices.Elector.Start(ctx)
+		a.elector = appServices.Elector
+	}
+
+	schedulerService, err := scheduler.NewService(config.App().Jobs, mectrics,
+		appServices.MsgQueueService, appServices.CursorStore, appServices.Elector)
 	if err != nil {
 		log.Logger().Info(ctx, "unable to start scheduler")
 	}
 	go schedulerService.Start(ctx)
-	a.shcedulerServ = *schedulerService
+	a.shcedulerServ = schedulerService
+
+	config.OnJobsChange(func(jobs config.Jobs, err error) {
+		if err != nil {
+			schedulerService.RejectReload(ctx, err)
+			return
+		}
+		schedulerService.Reload(ctx, jobs)
+	})
 }
 
-func setupServices(c Connectors) Services {
+func setupServices(c Connectors, metrics portMetrics.SchedulerMetricsHandler) (Services, error) {
 	msgQueue := natsQueue.NewNatsService(c.natsJSCtx)
+	cursorStore, err := statestore.New(config.App().StateStore, c.natsJSCtx)
+	if err != nil {
+		return Services{}, errors.Wrap(err, "setting up cursor store")
+	}
+
+	var elector election_port.Elector
+	if leaderCfg := config.App().LeaderElection; leaderCfg.Enabled {
+		instance, _ := os.Hostname()
+		elector, err = election.NewKVElector(c.natsJSCtx, leaderCfg.Bucket, leaderCfg.TTL, instance, metrics)
+		if err != nil {
+			return Services{}, errors.Wrap(err, "setting up leader election")
+		}
+	}
+
 	// Implement service setup logic
 	return Services{
 		MsgQueueService: msgQueue,
-	}
+		CursorStore:     cursorStore,
+		Elector:         elector,
+	}, nil
 }
@@ -0,0 +1,45 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		remoteAddr    string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "no token, local request", remoteAddr: "127.0.0.1:41000", wantStatus: http.StatusOK},
+		{name: "no token, local IPv6 request", remoteAddr: "[::1]:41000", wantStatus: http.StatusOK},
+		{name: "no token, remote request", remoteAddr: "172.18.0.1:41000", wantStatus: http.StatusForbidden},
+		{name: "token presented", token: "s3cret", remoteAddr: "172.18.0.1:41000", authorization: "Bearer s3cret", wantStatus: http.StatusOK},
+		{name: "wrong token", token: "s3cret", remoteAddr: "172.18.0.1:41000", authorization: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "token missing", token: "s3cret", remoteAddr: "172.18.0.1:41000", wantStatus: http.StatusUnauthorized},
+		{name: "token missing on a local request", token: "s3cret", remoteAddr: "127.0.0.1:41000", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := requireAdmin(tt.token, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
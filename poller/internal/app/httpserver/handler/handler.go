@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+	"github.com/ronnyp07/SportStream/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/internal/domain/services/scheduler"
+)
+
+const defaultRunsLimit = 10
+
+type JobsHandler struct {
+	service services.SchedulerService
+}
+
+func NewJobsHandler(service services.SchedulerService) *JobsHandler {
+	return &JobsHandler{
+		service: service,
+	}
+}
+
+// GetJobs lists the scheduled jobs with their last and next run times.
+func (h *JobsHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.service.GetAllJobs(r.Context())
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		jobs = []models.Job{}
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+func (h *JobsHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetJob(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// RunJob triggers the job immediately, outside of its schedule.
+func (h *JobsHandler) RunJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.RunJobNow(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (h *JobsHandler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.PauseJob(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (h *JobsHandler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.ResumeJob(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// GetJobRuns returns the last N run outcomes of a job, N given by the limit query parameter.
+func (h *JobsHandler) GetJobRuns(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRunsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.service.GetJobRuns(r.Context(), r.PathValue("name"), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (h *JobsHandler) GetCursor(w http.ResponseWriter, r *http.Request) {
+	cursor, err := h.service.GetCursor(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cursor)
+}
+
+func (h *JobsHandler) ResetCursor(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ResetCursor(r.Context(), r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, scheduler.ErrJobPaused), errors.Is(err, scheduler.ErrNotLeader):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ronnyp07/SportStream/internal/app/httpserver/handler"
+	"github.com/ronnyp07/SportStream/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
+)
+
+func NewServerBuilder(services Services) *Server {
+	return &Server{
+		Services:   services,
+		httpServer: &http.Server{},
+	}
+}
+
+func (s *Server) WithAddr(addr string) *Server {
+	s.httpServer.Addr = addr
+	return s
+}
+
+func (s *Server) WithReadTimeout(readTimeOut time.Duration) *Server {
+	s.httpServer.ReadTimeout = readTimeOut
+	return s
+}
+
+func (s *Server) WithWriteTimeout(writeTimeOut time.Duration) *Server {
+	s.httpServer.WriteTimeout = writeTimeOut
+	return s
+}
+
+// WithAdminToken sets the bearer token the admin API requires. Without one
+// the admin API is only served to local requests.
+func (s *Server) WithAdminToken(token string) *Server {
+	s.adminToken = token
+	return s
+}
+
+func (s *Server) Build() *Server {
+	s.setupHandler()
+	s.httpServer.Handler = s.Routes
+	return s
+}
+
+func (s *Server) Start(ctx context.Context, shutDownCall func()) {
+	go func() {
+		log.Logger().Info(ctx, fmt.Sprintf("Admin server starting on %s", s.httpServer.Addr))
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Logger().Error(ctx, fmt.Sprintf("Admin server stopped in %s due to %v", config.App().Env.Name, err))
+			shutDownCall()
+		}
+	}()
+}
+
+func (s *Server) setupHandler() {
+	jobsHandler := handler.NewJobsHandler(s.Services.SchedulerService)
+	s.Routes = NewRouter(jobsHandler, s.adminToken)
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("Admin server shutdown failed %v", err))
+		return err
+	}
+	return nil
+}
+
+func NewRouter(jobsHandler *handler.JobsHandler, adminToken string) *http.ServeMux {
+	r := http.NewServeMux()
+
+	// Job administration
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin/jobs", jobsHandler.GetJobs)
+	admin.HandleFunc("GET /admin/jobs/{name}", jobsHandler.GetJob)
+	admin.HandleFunc("POST /admin/jobs/{name}/run", jobsHandler.RunJob)
+	admin.HandleFunc("POST /admin/jobs/{name}/pause", jobsHandler.PauseJob)
+	admin.HandleFunc("POST /admin/jobs/{name}/resume", jobsHandler.ResumeJob)
+	admin.HandleFunc("GET /admin/jobs/{name}/runs", jobsHandler.GetJobRuns)
+	admin.HandleFunc("GET /admin/jobs/{name}/cursor", jobsHandler.GetCursor)
+	admin.HandleFunc("DELETE /admin/jobs/{name}/cursor", jobsHandler.ResetCursor)
+	r.Handle("/admin/", requireAdmin(adminToken, admin))
+
+	// Health check
+	r.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return r
+}
@@ -0,0 +1,18 @@
+package httpserver
+
+import (
+	"net/http"
+
+	portsServices "github.com/ronnyp07/SportStream/internal/domain/ports/services"
+)
+
+type Services struct {
+	SchedulerService portsServices.SchedulerService
+}
+
+type Server struct {
+	Services   Services
+	httpServer *http.Server
+	Routes     *http.ServeMux
+	adminToken string
+}
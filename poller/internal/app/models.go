@@ -1,9 +1,13 @@
 package app
 
 import (
+	election_port "github.com/ronnyp07/SportStream/internal/domain/ports/election"
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
 	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
 )
 
 type Services struct {
 	MsgQueueService msgqueue.MsgQueueService
+	CursorStore     ports.CursorStore
+	Elector         election_port.Elector
 }
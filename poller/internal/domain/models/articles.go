@@ -2,13 +2,15 @@ package models
 
 // Define models for the external API response
 type ArticleResponse struct {
-	PageInfo struct {
-		Page       int `json:"page"`
-		NumPages   int `json:"numPages"`
-		PageSize   int `json:"pageSize"`
-		NumEntries int `json:"numEntries"`
-	} `json:"pageInfo"`
-	Content []Article `json:"content"`
+	PageInfo PageInfo  `json:"pageInfo"`
+	Content  []Article `json:"content"`
+}
+
+type PageInfo struct {
+	Page       int `json:"page"`
+	NumPages   int `json:"numPages"`
+	PageSize   int `json:"pageSize"`
+	NumEntries int `json:"numEntries"`
 }
 
 type Article struct {
@@ -20,6 +22,7 @@ type Article struct {
 	Summary     string `json:"summary"`
 	LeadMedia   Media  `json:"leadMedia"`
 	Tags        []Tag  `json:"tags"`
+	Source      string `json:"source"`
 	// Add other fields as needed
 }
 
@@ -27,6 +30,7 @@ type Media struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 	Type  string `json:"type"`
+	URL   string `json:"url,omitempty"`
 	// Add image URLs/variants as needed
 }
 
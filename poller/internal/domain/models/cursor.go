@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Cursor is the pagination position of a job against its upstream source.
+type Cursor struct {
+	CurrentPage   int       `json:"current_page"`
+	MaxPages      int       `json:"max_pages"`
+	LastFetchTime time.Time `json:"last_fetch_time"`
+	Watermark     Watermark `json:"watermark"`
+	// CatchUp is set while an incremental job is still paging down to its
+	// watermark, after a run used up its pages before reaching it.
+	CatchUp *CatchUp `json:"catch_up,omitempty"`
+}
+
+// CatchUp is where an incremental job resumes paging towards its watermark,
+// and the newest article published since it started, which becomes the
+// watermark once the old one is reached.
+type CatchUp struct {
+	NextPage int       `json:"next_page"`
+	Newest   Watermark `json:"newest"`
+}
+
+// Watermark is the newest article an incremental job has already published.
+type Watermark struct {
+	Date string `json:"date"`
+	ID   int    `json:"id"`
+}
+
+// NewWatermark builds the watermark pointing at the given article.
+func NewWatermark(article Article) Watermark {
+	return Watermark{Date: article.Date, ID: article.ID}
+}
+
+// IsZero reports whether nothing has been published yet.
+func (w Watermark) IsZero() bool {
+	return w.Date == "" && w.ID == 0
+}
+
+// Time parses the watermark date as RFC3339.
+func (w Watermark) Time() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, w.Date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// IsBefore reports whether the article is newer than the watermark. Dates are
+// compared as RFC3339 when both parse, as plain strings otherwise, and the ID
+// breaks ties.
+func (w Watermark) IsBefore(article Article) bool {
+	if w.IsZero() {
+		return true
+	}
+
+	if cmp := compareDates(w.Date, article.Date); cmp != 0 {
+		return cmp < 0
+	}
+	return w.ID < article.ID
+}
+
+func compareDates(a, b string) int {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA == nil && errB == nil {
+		return ta.Compare(tb)
+	}
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
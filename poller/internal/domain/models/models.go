@@ -9,6 +9,19 @@ type Job struct {
 	LastRun   time.Time `json:"last_run"`
 	NextRun   time.Time `json:"next_run"`
 	Tags      []string  `json:"tags"`
+	Paused    bool      `json:"paused"`
+}
+
+// JobRun is the outcome of a single execution of a job.
+type JobRun struct {
+	StartedAt time.Time `json:"started_at"`
+	Mode      string    `json:"mode"`
+	Attempts  int       `json:"attempts"`
+	Pages     int       `json:"pages"`
+	Articles  int       `json:"articles"`
+	Latency   string    `json:"latency"`
+	Succeeded bool      `json:"succeeded"`
+	Error     string    `json:"error,omitempty"`
 }
 
 type TrackInfo struct {
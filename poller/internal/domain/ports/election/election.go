@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+// Elector decides which poller replica runs the schedule. It satisfies
+// gocron.Elector so the scheduler only executes jobs on the leader.
+type Elector interface {
+	IsLeader(ctx context.Context) error
+
+	Start(ctx context.Context)
+
+	Resign(ctx context.Context) error
+}
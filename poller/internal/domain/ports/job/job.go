@@ -3,11 +3,30 @@ package ports
 import (
 	"context"
 
+	"github.com/ronnyp07/SportStream/internal/domain/models"
 	"github.com/ronnyp07/SportStream/internal/pkg/config"
 )
 
 type IJob interface {
 	Configure(ctx context.Context, jobCnfig config.Job) error
 
+	Reconfigure(ctx context.Context, jobConfig config.Job) error
+
+	Remove(ctx context.Context) error
+
+	Config() config.Job
+
 	Name() string
+
+	Cursor(ctx context.Context) (models.Cursor, error)
+
+	ResetCursor(ctx context.Context) error
+
+	Pause()
+
+	Resume()
+
+	Paused() bool
+
+	Runs(limit int) []models.JobRun
 }
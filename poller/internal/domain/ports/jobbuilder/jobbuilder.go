@@ -9,4 +9,7 @@ import (
 
 type JobBuilder interface {
 	BuildJob(ctx context.Context, name string, jobConfig config.Job, task func(ctx context.Context)) (gocron.Job, error)
+	UpdateJob(ctx context.Context, job gocron.Job, jobConfig config.Job, task func(ctx context.Context)) (gocron.Job, error)
+	RemoveJob(ctx context.Context, job gocron.Job) error
+	ValidateJob(jobConfig config.Job) error
 }
@@ -10,4 +10,8 @@ type SchedulerMetricsHandler interface {
 	SchedulerHTTPClientCall(startTime time.Time, method string,
 		destination string, requestCode int, platformName string)
 	ReportScheduleOfJob(string)
+	ReportPollRun(jobName string, mode string, pages int, articles int)
+	ReportWatermark(jobName string, watermark time.Time)
+	ReportConfigReload(status string)
+	ReportLeader(leader bool)
 }
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+)
+
+type SchedulerService interface {
+	GetAllJobs(ctx context.Context) ([]models.Job, error)
+	GetJob(ctx context.Context, name string) (models.Job, error)
+	RunJobNow(ctx context.Context, ID string) (models.Job, error)
+	PauseJob(ctx context.Context, name string) (models.Job, error)
+	ResumeJob(ctx context.Context, name string) (models.Job, error)
+	GetJobRuns(ctx context.Context, name string, limit int) ([]models.JobRun, error)
+	GetCursor(ctx context.Context, name string) (models.Cursor, error)
+	ResetCursor(ctx context.Context, name string) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+)
+
+// SourceAdapter knows how to address and decode the articles of a single
+// upstream publisher. The poller job owns scheduling, retries and publishing.
+type SourceAdapter interface {
+	Type() string
+	BuildURL(baseURL string, page int, pageSize int) string
+	Decode(body []byte) (models.ArticleResponse, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+)
+
+// CursorStore persists job cursors so a restarted poller resumes where it
+// left off. Get returns a zero cursor when nothing was committed yet.
+type CursorStore interface {
+	Get(ctx context.Context, jobName string) (models.Cursor, error)
+	Save(ctx context.Context, jobName string, cursor models.Cursor) error
+	Reset(ctx context.Context, jobName string) error
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/robfig/cron/v3"
 	"github.com/ronnyp07/SportStream/internal/pkg/config"
 	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
 )
@@ -44,6 +45,46 @@ func (j *JobBuilder) BuildJob(ctx context.Context, name string, cfg config.Job,
 	)
 }
 
+// UpdateJob reschedules an existing job in place, keeping its ID and name.
+func (j *JobBuilder) UpdateJob(ctx context.Context, job gocron.Job, cfg config.Job, fn func(ctx context.Context)) (gocron.Job, error) {
+	jobDefinition, err := getJobDefinition(cfg.Type, cfg.Interval, cfg.UseSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Logger().Info(ctx, fmt.Sprintf("Rescheduling job name %s", job.Name()))
+	return j.scheduler.Update(
+		job.ID(),
+		jobDefinition,
+		gocron.NewTask(fn, ctx),
+		gocron.WithName(job.Name()),
+	)
+}
+
+func (j *JobBuilder) RemoveJob(ctx context.Context, job gocron.Job) error {
+	log.Logger().Info(ctx, fmt.Sprintf("Removing job name %s", job.Name()))
+	return j.scheduler.RemoveJob(job.ID())
+}
+
+// ValidateJob checks that an enabled job configuration can be scheduled.
+func (j *JobBuilder) ValidateJob(cfg config.Job) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Type == CronJobType {
+		fields := cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor
+		if cfg.UseSeconds {
+			fields |= cron.SecondOptional
+		}
+		_, err := cron.NewParser(fields).Parse(cfg.Interval)
+		return err
+	}
+
+	_, err := getJobDefinition(cfg.Type, cfg.Interval, cfg.UseSeconds)
+	return err
+}
+
 func getJobDefinition(jType, interval string, withSeconds bool) (gocron.JobDefinition, error) {
 	switch jType {
 	case CronJobType:
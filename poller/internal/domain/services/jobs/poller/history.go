@@ -0,0 +1,37 @@
+package pooller
+
+import (
+	"sync"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+)
+
+const historySize = 50
+
+// runHistory keeps the most recent run outcomes of a job, newest first.
+type runHistory struct {
+	mu   sync.Mutex
+	runs []models.JobRun
+}
+
+func (h *runHistory) add(run models.JobRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.runs = append([]models.JobRun{run}, h.runs...)
+	if len(h.runs) > historySize {
+		h.runs = h.runs[:historySize]
+	}
+}
+
+func (h *runHistory) last(limit int) []models.JobRun {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if limit <= 0 || limit > len(h.runs) {
+		limit = len(h.runs)
+	}
+	runs := make([]models.JobRun, limit)
+	copy(runs, h.runs)
+	return runs
+}
@@ -2,17 +2,26 @@ package pooller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/ronnyp07/SportStream/internal/domain/models"
 	ports "github.com/ronnyp07/SportStream/internal/domain/ports/jobbuilder"
 	metrics_port "github.com/ronnyp07/SportStream/internal/domain/ports/metrics"
+	source_port "github.com/ronnyp07/SportStream/internal/domain/ports/source"
+	state_port "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
+	"github.com/ronnyp07/SportStream/internal/domain/services/msgqueue/envelope"
+	"github.com/ronnyp07/SportStream/internal/domain/services/msgqueue/msgtype"
 	"github.com/ronnyp07/SportStream/internal/pkg/config"
 	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
 	natsQueue "github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
@@ -21,64 +30,393 @@ import (
 )
 
 const (
-	name            = "poller"
-	externalService = "articleservice"
-	natsSubject     = "SPORTSTREAM.status.updated"
-	pageSize        = 2
+	natsSubject = "SPORTSTREAM.status.updated"
+	pageSize    = 2
+
+	// articleSchemaVersion is the version of the article payload in published envelopes.
+	articleSchemaVersion = 1
+
+	// IncrementalMode pages from the newest article until the stored watermark is reached.
+	IncrementalMode = "INCREMENTAL"
+	// FullResyncMode walks one page per run through the whole catalog and wraps around.
+	FullResyncMode = "FULL_RESYNC"
+
+	defaultMaxPagesPerRun = 10
 )
 
 type Job struct {
+	name          string
+	source        source_port.SourceAdapter
 	scheduler     gocron.Scheduler
+	cronJob       gocron.Job
 	jobConfig     config.Job
 	jobBuilder    ports.JobBuilder
 	httpclient    cchttp.Client
 	metrics       metrics_port.SchedulerMetricsHandler
 	msgQueueServ  natsQueue.MsgQueueService
+	cursorStore   state_port.CursorStore
 	currentPage   int
 	maxPages      int
 	lastFetchTime time.Time
+	watermark     models.Watermark
+	catchUp       *models.CatchUp
 	stateMutex    sync.Mutex
+	paused        atomic.Bool
+	history       runHistory
 }
 
 func New(
+	name string,
+	source source_port.SourceAdapter,
 	scheduler gocron.Scheduler,
 	jobBuilder ports.JobBuilder,
 	httpclient cchttp.Client,
 	metrics metrics_port.SchedulerMetricsHandler,
 	msgQueueServ natsQueue.MsgQueueService,
+	cursorStore state_port.CursorStore,
 ) *Job {
 	return &Job{
+		name:         name,
+		source:       source,
 		scheduler:    scheduler,
 		jobBuilder:   jobBuilder,
 		httpclient:   httpclient,
 		metrics:      metrics,
 		msgQueueServ: msgQueueServ,
+		cursorStore:  cursorStore,
 	}
 }
 
 func (j *Job) Configure(ctx context.Context, jobConfig config.Job) error {
-	_, err := j.jobBuilder.BuildJob(ctx, name, jobConfig, j.runTask)
+	if err := j.restoreCursor(ctx); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("unable to restore cursor for job %s, starting from page 0 due to %s", j.name, err.Error()))
+	}
+
+	cronJob, err := j.jobBuilder.BuildJob(ctx, j.name, jobConfig, j.runTask)
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("An error occurred configuring the job %s due to %s", name, err.Error()))
+		log.Logger().Error(ctx, fmt.Sprintf("An error occurred configuring the job %s due to %s", j.name, err.Error()))
 		return err
 	}
-	j.jobConfig = jobConfig
+	j.cronJob = cronJob
+	j.setJobConfig(jobConfig)
 	return nil
 }
 
+// Reconfigure applies a changed configuration to a configured job: it is
+// rescheduled in place, scheduled if it was disabled, or removed if it is
+// disabled now.
+func (j *Job) Reconfigure(ctx context.Context, jobConfig config.Job) error {
+	var err error
+	switch {
+	case !jobConfig.Enabled:
+		err = j.Remove(ctx)
+	case j.cronJob == nil:
+		j.cronJob, err = j.jobBuilder.BuildJob(ctx, j.name, jobConfig, j.runTask)
+	default:
+		j.cronJob, err = j.jobBuilder.UpdateJob(ctx, j.cronJob, jobConfig, j.runTask)
+	}
+	if err != nil {
+		return err
+	}
+
+	j.setJobConfig(jobConfig)
+	return nil
+}
+
+// Remove takes the job off the scheduler.
+func (j *Job) Remove(ctx context.Context) error {
+	if j.cronJob == nil {
+		return nil
+	}
+	if err := j.jobBuilder.RemoveJob(ctx, j.cronJob); err != nil {
+		return err
+	}
+	j.cronJob = nil
+	return nil
+}
+
+func (j *Job) Config() config.Job {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+
+	return j.jobConfig
+}
+
+func (j *Job) setJobConfig(jobConfig config.Job) {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+
+	j.jobConfig = jobConfig
+}
+
 func (j *Job) Name() string {
-	return name
+	return j.name
+}
+
+// Cursor returns the pagination position the job will fetch next.
+func (j *Job) Cursor(_ context.Context) (models.Cursor, error) {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+
+	return j.cursor(), nil
+}
+
+// ResetCursor moves the job back to the first page and clears the stored cursor.
+func (j *Job) ResetCursor(ctx context.Context) error {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+
+	if err := j.cursorStore.Reset(ctx, j.name); err != nil {
+		return err
+	}
+
+	j.currentPage = 0
+	j.maxPages = 0
+	j.lastFetchTime = time.Time{}
+	j.watermark = models.Watermark{}
+	j.catchUp = nil
+	log.Logger().Info(ctx, fmt.Sprintf("cursor reset for job %s", j.name))
+	return nil
+}
+
+// Pause makes scheduled runs of the job skip until it is resumed.
+func (j *Job) Pause() {
+	j.paused.Store(true)
+}
+
+// Resume lets scheduled runs of the job fetch again.
+func (j *Job) Resume() {
+	j.paused.Store(false)
+}
+
+func (j *Job) Paused() bool {
+	return j.paused.Load()
+}
+
+// Runs returns up to limit of the most recent run outcomes, newest first.
+func (j *Job) Runs(limit int) []models.JobRun {
+	return j.history.last(limit)
+}
+
+func (j *Job) cursor() models.Cursor {
+	return models.Cursor{
+		CurrentPage:   j.currentPage,
+		MaxPages:      j.maxPages,
+		LastFetchTime: j.lastFetchTime,
+		Watermark:     j.watermark,
+		CatchUp:       j.catchUp,
+	}
+}
+
+func (j *Job) restoreCursor(ctx context.Context) error {
+	j.stateMutex.Lock()
+	defer j.stateMutex.Unlock()
+
+	if err := j.loadCursor(ctx); err != nil {
+		return err
+	}
+	log.Logger().Info(ctx, fmt.Sprintf("restored cursor for job %s %v", j.name, j.cursor()))
+	return nil
+}
+
+// loadCursor replaces the job's position with the stored cursor; the caller
+// holds stateMutex.
+func (j *Job) loadCursor(ctx context.Context) error {
+	cursor, err := j.cursorStore.Get(ctx, j.name)
+	if err != nil {
+		return err
+	}
+
+	j.currentPage = cursor.CurrentPage
+	j.maxPages = cursor.MaxPages
+	j.lastFetchTime = cursor.LastFetchTime
+	j.watermark = cursor.Watermark
+	j.catchUp = cursor.CatchUp
+	log.Logger().Debug(ctx, fmt.Sprintf("restored cursor for job %s %v", j.name, cursor))
+	return nil
+}
+
+func (j *Job) commitCursor(ctx context.Context) {
+	if err := j.cursorStore.Save(ctx, j.name, j.cursor()); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("unable to commit cursor for job %s due to %s", j.name, err.Error()))
+	}
+}
+
+func (j *Job) mode() string {
+	if strings.ToUpper(j.jobConfig.Mode) == IncrementalMode {
+		return IncrementalMode
+	}
+	return FullResyncMode
+}
+
+// sourceName is the value stamped on the source field of published articles.
+func (j *Job) sourceName() string {
+	if j.jobConfig.SourceName != "" {
+		return j.jobConfig.SourceName
+	}
+	return j.name
+}
+
+func (j *Job) maxPagesPerRun() int {
+	if j.jobConfig.MaxPagesPerRun > 0 {
+		return j.jobConfig.MaxPagesPerRun
+	}
+	return defaultMaxPagesPerRun
 }
 
 func (j *Job) runTask(ctx context.Context) {
+	if j.Paused() {
+		log.Logger().Info(ctx, fmt.Sprintf("skipping paused job %s", j.Name()))
+		return
+	}
 
 	j.metrics.ReportScheduleOfJob(j.Name())
-	log.Logger().Info(ctx, fmt.Sprintf("executing job %s at %s", j.Name(), time.Now()))
 
 	j.stateMutex.Lock()
 	defer j.stateMutex.Unlock()
 
-	url := fmt.Sprintf("%s/?page=%d&pageSize=%d", j.jobConfig.ExternalAddrs, j.currentPage, pageSize)
+	// Another replica may have run the job since this one last did, before
+	// leadership moved here, so the stored cursor is the one to go on from.
+	if err := j.loadCursor(ctx); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("unable to reload cursor for job %s, going on from %v due to %s",
+			j.name, j.cursor(), err.Error()))
+	}
+
+	log.Logger().Info(ctx, fmt.Sprintf("executing job %s in %s mode at %s", j.Name(), j.mode(), time.Now()))
+
+	run := models.JobRun{StartedAt: time.Now(), Mode: j.mode()}
+
+	var err error
+	switch run.Mode {
+	case IncrementalMode:
+		err = j.runIncremental(ctx, &run)
+	default:
+		err = j.runFullResync(ctx, &run)
+	}
+
+	run.Latency = time.Since(run.StartedAt).String()
+	run.Succeeded = err == nil
+	if err != nil {
+		run.Error = err.Error()
+	}
+	j.history.add(run)
+}
+
+// runFullResync fetches the page under the cursor, publishes all of it and
+// moves to the next page, wrapping back to the first one at the end.
+func (j *Job) runFullResync(ctx context.Context, run *models.JobRun) error {
+	response, err := j.fetchPage(ctx, j.currentPage, run)
+	if err != nil {
+		return err
+	}
+	run.Pages++
+
+	if err := j.publish(ctx, response.Content); err != nil {
+		j.metrics.JobErrorInc(j.name, err.Error())
+		log.Logger().Error(ctx, fmt.Sprintf("job execution failed %v", map[string]interface{}{
+			"name":  j.Name(),
+			"mode":  FullResyncMode,
+			"error": err.Error(),
+		}))
+		return err
+	}
+	run.Articles += len(response.Content)
+
+	j.lastFetchTime = time.Now()
+
+	// Move to next page or wrap around
+	j.currentPage++
+	if j.currentPage >= j.maxPages {
+		j.currentPage = 0
+	}
+
+	j.commitCursor(ctx)
+	j.metrics.ReportPollRun(j.name, FullResyncMode, run.Pages, run.Articles)
+	return nil
+}
+
+// runIncremental pages from the newest article and stops at the first one at
+// or before the watermark. The upstream must list articles newest first. The
+// watermark only moves once it or the last page is reached; a run that uses up
+// its pages before that leaves a catch-up for the next runs to resume from, so
+// the articles between are not skipped.
+func (j *Job) runIncremental(ctx context.Context, run *models.JobRun) error {
+	newest, start := j.watermark, 0
+	if j.catchUp != nil {
+		newest, start = j.catchUp.Newest, j.catchUp.NextPage
+	}
+
+	page, done := start, false
+	for ; page < start+j.maxPagesPerRun(); page++ {
+		response, err := j.fetchPage(ctx, page, run)
+		if err != nil {
+			return err
+		}
+		run.Pages++
+
+		fresh, reached := newerThan(response.Content, j.watermark)
+		if err := j.publish(ctx, fresh); err != nil {
+			j.metrics.JobErrorInc(j.name, err.Error())
+			log.Logger().Error(ctx, fmt.Sprintf("job execution failed %v", map[string]interface{}{
+				"name":  j.Name(),
+				"mode":  IncrementalMode,
+				"page":  page,
+				"error": err.Error(),
+			}))
+			return err
+		}
+		run.Articles += len(fresh)
+
+		for _, article := range fresh {
+			if newest.IsBefore(article) {
+				newest = models.NewWatermark(article)
+			}
+		}
+
+		if reached || page+1 >= response.PageInfo.NumPages {
+			done = true
+			break
+		}
+	}
+
+	if done {
+		j.watermark, j.catchUp = newest, nil
+	} else {
+		j.catchUp = &models.CatchUp{NextPage: page, Newest: newest}
+		log.Logger().Info(ctx, fmt.Sprintf("job %s used up its pages before its watermark, resuming from page %d",
+			j.name, page))
+	}
+	j.lastFetchTime = time.Now()
+	j.commitCursor(ctx)
+
+	j.metrics.ReportPollRun(j.name, IncrementalMode, run.Pages, run.Articles)
+	if t, ok := j.watermark.Time(); ok {
+		j.metrics.ReportWatermark(j.name, t)
+	}
+	return nil
+}
+
+// newerThan keeps the articles after the watermark and reports whether the
+// watermark was reached on this page.
+func newerThan(articles []models.Article, watermark models.Watermark) ([]models.Article, bool) {
+	fresh := make([]models.Article, 0, len(articles))
+	reached := false
+	for _, article := range articles {
+		if watermark.IsBefore(article) {
+			fresh = append(fresh, article)
+		} else {
+			reached = true
+		}
+	}
+	return fresh, reached
+}
+
+// fetchPage requests one page from the source, retrying according to the job
+// configuration, and adds the attempts made to the run.
+func (j *Job) fetchPage(ctx context.Context, page int, run *models.JobRun) (models.ArticleResponse, error) {
+	var apiResponse models.ArticleResponse
+
+	url := j.source.BuildURL(j.jobConfig.ExternalAddrs, page, pageSize)
 	reqMethod := http.MethodGet
 
 	rb := cchttp.NewRequestBuilder().
@@ -89,12 +427,13 @@ func (j *Job) runTask(ctx context.Context) {
 
 	req, err := rb.Build()
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("unable to build the request for job %s due to %s", name, err.Error()))
+		log.Logger().Error(ctx, fmt.Sprintf("unable to build the request for job %s due to %s", j.name, err.Error()))
+		return apiResponse, err
 	}
 
 	retryDuration, err := time.ParseDuration(j.jobConfig.Retry.Duration)
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("invalid retry duration for job %s due to %s", name, err.Error()))
+		log.Logger().Error(ctx, fmt.Sprintf("invalid retry duration for job %s due to %s", j.name, err.Error()))
 	}
 	var responseCode int
 	var actualCallStart, actualCallEnd time.Time
@@ -119,33 +458,12 @@ func (j *Job) runTask(ctx context.Context) {
 				return fmt.Errorf("invalid response code %d", externalResponse.StatusCode)
 			}
 
-			// Unmarshal into our struct
-			var apiResponse models.ArticleResponse
-			if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil {
-				return fmt.Errorf("error unmarshaling response: %s", err.Error())
-			}
-
-			// Update max pages if needed
-			if apiResponse.PageInfo.NumPages > j.maxPages {
-				j.maxPages = apiResponse.PageInfo.NumPages
-			}
-
-			// Convert back to clean JSON
-			cleanJSON, err := json.Marshal(apiResponse.Content)
+			apiResponse, err = j.source.Decode(bodyBytes)
 			if err != nil {
-				return fmt.Errorf("error marshaling to JSON: %s", err.Error())
+				return err
 			}
 
 			j.msgQueueServ.PublishMessage(ctx, "SPORTSTREAM.DOCKER.status.updated", bodyBytes)
-
-			if _, err := j.msgQueueServ.PublishMessage(ctx, natsSubject, cleanJSON); err != nil {
-				return fmt.Errorf("error publishing to NATS: %s", err.Error())
-			}
-
-			log.Logger().Debug(ctx, fmt.Sprintf("Successfully published articles to NATS %v",
-				map[string]interface{}{
-					"article_count": len(apiResponse.Content), "subject": natsSubject,
-				}))
 		}
 
 		if err != nil {
@@ -161,41 +479,102 @@ func (j *Job) runTask(ctx context.Context) {
 	}()
 
 	retryResponse, err := retry.Run()
+	run.Attempts += retryResponse.NumberOfAttempts()
 	if err != nil {
 		log.Logger().Error(ctx, fmt.Sprintf("job execution failed %v", map[string]interface{}{
 			"name":             j.Name(),
+			"page":             page,
 			"numberOfAttempts": retryResponse.NumberOfAttempts(),
 			"error":            err.Error(),
 		}))
 
-		return
+		return apiResponse, err
 	}
 
 	if retryResponse.NumberOfAttempts() > 1 {
-		log.Logger().Info(ctx, fmt.Sprintf("retry succeeded", retryResponse.String()))
+		log.Logger().Info(ctx, fmt.Sprintf("retry succeeded %s", retryResponse.String()))
 	}
 
-	actualCallLatency := actualCallEnd.Sub(actualCallStart)
-	j.lastFetchTime = time.Now()
+	for i := range apiResponse.Content {
+		apiResponse.Content[i].Source = j.sourceName()
+	}
 
-	// Move to next page or wrap around
-	j.currentPage++
-	if j.currentPage >= j.maxPages {
-		j.currentPage = 0
+	// Update max pages if needed
+	if apiResponse.PageInfo.NumPages > j.maxPages {
+		j.maxPages = apiResponse.PageInfo.NumPages
 	}
+
+	actualCallLatency := actualCallEnd.Sub(actualCallStart)
 	log.Logger().Info(ctx, fmt.Sprintf("job execution completed %v", map[string]interface{}{
 		"name":          j.Name(),
+		"page":          page,
 		"time":          time.Now(),
 		"latency":       actualCallLatency,
 		"latencyString": actualCallLatency.String(),
 		"request":       req,
 	}))
+
+	return apiResponse, nil
+}
+
+// publish sends one message per article so a bad article cannot poison the
+// others. Every message carries a Nats-Msg-Id, so articles re-polled unchanged
+// within the stream duplicate window are dropped by the broker.
+func (j *Job) publish(ctx context.Context, articles []models.Article) error {
+	var errs []error
+	published, duplicates := 0, 0
+
+	for _, article := range articles {
+		// Convert back to clean JSON
+		cleanJSON, err := json.Marshal(article)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error marshaling article %d to JSON: %s", article.ID, err.Error()))
+			continue
+		}
+
+		env, err := envelope.New(ctx, msgtype.ArticlesUpdated, articleSchemaVersion, j.sourceName(), json.RawMessage(cleanJSON))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error wrapping article %d: %s", article.ID, err.Error()))
+			continue
+		}
+		data, err := json.Marshal(env)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error marshaling envelope of article %d to JSON: %s", article.ID, err.Error()))
+			continue
+		}
+
+		msg, err := j.msgQueueServ.PublishMessageWithID(ctx, natsSubject, messageID(article, cleanJSON), data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error publishing article %d to NATS: %s", article.ID, err.Error()))
+			continue
+		}
+
+		if msg.Duplicate {
+			duplicates++
+			continue
+		}
+		published++
+	}
+
+	log.Logger().Debug(ctx, fmt.Sprintf("Successfully published articles to NATS %v",
+		map[string]interface{}{
+			"source": j.sourceName(), "article_count": published, "duplicate_count": duplicates, "subject": natsSubject,
+		}))
+
+	return errors.Join(errs...)
+}
+
+// messageID identifies an article revision: the same source, external ID and
+// content always map to the same ID.
+func messageID(article models.Article, payload []byte) string {
+	hash := sha256.Sum256(payload)
+	return fmt.Sprintf("%s-%d-%s", article.Source, article.ID, hex.EncodeToString(hash[:16]))
 }
 
 func (j *Job) handleMetrics(start time.Time, method string, url string, responseCode int, err error) {
-	j.metrics.SchedulerOutgoingHttpRequest(start, method, url, responseCode, externalService)
+	j.metrics.SchedulerOutgoingHttpRequest(start, method, url, responseCode, j.name)
 	if err != nil {
-		j.metrics.JobErrorInc(name, err.Error())
+		j.metrics.JobErrorInc(j.name, err.Error())
 	}
-	j.metrics.SchedulerHTTPClientCall(start, method, url, responseCode, externalService)
+	j.metrics.SchedulerHTTPClientCall(start, method, url, responseCode, j.name)
 }
@@ -0,0 +1,429 @@
+package pooller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/sources/feed"
+	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/sources/pulselive"
+	"github.com/ronnyp07/SportStream/internal/domain/services/msgqueue/envelope"
+	"github.com/ronnyp07/SportStream/internal/domain/services/msgqueue/msgtype"
+	"github.com/ronnyp07/SportStream/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
+	natsQueue "github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/statestore"
+	"github.com/sts-solutions/base-code/cchttp"
+)
+
+type fakeMetrics struct{}
+
+func (fakeMetrics) RegisterMetrics()                                                    {}
+func (fakeMetrics) JobErrorInc(string, string)                                          {}
+func (fakeMetrics) SchedulerOutgoingHttpRequest(time.Time, string, string, int, string) {}
+func (fakeMetrics) SchedulerHTTPClientCall(time.Time, string, string, int, string)      {}
+func (fakeMetrics) ReportScheduleOfJob(string)                                          {}
+func (fakeMetrics) ReportPollRun(string, string, int, int)                              {}
+func (fakeMetrics) ReportWatermark(string, time.Time)                                   {}
+func (fakeMetrics) ReportConfigReload(string)                                           {}
+func (fakeMetrics) ReportLeader(bool)                                                   {}
+
+type fakeMsgQueue struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	msgIDs    []string
+}
+
+func (f *fakeMsgQueue) PublishMessage(_ context.Context, subject string, data []byte) (natsQueue.QueueMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.published == nil {
+		f.published = make(map[string][][]byte)
+	}
+	f.published[subject] = append(f.published[subject], data)
+	return natsQueue.QueueMessage{Subject: subject, Data: data}, nil
+}
+
+func (f *fakeMsgQueue) PublishMessageWithID(ctx context.Context, subject string, msgID string, data []byte) (natsQueue.QueueMessage, error) {
+	f.mu.Lock()
+	f.msgIDs = append(f.msgIDs, msgID)
+	f.mu.Unlock()
+	return f.PublishMessage(ctx, subject, data)
+}
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("PoolserviceTest"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestJob_RunTaskPublishesFeedArticles(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("..", "sources", "feed", "testdata", "rss.xml"))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	msgQueue := &fakeMsgQueue{}
+	job := New("county", feed.New(), nil, nil, cchttp.NewClient(0, 0, 0, time.Second), fakeMetrics{}, msgQueue, cursorStore)
+	job.jobConfig = config.Job{
+		ExternalAddrs: server.URL,
+		Retry:         config.Retry{MaxAttempts: 1, Duration: "1ms"},
+	}
+
+	job.runTask(context.Background())
+
+	committed, err := cursorStore.Get(context.Background(), "county")
+	if err != nil {
+		t.Fatalf("reading committed cursor: %v", err)
+	}
+	if committed.LastFetchTime.IsZero() || committed.MaxPages != 1 {
+		t.Errorf("expected the cursor to be committed, got %+v", committed)
+	}
+
+	messages := msgQueue.published[natsSubject]
+	if len(messages) != 2 {
+		t.Fatalf("expected one message per article on %s, got %d", natsSubject, len(messages))
+	}
+
+	for _, message := range messages {
+		article := decodeArticle(t, message)
+		if article.Source != "county" {
+			t.Errorf("expected source county, got %q", article.Source)
+		}
+	}
+
+	firstIDs := msgQueue.msgIDs
+	msgQueue.published, msgQueue.msgIDs = nil, nil
+	job.runTask(context.Background())
+	if len(msgQueue.msgIDs) != 2 || msgQueue.msgIDs[0] != firstIDs[0] || msgQueue.msgIDs[1] != firstIDs[1] {
+		t.Errorf("expected unchanged articles to keep their message IDs, got %v and %v", firstIDs, msgQueue.msgIDs)
+	}
+	if firstIDs[0] == firstIDs[1] {
+		t.Errorf("expected distinct message IDs per article, got %v", firstIDs)
+	}
+}
+
+func TestJob_CursorSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cursors.json")
+
+	store, err := statestore.NewFileStore(path)
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+	if err := store.Save(ctx, "ecb", models.Cursor{CurrentPage: 7, MaxPages: 40}); err != nil {
+		t.Fatalf("saving cursor: %v", err)
+	}
+
+	restarted, err := statestore.NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopening cursor store: %v", err)
+	}
+	job := New("ecb", feed.New(), nil, nil, nil, fakeMetrics{}, &fakeMsgQueue{}, restarted)
+	if err := job.restoreCursor(ctx); err != nil {
+		t.Fatalf("restoring cursor: %v", err)
+	}
+
+	cursor, _ := job.Cursor(ctx)
+	if cursor.CurrentPage != 7 || cursor.MaxPages != 40 {
+		t.Fatalf("expected to resume from page 7 of 40, got %+v", cursor)
+	}
+
+	if err := job.ResetCursor(ctx); err != nil {
+		t.Fatalf("resetting cursor: %v", err)
+	}
+	stored, _ := restarted.Get(ctx, "ecb")
+	if stored.CurrentPage != 0 || stored.MaxPages != 0 {
+		t.Fatalf("expected the stored cursor to be cleared, got %+v", stored)
+	}
+}
+
+func TestJob_IncrementalStopsAtWatermark(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	catalog := []models.Article{
+		{ID: 3, Date: "2024-05-03T10:00:00Z"},
+		{ID: 2, Date: "2024-05-02T10:00:00Z"},
+		{ID: 1, Date: "2024-05-01T10:00:00Z"},
+	}
+	var requestedPages []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requestedPages = append(requestedPages, page)
+
+		numPages := (len(catalog) + pageSize - 1) / pageSize
+		start := min(page*pageSize, len(catalog))
+		end := min(start+pageSize, len(catalog))
+		_ = json.NewEncoder(w).Encode(models.ArticleResponse{
+			PageInfo: models.PageInfo{Page: page, NumPages: numPages, PageSize: pageSize, NumEntries: len(catalog)},
+			Content:  catalog[start:end],
+		})
+	}))
+	defer server.Close()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	msgQueue := &fakeMsgQueue{}
+	job := New("ecb", pulselive.New(), nil, nil, cchttp.NewClient(0, 0, 0, time.Second), fakeMetrics{}, msgQueue, cursorStore)
+	job.jobConfig = config.Job{
+		Mode:          IncrementalMode,
+		ExternalAddrs: server.URL,
+		Retry:         config.Retry{MaxAttempts: 1, Duration: "1ms"},
+	}
+
+	job.runTask(ctx)
+
+	if got := publishedIDs(t, msgQueue); len(got) != 3 {
+		t.Fatalf("expected the first run to publish the whole catalog, got %v", got)
+	}
+	committed, _ := cursorStore.Get(ctx, "ecb")
+	if committed.Watermark.ID != 3 {
+		t.Fatalf("expected the watermark to point at article 3, got %+v", committed.Watermark)
+	}
+
+	mu.Lock()
+	catalog = append([]models.Article{{ID: 4, Date: "2024-05-04T10:00:00Z"}}, catalog...)
+	requestedPages = nil
+	mu.Unlock()
+	msgQueue.published = nil
+
+	job.runTask(ctx)
+
+	if got := publishedIDs(t, msgQueue); len(got) != 1 || got[0] != 4 {
+		t.Fatalf("expected only article 4 to be published, got %v", got)
+	}
+	if len(requestedPages) != 1 {
+		t.Fatalf("expected paging to stop at the watermark, fetched pages %v", requestedPages)
+	}
+}
+
+func TestJob_IncrementalCatchesUpWithinPageBudget(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	catalog := []models.Article{
+		{ID: 3, Date: "2024-05-03T10:00:00Z"},
+		{ID: 2, Date: "2024-05-02T10:00:00Z"},
+		{ID: 1, Date: "2024-05-01T10:00:00Z"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		numPages := (len(catalog) + pageSize - 1) / pageSize
+		start := min(page*pageSize, len(catalog))
+		end := min(start+pageSize, len(catalog))
+		_ = json.NewEncoder(w).Encode(models.ArticleResponse{
+			PageInfo: models.PageInfo{Page: page, NumPages: numPages, PageSize: pageSize, NumEntries: len(catalog)},
+			Content:  catalog[start:end],
+		})
+	}))
+	defer server.Close()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	msgQueue := &fakeMsgQueue{}
+	job := New("ecb", pulselive.New(), nil, nil, cchttp.NewClient(0, 0, 0, time.Second), fakeMetrics{}, msgQueue, cursorStore)
+	job.jobConfig = config.Job{
+		Mode:          IncrementalMode,
+		ExternalAddrs: server.URL,
+		Retry:         config.Retry{MaxAttempts: 1, Duration: "1ms"},
+	}
+	job.runTask(ctx)
+	msgQueue.published = nil
+
+	// A backlog of two pages, with a budget of one page per run.
+	mu.Lock()
+	catalog = append([]models.Article{
+		{ID: 7, Date: "2024-05-07T10:00:00Z"},
+		{ID: 6, Date: "2024-05-06T10:00:00Z"},
+		{ID: 5, Date: "2024-05-05T10:00:00Z"},
+		{ID: 4, Date: "2024-05-04T10:00:00Z"},
+	}, catalog...)
+	mu.Unlock()
+	job.jobConfig.MaxPagesPerRun = 1
+
+	job.runTask(ctx)
+
+	committed, _ := cursorStore.Get(ctx, "ecb")
+	if committed.Watermark.ID != 3 {
+		t.Fatalf("expected the watermark to stay at article 3 until it is reached, got %+v", committed.Watermark)
+	}
+	if committed.CatchUp == nil || committed.CatchUp.NextPage != 1 || committed.CatchUp.Newest.ID != 7 {
+		t.Fatalf("expected to resume from page 1 towards article 7, got %+v", committed.CatchUp)
+	}
+
+	job.runTask(ctx)
+	job.runTask(ctx)
+
+	if got := publishedIDs(t, msgQueue); len(got) != 4 || got[0] != 7 || got[1] != 6 || got[2] != 5 || got[3] != 4 {
+		t.Fatalf("expected the whole backlog to be published, got %v", got)
+	}
+	committed, _ = cursorStore.Get(ctx, "ecb")
+	if committed.Watermark.ID != 7 || committed.CatchUp != nil {
+		t.Fatalf("expected the watermark at article 7 once caught up, got %+v", committed)
+	}
+}
+
+func TestJob_RunGoesOnFromTheStoredCursor(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var requestedPages []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requestedPages = append(requestedPages, page)
+		_ = json.NewEncoder(w).Encode(models.ArticleResponse{
+			PageInfo: models.PageInfo{Page: page, NumPages: 3, PageSize: pageSize, NumEntries: 6},
+			Content:  []models.Article{{ID: 2*page + 1}, {ID: 2*page + 2}},
+		})
+	}))
+	defer server.Close()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	// Two replicas sharing the store, both configured at page 0; leadership
+	// moves from the first to the second after one run.
+	replicas := make([]*Job, 2)
+	for i := range replicas {
+		replicas[i] = New("ecb", pulselive.New(), nil, nil, cchttp.NewClient(0, 0, 0, time.Second), fakeMetrics{},
+			&fakeMsgQueue{}, cursorStore)
+		replicas[i].jobConfig = config.Job{
+			ExternalAddrs: server.URL,
+			Retry:         config.Retry{MaxAttempts: 1, Duration: "1ms"},
+		}
+		if err := replicas[i].restoreCursor(ctx); err != nil {
+			t.Fatalf("restoring cursor: %v", err)
+		}
+	}
+
+	replicas[0].runTask(ctx)
+	replicas[1].runTask(ctx)
+
+	if len(requestedPages) != 2 || requestedPages[0] != 0 || requestedPages[1] != 1 {
+		t.Fatalf("expected the new leader to go on from page 1, fetched pages %v", requestedPages)
+	}
+	committed, _ := cursorStore.Get(ctx, "ecb")
+	if committed.CurrentPage != 2 {
+		t.Errorf("expected the cursor at page 2, got %+v", committed)
+	}
+}
+
+func decodeArticle(t *testing.T, message []byte) models.Article {
+	t.Helper()
+
+	var env envelope.Envelope
+	if err := json.Unmarshal(message, &env); err != nil {
+		t.Fatalf("unexpected envelope: %v", err)
+	}
+	if env.Type != msgtype.ArticlesUpdated.Name() || env.SchemaVersion != articleSchemaVersion || env.Producer != envelope.Producer {
+		t.Fatalf("unexpected envelope header %+v", env)
+	}
+	if env.CorrelationID == "" || env.ProducedAt.IsZero() {
+		t.Errorf("expected correlation ID and produced-at time, got %+v", env)
+	}
+
+	var article models.Article
+	if err := json.Unmarshal(env.Payload, &article); err != nil {
+		t.Fatalf("unexpected payload: %v", err)
+	}
+	if env.Source != article.Source {
+		t.Errorf("expected envelope source %q, got %q", article.Source, env.Source)
+	}
+	return article
+}
+
+func publishedIDs(t *testing.T, msgQueue *fakeMsgQueue) []int {
+	t.Helper()
+
+	var ids []int
+	for _, message := range msgQueue.published[natsSubject] {
+		ids = append(ids, decodeArticle(t, message).ID)
+	}
+	return ids
+}
+
+func TestJob_RecordsRunsAndSkipsWhilePaused(t *testing.T) {
+	ctx := context.Background()
+
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.ArticleResponse{
+			PageInfo: models.PageInfo{NumPages: 1},
+			Content:  []models.Article{{ID: 1, Date: "2024-05-01T10:00:00Z"}},
+		})
+	}))
+	defer server.Close()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	job := New("ecb", pulselive.New(), nil, nil, cchttp.NewClient(0, 0, 0, time.Second), fakeMetrics{}, &fakeMsgQueue{}, cursorStore)
+	job.jobConfig = config.Job{
+		ExternalAddrs: server.URL,
+		Retry:         config.Retry{MaxAttempts: 2, Duration: "1ms"},
+	}
+
+	job.runTask(ctx)
+	failing = false
+	job.runTask(ctx)
+
+	job.Pause()
+	job.runTask(ctx)
+	job.Resume()
+
+	runs := job.Runs(10)
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 recorded runs, got %d", len(runs))
+	}
+	if !runs[0].Succeeded || runs[0].Attempts != 1 || runs[0].Articles != 1 {
+		t.Errorf("unexpected latest run %+v", runs[0])
+	}
+	if runs[1].Succeeded || runs[1].Attempts != 2 || runs[1].Error == "" {
+		t.Errorf("unexpected failed run %+v", runs[1])
+	}
+}
@@ -0,0 +1,113 @@
+package feed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestAdapter_DecodeRSS(t *testing.T) {
+	t.Parallel()
+
+	response, err := New().Decode(readFixture(t, "rss.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.PageInfo.NumPages != 1 || len(response.Content) != 2 {
+		t.Fatalf("expected a single page with 2 articles, got %+v", response.PageInfo)
+	}
+
+	article := response.Content[0]
+	if article.Title != "Surrey win by six wickets" {
+		t.Errorf("unexpected title %q", article.Title)
+	}
+	if article.Description != "Surrey chased down 250 at The Oval." {
+		t.Errorf("unexpected description %q", article.Description)
+	}
+	if article.Body != "<p>Surrey chased down 250 with ten balls to spare.</p>" {
+		t.Errorf("unexpected body %q", article.Body)
+	}
+	if article.Date != "2024-05-14T17:30:00Z" {
+		t.Errorf("unexpected date %q", article.Date)
+	}
+	if article.LeadMedia.URL != "https://example.com/img/oval.jpg" || article.LeadMedia.Type != "image/jpeg" {
+		t.Errorf("unexpected lead media %+v", article.LeadMedia)
+	}
+	if len(article.Tags) != 2 || article.Tags[1].Label != "County Championship" {
+		t.Errorf("unexpected tags %+v", article.Tags)
+	}
+	if article.ID <= 0 || article.ID == response.Content[1].ID {
+		t.Errorf("expected distinct positive ids, got %d and %d", article.ID, response.Content[1].ID)
+	}
+}
+
+func TestHashID(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"", "a", "https://example.com/news/1", "https://example.com/news/2", "county championship"}
+	seen := map[int]string{}
+	for _, key := range keys {
+		id := hashID(key)
+		if id < idSpace || id >= 2*idSpace {
+			t.Errorf("expected the id of %q outside the pulselive range and below 2^53, got %d", key, id)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("expected distinct ids, %q and %q share %d", key, other, id)
+		}
+		seen[id] = key
+		if hashID(" "+key+" ") != id {
+			t.Errorf("expected the id of %q to ignore surrounding space", key)
+		}
+	}
+}
+
+func TestAdapter_DecodeAtom(t *testing.T) {
+	t.Parallel()
+
+	response, err := New().Decode(readFixture(t, "atom.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(response.Content) != 1 {
+		t.Fatalf("expected 1 article, got %d", len(response.Content))
+	}
+
+	article := response.Content[0]
+	if article.Description != "Three uncapped players included." {
+		t.Errorf("unexpected description %q", article.Description)
+	}
+	if article.Body != "<p>The selectors named three uncapped players.</p>" {
+		t.Errorf("unexpected body %q", article.Body)
+	}
+	if article.Date != "2024-05-14T07:00:00Z" {
+		t.Errorf("unexpected date %q", article.Date)
+	}
+	if article.LeadMedia.URL != "https://example.com/video/squad.mp4" {
+		t.Errorf("unexpected lead media %+v", article.LeadMedia)
+	}
+	if len(article.Tags) != 2 || article.Tags[0].Label != "England" || article.Tags[1].Label != "tour" {
+		t.Errorf("unexpected tags %+v", article.Tags)
+	}
+}
+
+func TestAdapter_DecodeUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New().Decode([]byte(`<html><body/></html>`)); err == nil {
+		t.Fatal("expected an error for a non feed document")
+	}
+	if _, err := New().Decode([]byte(`{"content": []}`)); err == nil {
+		t.Fatal("expected an error for a json document")
+	}
+}
@@ -0,0 +1,34 @@
+package pulselive
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+)
+
+const (
+	Type = "PULSELIVE"
+)
+
+type Adapter struct{}
+
+func New() *Adapter {
+	return &Adapter{}
+}
+
+func (a *Adapter) Type() string {
+	return Type
+}
+
+func (a *Adapter) BuildURL(baseURL string, page int, pageSize int) string {
+	return fmt.Sprintf("%s/?page=%d&pageSize=%d", baseURL, page, pageSize)
+}
+
+func (a *Adapter) Decode(body []byte) (models.ArticleResponse, error) {
+	var apiResponse models.ArticleResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return apiResponse, fmt.Errorf("error unmarshaling response: %s", err.Error())
+	}
+	return apiResponse, nil
+}
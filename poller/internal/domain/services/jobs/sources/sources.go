@@ -0,0 +1,32 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/source"
+	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/sources/feed"
+	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/sources/pulselive"
+)
+
+// DefaultType is used when a job does not declare a source adapter.
+const DefaultType = pulselive.Type
+
+var adapters = map[string]func() ports.SourceAdapter{
+	pulselive.Type: func() ports.SourceAdapter { return pulselive.New() },
+	feed.Type:      func() ports.SourceAdapter { return feed.New() },
+}
+
+// New returns the source adapter registered under the given type.
+func New(sourceType string) (ports.SourceAdapter, error) {
+	if sourceType == "" {
+		sourceType = DefaultType
+	}
+
+	factory, ok := adapters[strings.ToUpper(sourceType)]
+	if !ok {
+		return nil, fmt.Errorf("unknown source adapter %s", sourceType)
+	}
+
+	return factory(), nil
+}
@@ -0,0 +1,58 @@
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/ronnyp07/SportStream/internal/domain/services/msgqueue/msgtype"
+	"github.com/sts-solutions/base-code/cccorrelation"
+)
+
+// Producer identifies this service in the envelopes it publishes.
+const Producer = "poller"
+
+// Envelope wraps every message published on the stream so consumers can
+// dispatch on its type and schema version.
+type Envelope struct {
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Producer      string          `json:"producer"`
+	Source        string          `json:"source"`
+	ProducedAt    time.Time       `json:"producedAt"`
+	CorrelationID string          `json:"correlationID"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// New wraps the payload in an envelope, taking the correlation ID from the
+// context or generating one when the context has none.
+func New(ctx context.Context, msgType msgtype.MessageType, schemaVersion int,
+	source string, payload any) (Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	ok, correlationID := cccorrelation.GetCorrelationId(ctx)
+	if !ok || correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+
+	return Envelope{
+		Type:          msgType.Name(),
+		SchemaVersion: schemaVersion,
+		Producer:      Producer,
+		Source:        source,
+		ProducedAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Payload:       data,
+	}, nil
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -35,3 +35,11 @@ func Get(id int32) MessageType {
 func (t MessageType) ID() int32 {
 	return int32(t)
 }
+
+func (t MessageType) Name() string {
+	if name, ok := namesMessageType[t]; ok {
+		return name
+	}
+
+	return namesMessageType[NotSet]
+}
@@ -4,50 +4,99 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/ronnyp07/SportStream/internal/domain/models"
+	election_port "github.com/ronnyp07/SportStream/internal/domain/ports/election"
 	ports "github.com/ronnyp07/SportStream/internal/domain/ports/job"
+	jobbuilder_port "github.com/ronnyp07/SportStream/internal/domain/ports/jobbuilder"
 	"github.com/ronnyp07/SportStream/internal/domain/ports/metrics"
+	state_port "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
 	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/jobbuilder"
 	pooller "github.com/ronnyp07/SportStream/internal/domain/services/jobs/poller"
+	"github.com/ronnyp07/SportStream/internal/domain/services/jobs/sources"
 	"github.com/ronnyp07/SportStream/internal/pkg/config"
 	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
 	natsQueue "github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
 	"github.com/sts-solutions/base-code/cchttp"
 )
 
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobPaused   = errors.New("job is paused")
+	// ErrNotLeader is returned for runs asked of a replica that isn't the
+	// leader, whose scheduler would skip them.
+	ErrNotLeader = errors.New("this poller instance is not the leader")
+)
+
+const (
+	ReloadApplied  = "applied"
+	ReloadRejected = "rejected"
+)
+
 type Service struct {
 	scheduler      gocron.Scheduler
+	jobBuilder     jobbuilder_port.JobBuilder
+	httpclient     cchttp.Client
 	scheduledJobs  []ports.IJob
 	jobsConfig     config.Jobs
+	jobsMutex      sync.RWMutex
 	metricsHandler metrics.SchedulerMetricsHandler
 	msgQueueServ   natsQueue.MsgQueueService
+	cursorStore    state_port.CursorStore
+	elector        election_port.Elector
 }
 
 func NewService(jobsConfig config.Jobs,
 	metrics metrics.SchedulerMetricsHandler,
-	msgQueueServ natsQueue.MsgQueueService) (*Service, error) {
-	sch, err := gocron.NewScheduler()
+	msgQueueServ natsQueue.MsgQueueService,
+	cursorStore state_port.CursorStore,
+	elector election_port.Elector) (*Service, error) {
+	var options []gocron.SchedulerOption
+	if elector != nil {
+		options = append(options, gocron.WithDistributedElector(elector))
+	}
+
+	sch, err := gocron.NewScheduler(options...)
 	if err != nil {
-		log.Logger().Fatal(context.Background(), fmt.Sprintf("Cannot create go cron scheduler due to %w", err))
+		log.Logger().Fatal(context.Background(), fmt.Sprintf("Cannot create go cron scheduler due to %v", err))
 		return nil, err
 	}
 
-	jobBuilder := jobbuilder.New(sch)
-	httpclient := cchttp.NewClient(0, 0, 0, time.Minute)
-
-	poollerJob := pooller.New(sch, jobBuilder, httpclient, metrics, msgQueueServ)
-
-	return &Service{
-		scheduler: sch,
-		scheduledJobs: []ports.IJob{
-			poollerJob,
-		},
+	s := &Service{
+		scheduler:      sch,
+		jobBuilder:     jobbuilder.New(sch),
+		httpclient:     cchttp.NewClient(0, 0, 0, time.Minute),
+		scheduledJobs:  make([]ports.IJob, 0, len(jobsConfig)),
 		jobsConfig:     jobsConfig,
 		metricsHandler: metrics,
-	}, nil
+		msgQueueServ:   msgQueueServ,
+		cursorStore:    cursorStore,
+		elector:        elector,
+	}
+
+	for jobName, jobConfig := range jobsConfig {
+		job, err := s.newJob(jobName, jobConfig)
+		if err != nil {
+			log.Logger().Error(context.Background(), fmt.Sprintf("Skipping job %s due to %v", jobName, err))
+			continue
+		}
+		s.scheduledJobs = append(s.scheduledJobs, job)
+	}
+
+	return s, nil
+}
+
+func (s *Service) newJob(name string, jobConfig config.Job) (ports.IJob, error) {
+	source, err := sources.New(jobConfig.Source)
+	if err != nil {
+		return nil, err
+	}
+	return pooller.New(name, source, s.scheduler, s.jobBuilder, s.httpclient,
+		s.metricsHandler, s.msgQueueServ, s.cursorStore), nil
 }
 
 func (s *Service) Start(ctx context.Context) {
@@ -66,27 +115,110 @@ func (s *Service) GetAllJobs(ctx context.Context) ([]models.Job, error) {
 	log.Logger().Info(ctx, "Preparing to get all jobs")
 	jobs := make([]models.Job, 0)
 	for _, job := range s.scheduler.Jobs() {
-		jobs = append(jobs, fromLibraryToDomain(job))
+		jobs = append(jobs, s.toDomain(job))
 	}
 	if len(jobs) == 0 {
-		return nil, errors.New("job not found")
+		return nil, ErrJobNotFound
 	}
 	log.Logger().Info(ctx, fmt.Sprintf("Getting all jobs %v", jobs))
 	return jobs, nil
 }
 
+// RunJobNow triggers the job with the given scheduler ID or name immediately.
+// Only the leader runs jobs, so other replicas refuse with ErrNotLeader.
 func (s *Service) RunJobNow(ctx context.Context, ID string) (models.Job, error) {
 	log.Logger().Info(ctx, fmt.Sprintf("Run job now %v", ID))
 	for _, scheduledJob := range s.scheduler.Jobs() {
-		if scheduledJob.ID().String() == ID {
-			log.Logger().Info(ctx, fmt.Sprintf("Run job now %v", ID))
-			return fromLibraryToDomain(scheduledJob), scheduledJob.RunNow()
+		if scheduledJob.ID().String() == ID || scheduledJob.Name() == ID {
+			if job, err := s.scheduledJob(scheduledJob.Name()); err == nil && job.Paused() {
+				return s.toDomain(scheduledJob), ErrJobPaused
+			}
+			if s.elector != nil && s.elector.IsLeader(ctx) != nil {
+				return s.toDomain(scheduledJob), ErrNotLeader
+			}
+			return s.toDomain(scheduledJob), scheduledJob.RunNow()
+		}
+	}
+	return models.Job{}, ErrJobNotFound
+}
+
+// PauseJob pauses the job on this replica only. The pause is kept in memory,
+// so it's lost on restart and doesn't follow leadership to another replica.
+func (s *Service) PauseJob(ctx context.Context, name string) (models.Job, error) {
+	log.Logger().Info(ctx, fmt.Sprintf("Pause job %v", name))
+	job, err := s.scheduledJob(name)
+	if err != nil {
+		return models.Job{}, err
+	}
+	job.Pause()
+	return s.GetJob(ctx, name)
+}
+
+func (s *Service) ResumeJob(ctx context.Context, name string) (models.Job, error) {
+	log.Logger().Info(ctx, fmt.Sprintf("Resume job %v", name))
+	job, err := s.scheduledJob(name)
+	if err != nil {
+		return models.Job{}, err
+	}
+	job.Resume()
+	return s.GetJob(ctx, name)
+}
+
+func (s *Service) GetJob(_ context.Context, name string) (models.Job, error) {
+	for _, scheduledJob := range s.scheduler.Jobs() {
+		if scheduledJob.Name() == name {
+			return s.toDomain(scheduledJob), nil
+		}
+	}
+	return models.Job{}, ErrJobNotFound
+}
+
+// GetJobRuns returns up to limit of the most recent run outcomes of a job, newest first.
+func (s *Service) GetJobRuns(_ context.Context, name string, limit int) ([]models.JobRun, error) {
+	job, err := s.scheduledJob(name)
+	if err != nil {
+		return nil, err
+	}
+	return job.Runs(limit), nil
+}
+
+func (s *Service) GetCursor(ctx context.Context, name string) (models.Cursor, error) {
+	job, err := s.scheduledJob(name)
+	if err != nil {
+		return models.Cursor{}, err
+	}
+	return job.Cursor(ctx)
+}
+
+func (s *Service) ResetCursor(ctx context.Context, name string) error {
+	log.Logger().Info(ctx, fmt.Sprintf("Reset cursor of job %v", name))
+	job, err := s.scheduledJob(name)
+	if err != nil {
+		return err
+	}
+	return job.ResetCursor(ctx)
+}
+
+func (s *Service) scheduledJob(name string) (ports.IJob, error) {
+	s.jobsMutex.RLock()
+	defer s.jobsMutex.RUnlock()
+
+	return s.findJob(name)
+}
+
+func (s *Service) findJob(name string) (ports.IJob, error) {
+	for _, scheduledJob := range s.scheduledJobs {
+		if scheduledJob.Name() == name {
+			return scheduledJob, nil
 		}
 	}
-	return models.Job{}, errors.New("not found")
+	return nil, ErrJobNotFound
 }
 
 func (s *Service) configureJobs(ctx context.Context) {
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
 	log.Logger().Info(ctx, "Configuring all jobs")
 	for _, scheduledJob := range s.scheduledJobs {
 		jobName := scheduledJob.Name()
@@ -96,6 +228,143 @@ func (s *Service) configureJobs(ctx context.Context) {
 	}
 }
 
+// Reload applies a new JOBS configuration: changed jobs are rescheduled in
+// place, newly enabled jobs are added and disabled or deleted jobs are removed.
+// The whole reload is rejected when any enabled job is invalid.
+func (s *Service) Reload(ctx context.Context, jobsConfig config.Jobs) error {
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
+	if err := s.validateJobs(jobsConfig); err != nil {
+		s.RejectReload(ctx, err)
+		return err
+	}
+
+	var errs []error
+	changes := 0
+	for jobName, jobConfig := range jobsConfig {
+		job, err := s.findJob(jobName)
+		if err == nil && job.Config() == jobConfig {
+			continue
+		}
+
+		changes++
+		if err := s.applyJob(ctx, job, jobName, jobConfig); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", jobName, err))
+		}
+	}
+
+	remaining := s.scheduledJobs[:0]
+	for _, job := range s.scheduledJobs {
+		if _, ok := jobsConfig[job.Name()]; ok {
+			remaining = append(remaining, job)
+			continue
+		}
+
+		changes++
+		if err := job.Remove(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", job.Name(), err))
+			remaining = append(remaining, job)
+			continue
+		}
+		log.Logger().Info(ctx, fmt.Sprintf("Removed job %s from the schedule", job.Name()))
+	}
+	s.scheduledJobs = remaining
+	s.jobsConfig = jobsConfig
+
+	if err := errors.Join(errs...); err != nil {
+		s.RejectReload(ctx, err)
+		return err
+	}
+
+	if changes == 0 {
+		log.Logger().Info(ctx, "Jobs config reloaded without changes")
+		return nil
+	}
+
+	s.metricsHandler.ReportConfigReload(ReloadApplied)
+	log.Logger().Info(ctx, fmt.Sprintf("Applied jobs config reload with %d changes", changes))
+	return nil
+}
+
+// RejectReload records a reload that could not be applied.
+func (s *Service) RejectReload(ctx context.Context, err error) {
+	s.metricsHandler.ReportConfigReload(ReloadRejected)
+	log.Logger().Error(ctx, fmt.Sprintf("Rejected jobs config reload due to %v", err))
+}
+
+// applyJob adds a new job or reconfigures an existing one. A job whose source
+// changed is replaced since the adapter is fixed at construction.
+func (s *Service) applyJob(ctx context.Context, job ports.IJob, jobName string, jobConfig config.Job) error {
+	if job != nil && sameSource(job.Config(), jobConfig) {
+		log.Logger().Info(ctx, fmt.Sprintf("Reconfiguring job %s", jobName))
+		return job.Reconfigure(ctx, jobConfig)
+	}
+
+	if job != nil {
+		if err := job.Remove(ctx); err != nil {
+			return err
+		}
+		s.dropJob(jobName)
+	}
+
+	newJob, err := s.newJob(jobName, jobConfig)
+	if err != nil {
+		return err
+	}
+	log.Logger().Info(ctx, fmt.Sprintf("Adding job %s", jobName))
+	if err := newJob.Configure(ctx, jobConfig); err != nil {
+		return err
+	}
+	s.scheduledJobs = append(s.scheduledJobs, newJob)
+	return nil
+}
+
+func (s *Service) dropJob(name string) {
+	for i, job := range s.scheduledJobs {
+		if job.Name() == name {
+			s.scheduledJobs = append(s.scheduledJobs[:i], s.scheduledJobs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Service) validateJobs(jobsConfig config.Jobs) error {
+	var errs []error
+	for jobName, jobConfig := range jobsConfig {
+		if !jobConfig.Enabled {
+			continue
+		}
+		if _, err := sources.New(jobConfig.Source); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", jobName, err))
+		}
+		if err := s.jobBuilder.ValidateJob(jobConfig); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", jobName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sameSource(current, next config.Job) bool {
+	return sourceType(current) == sourceType(next)
+}
+
+func sourceType(jobConfig config.Job) string {
+	if jobConfig.Source == "" {
+		return sources.DefaultType
+	}
+	return strings.ToUpper(jobConfig.Source)
+}
+
+func (s *Service) toDomain(job gocron.Job) models.Job {
+	domainJob := fromLibraryToDomain(job)
+	if scheduledJob, err := s.scheduledJob(job.Name()); err == nil && scheduledJob.Paused() {
+		domainJob.Paused = true
+		domainJob.NextRun = time.Time{}
+	}
+	return domainJob
+}
+
 func fromLibraryToDomain(job gocron.Job) models.Job {
 	nextRun, _ := job.NextRun()
 	lastRun, _ := job.LastRun()
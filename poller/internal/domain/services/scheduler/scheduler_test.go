@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
+	natsQueue "github.com/ronnyp07/SportStream/internal/pkg/infaestructure/msgqueue"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/statestore"
+)
+
+type fakeMetrics struct {
+	reloads map[string]int
+}
+
+func (fakeMetrics) RegisterMetrics()                                                    {}
+func (fakeMetrics) JobErrorInc(string, string)                                          {}
+func (fakeMetrics) SchedulerOutgoingHttpRequest(time.Time, string, string, int, string) {}
+func (fakeMetrics) SchedulerHTTPClientCall(time.Time, string, string, int, string)      {}
+func (fakeMetrics) ReportScheduleOfJob(string)                                          {}
+func (fakeMetrics) ReportPollRun(string, string, int, int)                              {}
+func (fakeMetrics) ReportWatermark(string, time.Time)                                   {}
+func (m fakeMetrics) ReportConfigReload(status string)                                  { m.reloads[status]++ }
+func (fakeMetrics) ReportLeader(bool)                                                   {}
+
+type fakeMsgQueue struct{}
+
+func (fakeMsgQueue) PublishMessage(_ context.Context, subject string, data []byte) (natsQueue.QueueMessage, error) {
+	return natsQueue.QueueMessage{Subject: subject, Data: data}, nil
+}
+
+func (f fakeMsgQueue) PublishMessageWithID(ctx context.Context, subject string, _ string, data []byte) (natsQueue.QueueMessage, error) {
+	return f.PublishMessage(ctx, subject, data)
+}
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("PoolserviceTest"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func hourlyJob(interval string) config.Job {
+	return config.Job{
+		Enabled:  true,
+		Source:   "PULSELIVE",
+		Type:     "CRONJOB",
+		Interval: interval,
+		Retry:    config.Retry{MaxAttempts: 1, Duration: "1s"},
+	}
+}
+
+func jobNames(t *testing.T, s *Service) []string {
+	t.Helper()
+
+	jobs, err := s.GetAllJobs(context.Background())
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestService_Reload(t *testing.T) {
+	ctx := context.Background()
+
+	cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+	if err != nil {
+		t.Fatalf("creating cursor store: %v", err)
+	}
+
+	metrics := fakeMetrics{reloads: map[string]int{}}
+	s, err := NewService(config.Jobs{
+		"ecb":    hourlyJob("0 * * * *"),
+		"county": hourlyJob("30 * * * *"),
+	}, metrics, fakeMsgQueue{}, cursorStore, nil)
+	if err != nil {
+		t.Fatalf("creating service: %v", err)
+	}
+	s.Start(ctx)
+	defer s.Shutdown()
+
+	before, _ := s.GetJob(ctx, "ecb")
+
+	disabled := hourlyJob("30 * * * *")
+	disabled.Enabled = false
+	err = s.Reload(ctx, config.Jobs{
+		"ecb":    hourlyJob("15 * * * *"),
+		"county": disabled,
+		"feed":   hourlyJob("45 * * * *"),
+	})
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+
+	if got := jobNames(t, s); len(got) != 2 || got[0] != "ecb" || got[1] != "feed" {
+		t.Fatalf("expected ecb and feed to be scheduled, got %v", got)
+	}
+	after, _ := s.GetJob(ctx, "ecb")
+	if after.ID != before.ID {
+		t.Errorf("expected ecb to be rescheduled in place, ID changed from %s to %s", before.ID, after.ID)
+	}
+	if after.NextRun.Minute() != 15 {
+		t.Errorf("expected ecb to run at minute 15, next run %v", after.NextRun)
+	}
+
+	err = s.Reload(ctx, config.Jobs{
+		"ecb":  hourlyJob("not a cron"),
+		"feed": hourlyJob("45 * * * *"),
+	})
+	if err == nil {
+		t.Fatal("expected an invalid interval to reject the reload")
+	}
+	if got := jobNames(t, s); len(got) != 2 {
+		t.Fatalf("expected a rejected reload to leave the schedule untouched, got %v", got)
+	}
+
+	if metrics.reloads[ReloadApplied] != 1 || metrics.reloads[ReloadRejected] != 1 {
+		t.Errorf("unexpected reload metrics %v", metrics.reloads)
+	}
+}
+
+// fakeElector reports whether this replica leads.
+type fakeElector struct {
+	leader bool
+}
+
+func (e fakeElector) IsLeader(context.Context) error {
+	if !e.leader {
+		return errors.New("not the leader")
+	}
+	return nil
+}
+
+func (fakeElector) Start(context.Context)        {}
+func (fakeElector) Resign(context.Context) error { return nil }
+
+func TestService_RunJobNow(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		leader  bool
+		paused  bool
+		wantErr error
+	}{
+		{name: "leader runs the job", leader: true},
+		{name: "follower refuses", wantErr: ErrNotLeader},
+		{name: "paused job refused", leader: true, paused: true, wantErr: ErrJobPaused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursorStore, err := statestore.NewFileStore(filepath.Join(t.TempDir(), "cursors.json"))
+			if err != nil {
+				t.Fatalf("creating cursor store: %v", err)
+			}
+			s, err := NewService(config.Jobs{"ecb": hourlyJob("0 * * * *")},
+				fakeMetrics{reloads: map[string]int{}}, fakeMsgQueue{}, cursorStore, fakeElector{leader: tt.leader})
+			if err != nil {
+				t.Fatalf("creating service: %v", err)
+			}
+			s.Start(ctx)
+			defer s.Shutdown()
+			if tt.paused {
+				if _, err := s.PauseJob(ctx, "ecb"); err != nil {
+					t.Fatalf("pausing: %v", err)
+				}
+			}
+
+			_, err = s.RunJobNow(ctx, "ecb")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
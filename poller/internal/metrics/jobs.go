@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -8,6 +10,13 @@ import (
 type jobMetrics struct {
 	jobScheduled *prometheus.CounterVec
 	jobErrorInc  *prometheus.CounterVec
+
+	pollRuns          *prometheus.CounterVec
+	pagesFetched      *prometheus.CounterVec
+	articlesPublished *prometheus.CounterVec
+	watermark         *prometheus.GaugeVec
+	configReloads     *prometheus.CounterVec
+	leader            *prometheus.GaugeVec
 }
 
 func (m *schedulerMetricsHandler) registerJobMetrics() {
@@ -28,6 +37,60 @@ func (m *schedulerMetricsHandler) registerJobMetrics() {
 		},
 		[]string{"host", "shard", "job_name", "reason"},
 	)
+
+	m.job.pollRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "poll_runs_total",
+			Help:      "The total number of completed poll runs by mode",
+		},
+		[]string{"host", "shard", "job_name", "mode"},
+	)
+
+	m.job.pagesFetched = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "poll_pages_fetched_total",
+			Help:      "The total number of upstream pages fetched by mode",
+		},
+		[]string{"host", "shard", "job_name", "mode"},
+	)
+
+	m.job.articlesPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "poll_articles_published_total",
+			Help:      "The total number of articles published by mode",
+		},
+		[]string{"host", "shard", "job_name", "mode"},
+	)
+
+	m.job.watermark = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.namespace,
+			Name:      "poll_watermark_timestamp_seconds",
+			Help:      "Publication time of the newest article seen by incremental jobs",
+		},
+		[]string{"host", "shard", "job_name"},
+	)
+
+	m.job.configReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "jobs_config_reloads_total",
+			Help:      "The total number of jobs config reloads by status",
+		},
+		[]string{"host", "shard", "status"},
+	)
+
+	m.job.leader = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.namespace,
+			Name:      "scheduler_leader",
+			Help:      "1 when this instance is the elected scheduler leader, 0 otherwise",
+		},
+		[]string{"host", "shard"},
+	)
 }
 
 // ReportScheduleOfJob reports a scheduled job
@@ -41,3 +104,32 @@ func (m *schedulerMetricsHandler) JobErrorInc(jobName string, errMsg string) {
 	labels := m.baseLabelsWithValues(jobName, errMsg)
 	m.job.jobErrorInc.WithLabelValues(labels...).Inc()
 }
+
+// ReportPollRun reports a completed poll run with the pages fetched and articles published
+func (m *schedulerMetricsHandler) ReportPollRun(jobName string, mode string, pages int, articles int) {
+	labels := m.baseLabelsWithValues(jobName, mode)
+	m.job.pollRuns.WithLabelValues(labels...).Inc()
+	m.job.pagesFetched.WithLabelValues(labels...).Add(float64(pages))
+	m.job.articlesPublished.WithLabelValues(labels...).Add(float64(articles))
+}
+
+// ReportWatermark reports the high-water mark of an incremental job
+func (m *schedulerMetricsHandler) ReportWatermark(jobName string, watermark time.Time) {
+	labels := m.baseLabelsWithValues(jobName)
+	m.job.watermark.WithLabelValues(labels...).Set(float64(watermark.Unix()))
+}
+
+// ReportConfigReload reports an applied or rejected jobs config reload
+func (m *schedulerMetricsHandler) ReportConfigReload(status string) {
+	labels := m.baseLabelsWithValues(status)
+	m.job.configReloads.WithLabelValues(labels...).Inc()
+}
+
+// ReportLeader reports whether this instance currently leads the schedule
+func (m *schedulerMetricsHandler) ReportLeader(leader bool) {
+	value := 0.0
+	if leader {
+		value = 1
+	}
+	m.job.leader.WithLabelValues(m.baseLabels()...).Set(value)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -17,9 +18,12 @@ const (
 )
 
 type AppConfig struct {
-	Env           Environment   `mapstructure:"ENVIRONMENT"`
-	Observability Observability `mapstructure:"OBSERVABILITY"`
-	Jobs          Jobs          `mapstructure:"JOBS"`
+	Env            Environment    `mapstructure:"ENVIRONMENT"`
+	Observability  Observability  `mapstructure:"OBSERVABILITY"`
+	Jobs           Jobs           `mapstructure:"JOBS"`
+	StateStore     StateStore     `mapstructure:"STATE_STORE"`
+	Http           Http           `mapstructure:"HTTP"`
+	LeaderElection LeaderElection `mapstructure:"LEADER_ELECTION"`
 }
 
 type Environment struct {
@@ -64,12 +68,16 @@ type Tracing struct {
 }
 
 type Job struct {
-	Enabled       bool   `mapstructure:"ENABLED"`
-	Type          string `mapstructure:"TYPE"`
-	Interval      string `mapstructure:"INTERVAL"`
-	UseSeconds    bool   `mapstructure:"USESECONDS"`
-	ExternalAddrs string `mapstructure:"EXTERNALADDRESS"`
-	Retry         Retry  `mapstructure:"RETRY"`
+	Enabled        bool   `mapstructure:"ENABLED"`
+	Source         string `mapstructure:"SOURCE"`
+	SourceName     string `mapstructure:"SOURCENAME"`
+	Mode           string `mapstructure:"MODE"`
+	MaxPagesPerRun int    `mapstructure:"MAXPAGESPERRUN"`
+	Type           string `mapstructure:"TYPE"`
+	Interval       string `mapstructure:"INTERVAL"`
+	UseSeconds     bool   `mapstructure:"USESECONDS"`
+	ExternalAddrs  string `mapstructure:"EXTERNALADDRESS"`
+	Retry          Retry  `mapstructure:"RETRY"`
 }
 
 type Retry struct {
@@ -79,6 +87,54 @@ type Retry struct {
 
 type Jobs map[string]Job
 
+type Http struct {
+	HostAddress  string        `mapstructure:"HOST_ADDRESS"`
+	ReadTimeout  time.Duration `mapstructure:"READ_TIMEOUT"`
+	WriteTimeout time.Duration `mapstructure:"WRITE_TIMEOUT"`
+	// AdminToken is the bearer token the admin API requires. When empty the
+	// admin API only answers requests from localhost.
+	AdminToken string `mapstructure:"ADMIN_TOKEN"`
+}
+
+type LeaderElection struct {
+	Enabled bool          `mapstructure:"ENABLED"`
+	Bucket  string        `mapstructure:"BUCKET"`
+	TTL     time.Duration `mapstructure:"TTL"`
+}
+
+type StateStore struct {
+	Type   string `mapstructure:"TYPE"`
+	Bucket string `mapstructure:"BUCKET"`
+	Path   string `mapstructure:"PATH"`
+}
+
+// JobsChangeHandler receives the JOBS section after every config file change,
+// or the error that prevented it from being loaded.
+type JobsChangeHandler func(jobs Jobs, err error)
+
+var (
+	jobsChangeLock     = &sync.Mutex{}
+	jobsChangeHandlers []JobsChangeHandler
+)
+
+// OnJobsChange registers a handler called whenever the config file changes.
+func OnJobsChange(handler JobsChangeHandler) {
+	jobsChangeLock.Lock()
+	defer jobsChangeLock.Unlock()
+
+	jobsChangeHandlers = append(jobsChangeHandlers, handler)
+}
+
+func notifyJobsChange(jobs Jobs, err error) {
+	jobsChangeLock.Lock()
+	handlers := append([]JobsChangeHandler(nil), jobsChangeHandlers...)
+	jobsChangeLock.Unlock()
+
+	for _, handler := range handlers {
+		handler(jobs, err)
+	}
+}
+
 func loadApplicationConfig() (config *AppConfig, err error) {
 	config = &AppConfig{}
 	name := "config"
@@ -107,10 +163,17 @@ func loadApplicationConfig() (config *AppConfig, err error) {
 
 	var viperErr error
 	viper.OnConfigChange(func(e fsnotify.Event) {
-		err = viper.Unmarshal(&config)
-		if err != nil {
+		reloaded := &AppConfig{}
+		if err := viper.Unmarshal(&reloaded); err != nil {
 			viperErr = errors.Wrap(err, "loading new config")
+			notifyJobsChange(nil, viperErr)
+			return
 		}
+
+		cfgLock.Lock()
+		*config = *reloaded
+		cfgLock.Unlock()
+		notifyJobsChange(reloaded.Jobs, nil)
 	})
 
 	viper.WatchConfig()
@@ -0,0 +1,165 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/election"
+	"github.com/ronnyp07/SportStream/internal/domain/ports/metrics"
+	"github.com/ronnyp07/SportStream/internal/pkg/infaestructure/log"
+)
+
+const (
+	leaderKey = "leader"
+
+	defaultBucket = "poller_leader"
+	defaultTTL    = 15 * time.Second
+)
+
+var ErrNotLeader = errors.New("instance is not the leader")
+
+type kvElector struct {
+	kv       nats.KeyValue
+	instance string
+	ttl      time.Duration
+	metrics  metrics.SchedulerMetricsHandler
+
+	mu       sync.Mutex
+	leader   bool
+	revision uint64
+	resigned bool
+	stop     context.CancelFunc
+}
+
+// NewKVElector elects a leader through a JetStream key-value bucket whose
+// entries expire after ttl. The leader keeps refreshing its key; when it
+// stops, the key expires and another instance takes over.
+func NewKVElector(js nats.JetStreamContext, bucket string, ttl time.Duration,
+	instance string, metrics metrics.SchedulerMetricsHandler) (ports.Elector, error) {
+	if bucket == "" {
+		bucket = defaultBucket
+	}
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "poller leader election",
+			History:     1,
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "opening leader bucket")
+	}
+
+	return &kvElector{
+		kv:       kv,
+		instance: instance,
+		ttl:      ttl,
+		metrics:  metrics,
+	}, nil
+}
+
+// IsLeader implements gocron.Elector.
+func (e *kvElector) IsLeader(_ context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.leader {
+		return ErrNotLeader
+	}
+	return nil
+}
+
+// Start campaigns for leadership and keeps refreshing it until ctx is done or
+// the instance resigns.
+func (e *kvElector) Start(ctx context.Context) {
+	ctx, stop := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.stop = stop
+	e.mu.Unlock()
+
+	e.campaign(ctx)
+
+	ticker := time.NewTicker(e.ttl / 3)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.campaign(ctx)
+			}
+		}
+	}()
+}
+
+// Resign stops campaigning and gives up leadership, so another instance can
+// take over without waiting for the key to expire.
+func (e *kvElector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.resigned = true
+	if e.stop != nil {
+		e.stop()
+	}
+	if !e.leader {
+		return nil
+	}
+
+	e.setLeader(ctx, false)
+	if err := e.kv.Delete(leaderKey, nats.LastRevision(e.revision)); err != nil {
+		return errors.Wrap(err, "releasing leadership")
+	}
+	return nil
+}
+
+func (e *kvElector) campaign(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.resigned {
+		return
+	}
+
+	if e.leader {
+		revision, err := e.kv.Update(leaderKey, []byte(e.instance), e.revision)
+		if err != nil {
+			log.Logger().Error(ctx, fmt.Sprintf("instance %s lost leadership due to %v", e.instance, err))
+			e.setLeader(ctx, false)
+			return
+		}
+		e.revision = revision
+		return
+	}
+
+	revision, err := e.kv.Create(leaderKey, []byte(e.instance))
+	if err != nil {
+		if !errors.Is(err, nats.ErrKeyExists) {
+			log.Logger().Error(ctx, fmt.Sprintf("instance %s unable to campaign for leadership due to %v", e.instance, err))
+		}
+		e.setLeader(ctx, false)
+		return
+	}
+
+	e.revision = revision
+	e.setLeader(ctx, true)
+}
+
+func (e *kvElector) setLeader(ctx context.Context, leader bool) {
+	if leader != e.leader {
+		log.Logger().Info(ctx, fmt.Sprintf("instance %s leader changed to %t", e.instance, leader))
+	}
+	e.leader = leader
+	e.metrics.ReportLeader(leader)
+}
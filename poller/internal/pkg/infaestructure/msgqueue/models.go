@@ -7,9 +7,10 @@ const (
 )
 
 type QueueMessage struct {
-	Header  map[string][]string
-	Subject string
-	Data    []byte
+	Header    map[string][]string
+	Subject   string
+	Data      []byte
+	Duplicate bool
 }
 
 func (q QueueMessage) AsJson() map[string]any {
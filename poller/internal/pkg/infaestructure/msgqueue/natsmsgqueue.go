@@ -14,6 +14,10 @@ import (
 type MsgQueueService interface {
 	PublishMessage(ctx context.Context, subject string, data []byte) (
 		msg QueueMessage, err error)
+	// PublishMessageWithID sets the Nats-Msg-Id header so JetStream drops
+	// messages repeated within the stream duplicate window.
+	PublishMessageWithID(ctx context.Context, subject string, msgID string, data []byte) (
+		msg QueueMessage, err error)
 }
 
 type natsService struct {
@@ -29,13 +33,30 @@ func NewNatsService(jsCtxt nats.JetStreamContext) MsgQueueService {
 func (n natsService) PublishMessage(ctx context.Context, subject string, data []byte) (
 	msg QueueMessage, err error) {
 
+	return n.publish(ctx, &nats.Msg{
+		Subject: subject,
+		Data:    data,
+	})
+}
+
+func (n natsService) PublishMessageWithID(ctx context.Context, subject string, msgID string, data []byte) (
+	msg QueueMessage, err error) {
+
 	natsMsg := &nats.Msg{
 		Subject: subject,
 		Data:    data,
+		Header:  nats.Header{},
 	}
+	natsMsg.Header.Set(nats.MsgIdHdr, msgID)
+
+	return n.publish(ctx, natsMsg)
+}
+
+func (n natsService) publish(ctx context.Context, natsMsg *nats.Msg) (
+	msg QueueMessage, err error) {
 
-	msg.Subject = subject
-	msg.Data = data
+	msg.Subject = natsMsg.Subject
+	msg.Data = natsMsg.Data
 	msg.Header = make(map[string][]string)
 	for k, v := range natsMsg.Header {
 		msg.Header[k] = v
@@ -45,10 +66,11 @@ func (n natsService) PublishMessage(ctx context.Context, subject string, data []
 	cnats.SetMsgCorrelationId(ctx, natsMsg)
 	defer span.End()
 
-	_, err = promnats.PublishMsg(n.natsJSCtx, natsMsg)
+	ack, err := promnats.PublishMsg(n.natsJSCtx, natsMsg)
 	if err != nil {
 		return msg, errors.Wrap(err, "publishing message in nats queue")
 	}
+	msg.Duplicate = ack.Duplicate
 
 	return msg, err
 }
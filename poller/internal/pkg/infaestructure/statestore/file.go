@@ -0,0 +1,106 @@
+package statestore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
+)
+
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore keeps every job cursor in a single JSON document on local disk.
+func NewFileStore(path string) (ports.CursorStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "creating cursor directory")
+	}
+
+	return &fileStore{path: path}, nil
+}
+
+func (s *fileStore) Get(_ context.Context, jobName string) (models.Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors, err := s.read()
+	if err != nil {
+		return models.Cursor{}, err
+	}
+
+	return cursors[jobName], nil
+}
+
+func (s *fileStore) Save(_ context.Context, jobName string, cursor models.Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors, err := s.read()
+	if err != nil {
+		return err
+	}
+	cursors[jobName] = cursor
+
+	return s.write(cursors)
+}
+
+func (s *fileStore) Reset(_ context.Context, jobName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors, err := s.read()
+	if err != nil {
+		return err
+	}
+	delete(cursors, jobName)
+
+	return s.write(cursors)
+}
+
+func (s *fileStore) read() (map[string]models.Cursor, error) {
+	cursors := make(map[string]models.Cursor)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cursor file")
+	}
+
+	if len(data) == 0 {
+		return cursors, nil
+	}
+
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, errors.Wrap(err, "decoding cursor file")
+	}
+
+	return cursors, nil
+}
+
+// write replaces the cursor file atomically so a crash never leaves it half written.
+func (s *fileStore) write(cursors map[string]models.Cursor) error {
+	data, err := json.MarshalIndent(cursors, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding cursor file")
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return errors.Wrap(err, "writing cursor file")
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.Wrap(err, "replacing cursor file")
+	}
+
+	return nil
+}
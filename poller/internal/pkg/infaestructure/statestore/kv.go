@@ -0,0 +1,72 @@
+package statestore
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/ronnyp07/SportStream/internal/domain/models"
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
+)
+
+type kvStore struct {
+	kv nats.KeyValue
+}
+
+// NewKVStore stores cursors in a JetStream key-value bucket, creating the
+// bucket when it does not exist yet.
+func NewKVStore(js nats.JetStreamContext, bucket string) (ports.CursorStore, error) {
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "poller job cursors",
+			History:     5,
+		})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "opening cursor bucket")
+	}
+
+	return &kvStore{kv: kv}, nil
+}
+
+func (s *kvStore) Get(_ context.Context, jobName string) (models.Cursor, error) {
+	var cursor models.Cursor
+
+	entry, err := s.kv.Get(jobName)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return cursor, nil
+	}
+	if err != nil {
+		return cursor, errors.Wrap(err, "reading cursor")
+	}
+
+	if err := json.Unmarshal(entry.Value(), &cursor); err != nil {
+		return cursor, errors.Wrap(err, "decoding cursor")
+	}
+
+	return cursor, nil
+}
+
+func (s *kvStore) Save(_ context.Context, jobName string, cursor models.Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return errors.Wrap(err, "encoding cursor")
+	}
+
+	if _, err := s.kv.Put(jobName, data); err != nil {
+		return errors.Wrap(err, "writing cursor")
+	}
+
+	return nil
+}
+
+func (s *kvStore) Reset(_ context.Context, jobName string) error {
+	if err := s.kv.Delete(jobName); err != nil && !errors.Is(err, nats.ErrKeyNotFound) {
+		return errors.Wrap(err, "deleting cursor")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package statestore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	ports "github.com/ronnyp07/SportStream/internal/domain/ports/statestore"
+	"github.com/ronnyp07/SportStream/internal/pkg/config"
+)
+
+const (
+	KVStoreType   = "KV"
+	FileStoreType = "FILE"
+
+	defaultBucket = "poller_state"
+	defaultPath   = "state/cursors.json"
+)
+
+// New builds the cursor store selected in the STATE_STORE configuration.
+func New(cfg config.StateStore, js nats.JetStreamContext) (ports.CursorStore, error) {
+	switch strings.ToUpper(cfg.Type) {
+	case "", KVStoreType:
+		bucket := cfg.Bucket
+		if bucket == "" {
+			bucket = defaultBucket
+		}
+		return NewKVStore(js, bucket)
+	case FileStoreType:
+		path := cfg.Path
+		if path == "" {
+			path = defaultPath
+		}
+		return NewFileStore(path)
+	default:
+		return nil, fmt.Errorf("unknown state store type %s", cfg.Type)
+	}
+}
@@ -0,0 +1,146 @@
+// Command dlq inspects and replays the messages the worker dead-lettered.
+//
+//	dlq list [-limit N]
+//	dlq show <sequence>
+//	dlq replay -all | <sequence>...
+//	dlq purge -older-than <duration>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/deadletter"
+	config "github.com/ronnyp07/SportStream/worker/internal/pkg/config"
+	deadletterRepo "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/deadletter"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	appnats "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/nats"
+)
+
+const commandTimeout = time.Minute
+
+const usage = `usage:
+  dlq list [-limit N]              list dead letters with their failure reasons
+  dlq show <sequence>              print a dead letter with its payload and headers
+  dlq replay -all | <sequence>...  republish dead letters for the worker
+  dlq purge -older-than <duration> delete dead letters older than the duration, e.g. 24h`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// The connection retries in the background, so bound the command instead
+	// of hanging when the server is unreachable.
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	err := run(ctx, os.Args[1], os.Args[2:])
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	limit := flags.Int("limit", 100, "maximum number of dead letters to list")
+	all := flags.Bool("all", false, "replay every dead letter")
+	olderThan := flags.Duration("older-than", 0, "purge dead letters older than this")
+	flags.Parse(args)
+
+	switch command {
+	case "list", "show", "replay", "purge":
+	default:
+		return errors.Errorf("unknown command %q\n%s", command, usage)
+	}
+
+	service, closeFn, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	switch command {
+	case "list":
+		return printJSON(service.List(ctx, *limit))
+	case "show":
+		sequence, err := parseSequence(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return printJSON(service.Get(ctx, sequence))
+	case "replay":
+		if *all {
+			return printJSON(service.ReplayAll(ctx))
+		}
+		if flags.NArg() == 0 {
+			return errors.New("replay needs -all or at least one sequence")
+		}
+		sequences := make([]uint64, 0, flags.NArg())
+		for _, arg := range flags.Args() {
+			sequence, err := parseSequence(arg)
+			if err != nil {
+				return err
+			}
+			sequences = append(sequences, sequence)
+		}
+		return printJSON(service.Replay(ctx, sequences))
+	case "purge":
+		if *olderThan <= 0 {
+			return errors.New("purge needs a positive -older-than duration")
+		}
+		return printJSON(service.Purge(ctx, *olderThan))
+	}
+	return nil
+}
+
+func connect(ctx context.Context) (services.IDeadLetterService, func(), error) {
+	if err := config.Load(); err != nil {
+		return nil, nil, errors.Wrap(err, "loading config")
+	}
+	if err := log.SetupLogger("DeadLetterTool"); err != nil {
+		return nil, nil, errors.Wrap(err, "setting log")
+	}
+
+	nc, err := appnats.Connect(ctx, config.Infra().Nats.Host, strconv.Itoa(config.Infra().Nats.Port))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "connecting to nats")
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, nil, errors.Wrap(err, "creating jetstream context")
+	}
+
+	cfg := config.App().Nats.DeadLetters
+	repo := deadletterRepo.NewDeadLetterRepository(js, cfg.Stream, cfg.Subject)
+	return deadletter.NewDeadLetterService(repo, cfg.ReplaySubject), nc.Close, nil
+}
+
+func parseSequence(value string) (uint64, error) {
+	sequence, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid sequence %q", value)
+	}
+	return sequence, nil
+}
+
+func printJSON(result interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
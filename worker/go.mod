@@ -8,6 +8,8 @@ require (
 	github.com/nats-io/nats.go v1.42.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.22.0
+	github.com/ronnyp07/SportStream/pkg/articledb v0.0.0-00010101000000-000000000000
+	github.com/ronnyp07/SportStream/pkg/migrate v0.0.0-00010101000000-000000000000
 	github.com/spf13/viper v1.20.1
 	github.com/sts-solutions/base-code v0.3.4
 	go.mongodb.org/mongo-driver v1.17.3
@@ -63,3 +65,8 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/ronnyp07/SportStream/pkg/articledb => ../pkg/articledb
+	github.com/ronnyp07/SportStream/pkg/migrate => ../pkg/migrate
+)
@@ -6,25 +6,30 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"emperror.dev/errors"
+	"github.com/ronnyp07/SportStream/worker/internal/app/httpserver"
 	portsMetrics "github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/services/articles"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/dates"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/deadletter"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/outbox"
 	"github.com/ronnyp07/SportStream/worker/internal/metrics"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/database/migrations"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/database/repositories"
+	deadletterRepo "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/deadletter"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	appnats "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/nats"
 	subcriptions "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/natsconsumer"
 
-	//ccmetricsnats "github.com/sts-solutions/base-code/ccmetrics/ccmsgqueue/ccnats"
-	"github.com/sts-solutions/base-code/ccmsgqueue"
-	"github.com/sts-solutions/base-code/ccmsgqueue/ccnats"
+	"github.com/nats-io/nats.go/jetstream"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Connectors struct {
-	nats      ccmsgqueue.Connection
+	js        jetstream.JetStream
 	tracer    trace.Tracer
 	closeFunc func()
 	db        *MongoDB
@@ -36,6 +41,7 @@ type App struct {
 	ctxCancelFn       func()
 	connectors        Connectors
 	termChan          chan os.Signal
+	httpServer        *httpserver.Server
 	msgQueueProcessor services.MessageQueueProcessor
 }
 
@@ -51,28 +57,41 @@ func (a *App) Start(ctx context.Context) error {
 		return err
 	}
 
+	dateParser, err := newDateParser(config.App().Dates)
+	if err != nil {
+		return err
+	}
+
+	if err = a.migrate(a.ctx, dateParser); err != nil {
+		return err
+	}
+
 	metricsHandler := metrics.NewMetricsHandler()
 	metricsHandler.RegisterMetrics()
 
-	appServices := setupServices(a.connectors, metricsHandler)
+	appServices := setupServices(a.connectors, metricsHandler, dateParser)
 
+	updateCfg := config.App().Nats.Consumers.Articles.Update
 	natsServices := subcriptions.Service{
 		ArticleServ: appServices.ArticleServ,
+		Metrics:     metricsHandler,
+		Publisher:   a.connectors.js,
+		Delivery: subcriptions.DeliveryPolicy{
+			MaxDeliveries:     updateCfg.MaxDeliveries,
+			Backoff:           updateCfg.Backoff,
+			MaxBackoff:        updateCfg.MaxBackoff,
+			RetryInPlace:      updateCfg.RetryInPlace,
+			DeadLetterSubject: updateCfg.DeadLetterSubject,
+		},
+	}
+	if err := natsServices.Delivery.Validate(); err != nil {
+		return fmt.Errorf("articles consumer delivery config: %w", err)
 	}
 
 	natsMessageHandler := subcriptions.NewHandler(&natsServices)
-	natsConsumer, err := ccnats.NewConsumerBuilder().
-		WithName(config.App().Nats.Consumers.Articles.Update.ConsumerName).
-		WithStream(config.App().Nats.Consumers.Articles.Update.Stream).
-		WithSubject(config.App().Nats.Consumers.Articles.Update.Subject).
-		WithConnection(a.connectors.nats).
-		//WithMetrics(ccmetricsnats.NewConsumerMetrics(metrics.Namespace)).
-		WithMessageHandler(natsMessageHandler.HandleMessage).
-		Build()
-
-	if err != nil {
-		return errors.Wrap(err, "building nats consumer")
-	}
+	natsConsumer := subcriptions.NewConsumer(a.connectors.js, updateCfg.Stream, updateCfg.ConsumerName,
+		natsMessageHandler.HandleMessage, metricsHandler).
+		WithConcurrency(updateCfg.Workers, updateCfg.MaxInFlight, subcriptions.OrderingKeys)
 
 	go func() {
 		err = natsConsumer.Consume(a.ctx)
@@ -82,6 +101,18 @@ func (a *App) Start(ctx context.Context) error {
 		}
 	}()
 
+	go appServices.OutboxRelay.Start(a.ctx)
+
+	a.httpServer = httpserver.NewServerBuilder(httpserver.Services{
+		DeadLetterService: appServices.DeadLetterServ,
+	}).
+		WithAddr(config.App().Http.HostAddress).
+		WithReadTimeout(config.App().Http.ReadTimeout).
+		WithWriteTimeout(config.App().Http.WriteTimeout).
+		WithAdminToken(config.App().Http.AdminToken).
+		Build()
+	a.httpServer.Start(a.ctx, a.ctxCancelFn)
+
 	//appServices := setupServices(a.connectors)
 	//a.termChan = make(chan os.Signal, 1)
 	//signal.Notify(a.termChan, os.Interrupt)
@@ -116,11 +147,71 @@ func (a *App) Shutdown() {
 	}
 }
 
-func setupServices(c Connectors, metrics portsMetrics.MetricsHandler) Services {
+func setupServices(c Connectors, metrics portsMetrics.MetricsHandler, dateParser services.IDateParser) Services {
 	articleRepo := repositories.NewArticleRepository(c.db.DB, metrics)
-	articlesServ := articles.NewArticlesService(articleRepo)
+	articlesServ := articles.NewArticlesService(articleRepo, dateParser)
+
+	dlqCfg := config.App().Nats.DeadLetters
+	dlqRepo := deadletterRepo.NewDeadLetterRepository(c.js, dlqCfg.Stream, dlqCfg.Subject)
+	deadLetterServ := deadletter.NewDeadLetterService(dlqRepo, dlqCfg.ReplaySubject)
+
+	eventsCfg := config.App().Nats.Events
+	outboxRepo := repositories.NewOutboxRepository(c.db.DB, metrics)
+	outboxRelay := outbox.NewRelay(outboxRepo, appnats.NewPublisher(c.js), metrics,
+		eventsCfg.SubjectPrefix, eventsCfg.Interval, eventsCfg.BatchSize).
+		WithBackoff(eventsCfg.BackoffBase, eventsCfg.BackoffMax)
 	// Implement service setup logic
 	return Services{
-		ArticleServ: articlesServ,
+		ArticleServ:    articlesServ,
+		DeadLetterServ: deadLetterServ,
+		OutboxRelay:    outboxRelay,
+	}
+}
+
+// migrate brings the database schema up to date before anything reads or
+// writes it. In dry-run mode the pending migrations are only logged.
+func (a *App) migrate(ctx context.Context, dateParser services.IDateParser) error {
+	cfg := config.App().Migrations
+	applied, err := migrations.NewRunner(a.connectors.db.DB, migrations.Component, migrations.Worker(dateParser)).
+		WithDryRun(cfg.DryRun).
+		WithLock(cfg.LockTTL, cfg.LockWait).
+		Run(ctx)
+	if err != nil {
+		return fmt.Errorf("migrating the database: %w", err)
+	}
+
+	if !cfg.DryRun && len(applied) > 0 {
+		log.Logger().Info(ctx, fmt.Sprintf("applied migrations %v", applied))
+	}
+	return nil
+}
+
+func newDateParser(cfg config.Dates) (services.IDateParser, error) {
+	location, err := loadLocation(cfg.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]dates.SourceFormats, len(cfg.Sources))
+	for name, source := range cfg.Sources {
+		sourceLocation, err := loadLocation(source.Location)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", name, err)
+		}
+		sources[name] = dates.SourceFormats{Formats: source.Formats, Location: sourceLocation}
+	}
+
+	return dates.NewParser(dates.SourceFormats{Formats: cfg.Formats, Location: location}, sources), nil
+}
+
+// loadLocation returns nil for an empty name so the parser default applies.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading date location %q: %w", name, err)
 	}
+	return location, nil
 }
@@ -2,17 +2,15 @@ package app
 
 import (
 	"context"
-	"fmt"
-	"time"
+	"strconv"
 
 	"emperror.dev/errors"
-	"github.com/ronnyp07/SportStream/worker/internal/metrics"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/config"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	appnats "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/nats"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/otel"
-	ccmetricsnats "github.com/sts-solutions/base-code/ccmetrics/ccmsgqueue/ccnats"
-	"github.com/sts-solutions/base-code/ccmsgqueue"
-	"github.com/sts-solutions/base-code/ccmsgqueue/ccnats"
+	"github.com/sts-solutions/base-code/ccotel/ccotelnats"
 )
 
 func (a *App) connect(ctx context.Context) (cnn Connectors, err error) {
@@ -33,63 +31,35 @@ func (a *App) connect(ctx context.Context) (cnn Connectors, err error) {
 
 	log.Logger().Info(ctx, "connecting to nats server")
 
-	var natsConn ccmsgqueue.Connection = nil
-	natsConn, err = ccnats.NewConnectionBuilder().
-		WithHost(config.Infra().Nats.Host).
-		WithPort(config.Infra().Nats.Port).
-		WithMetrics(ccmetricsnats.NewConsumerMetrics(metrics.Namespace)).
-		WithLogger(log.Logger()).
-		WithTracer(tracer).
-		Build()
+	// The client retries on failed connects and reconnects on its own.
+	natsConn, err := appnats.Connect(ctx, config.Infra().Nats.Host, strconv.Itoa(config.Infra().Nats.Port))
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("fail building nats connection, %v", err))
+		return cnn, errors.Wrap(err, "connecting to the message queue")
 	}
+	ccotelnats.SetTracer(natsConn, tracer)
 
-	err = natsConn.Connect()
+	js, err := jetstream.New(natsConn)
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("fail connecting to the message queue, %v", err))
+		natsConn.Close()
+		return cnn, errors.Wrap(err, "creating jetstream context")
 	}
 
-	go monitoringMsgQueueConnection(ctx, natsConn, config.App().Nats.ReconnectWait)
-
 	log.Logger().Info(ctx, "connecting to mongo db")
 
 	db, err := SetupMongoDB(a.ctx)
 	if err != nil {
+		natsConn.Close()
 		return cnn, errors.Wrap(err, "setting up db")
 	}
 
 	cnn = Connectors{
 		tracer: tracer,
-		nats:   natsConn,
+		js:     js,
 		db:     db,
 		closeFunc: func() {
-			if natsConn != nil {
-				natsConn.Close()
-			}
+			natsConn.Close()
 		},
 	}
 
 	return cnn, nil
 }
-
-func monitoringMsgQueueConnection(ctx context.Context, conn ccmsgqueue.Connection, wait time.Duration) {
-	ticker := time.NewTicker(wait)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			if !conn.IsConnected() {
-				log.Logger().Info(ctx, "message queue disconnected, attempting to connect...")
-				if err := conn.Connect(); err != nil || !conn.IsConnected() {
-					log.Logger().Error(ctx, fmt.Sprintf("fail to reconnected to message queue, %v", err))
-				} else {
-					log.Logger().Info(ctx, "successfully reconnected to message queue")
-				}
-			}
-		case <-ctx.Done():
-			return
-		}
-	}
-}
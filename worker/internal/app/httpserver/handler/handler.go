@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
+)
+
+const defaultListLimit = 100
+
+type DeadLetterHandler struct {
+	service services.IDeadLetterService
+}
+
+func NewDeadLetterHandler(service services.IDeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		service: service,
+	}
+}
+
+// ReplayRequest selects the dead letters to replay, either by sequence or all of them.
+type ReplayRequest struct {
+	Sequences []uint64 `json:"sequences"`
+	All       bool     `json:"all"`
+}
+
+// List returns the dead letters with their failure reasons, oldest first.
+func (h *DeadLetterHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := defaultListLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	letters, err := h.service.List(r.Context(), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, letters)
+}
+
+// Get returns a dead letter with its payload and headers.
+func (h *DeadLetterHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sequence, err := strconv.ParseUint(r.PathValue("sequence"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid sequence", http.StatusBadRequest)
+		return
+	}
+
+	letter, err := h.service.Get(r.Context(), sequence)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, letter)
+}
+
+// Replay republishes the selected dead letters for the worker to process again.
+func (h *DeadLetterHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.All == (len(req.Sequences) > 0) {
+		http.Error(w, "Either sequences or all must be given", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		result models.ReplayResult
+		err    error
+	)
+	if req.All {
+		result, err = h.service.ReplayAll(r.Context())
+	} else {
+		result, err = h.service.Replay(r.Context(), req.Sequences)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Purge deletes the dead letters older than the olderThan query parameter, e.g. 24h.
+// It must be positive; emptying the whole queue at once isn't offered.
+func (h *DeadLetterHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	olderThan, err := time.ParseDuration(r.URL.Query().Get("olderThan"))
+	if err != nil || olderThan <= 0 {
+		http.Error(w, "Invalid olderThan", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Purge(r.Context(), olderThan)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrDeadLetterNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, models.ErrPurgeAge):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,89 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/app/httpserver/handler"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/config"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+)
+
+func NewServerBuilder(services Services) *Server {
+	return &Server{
+		Services:   services,
+		httpServer: &http.Server{},
+	}
+}
+
+func (s *Server) WithAddr(addr string) *Server {
+	s.httpServer.Addr = addr
+	return s
+}
+
+func (s *Server) WithReadTimeout(readTimeOut time.Duration) *Server {
+	s.httpServer.ReadTimeout = readTimeOut
+	return s
+}
+
+func (s *Server) WithWriteTimeout(writeTimeOut time.Duration) *Server {
+	s.httpServer.WriteTimeout = writeTimeOut
+	return s
+}
+
+// WithAdminToken sets the bearer token the admin API requires. Without one
+// the admin API is only served to local requests.
+func (s *Server) WithAdminToken(token string) *Server {
+	s.adminToken = token
+	return s
+}
+
+func (s *Server) Build() *Server {
+	s.setupHandler()
+	s.httpServer.Handler = s.Routes
+	return s
+}
+
+func (s *Server) Start(ctx context.Context, shutDownCall func()) {
+	go func() {
+		log.Logger().Info(ctx, fmt.Sprintf("Admin server starting on %s", s.httpServer.Addr))
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Logger().Error(ctx, fmt.Sprintf("Admin server stopped in %s due to %v", config.App().Env.Name, err))
+			shutDownCall()
+		}
+	}()
+}
+
+func (s *Server) setupHandler() {
+	deadLetterHandler := handler.NewDeadLetterHandler(s.Services.DeadLetterService)
+	s.Routes = NewRouter(deadLetterHandler, s.adminToken)
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("Admin server shutdown failed %v", err))
+		return err
+	}
+	return nil
+}
+
+func NewRouter(deadLetterHandler *handler.DeadLetterHandler, adminToken string) *http.ServeMux {
+	r := http.NewServeMux()
+
+	// Dead letter administration
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin/dlq", deadLetterHandler.List)
+	admin.HandleFunc("GET /admin/dlq/{sequence}", deadLetterHandler.Get)
+	admin.HandleFunc("POST /admin/dlq/replay", deadLetterHandler.Replay)
+	admin.HandleFunc("DELETE /admin/dlq", deadLetterHandler.Purge)
+	r.Handle("/admin/", requireAdmin(adminToken, admin))
+
+	// Health check
+	r.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return r
+}
@@ -0,0 +1,18 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
+)
+
+type Services struct {
+	DeadLetterService services.IDeadLetterService
+}
+
+type Server struct {
+	Services   Services
+	httpServer *http.Server
+	Routes     *http.ServeMux
+	adminToken string
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 // Define models for the external API response
 type ArticleResponse struct {
 	Content []Article `json:"content"`
@@ -10,6 +15,27 @@ type UpsertArticle struct {
 	Article
 }
 
+// Validate rejects articles the feed sent without an ID, which would all be
+// stored as the same article.
+func (a UpsertArticle) Validate() error {
+	if a.ID <= 0 {
+		return ValidationError{Field: "id", Reason: fmt.Sprintf("%d is not a feed ID", a.ID)}
+	}
+	return nil
+}
+
+// UpsertResult reports the outcome of a single article upsert. Unchanged
+// articles are not written; updated ones list the fields that changed.
+type UpsertResult struct {
+	ExternalID    int
+	ID            int
+	Revision      int
+	Inserted      bool
+	Unchanged     bool
+	ChangedFields []string
+	Err           error
+}
+
 type Article struct {
 	ID          int    `json:"id"`
 	Title       string `json:"title"`
@@ -19,6 +45,10 @@ type Article struct {
 	Summary     string `json:"summary"`
 	LeadMedia   Media  `json:"leadMedia"`
 	Tags        []Tag  `json:"tags"`
+	Source      string `json:"source"`
+	// PublishedAt is Date parsed with the formats of the source, in UTC. It is
+	// derived from Date, so it is not compared between versions.
+	PublishedAt time.Time `json:"publishedAt" bson:"publishedAt,omitempty"`
 	// Add other fields as needed
 }
 
@@ -26,6 +56,7 @@ type Media struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 	Type  string `json:"type"`
+	URL   string `json:"url,omitempty"`
 	// Add image URLs/variants as needed
 }
 
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Headers added to dead-lettered messages next to the original ones.
+const (
+	DeadLetterReasonHeader    = "Sportstream-Dlq-Reason"
+	DeadLetterSubjectHeader   = "Sportstream-Dlq-Original-Subject"
+	DeadLetterDeliveredHeader = "Sportstream-Dlq-Deliveries"
+	DeadLetterFailedAtHeader  = "Sportstream-Dlq-Failed-At"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrPurgeAge rejects purges without a positive age, which would empty the
+	// whole queue.
+	ErrPurgeAge = errors.New("purging needs a positive age")
+)
+
+// DeadLetter is a message the worker gave up on, identified by its stream sequence.
+type DeadLetter struct {
+	Sequence        uint64              `json:"sequence"`
+	Subject         string              `json:"subject"`
+	OriginalSubject string              `json:"originalSubject"`
+	Reason          string              `json:"reason"`
+	Deliveries      string              `json:"deliveries"`
+	FailedAt        string              `json:"failedAt"`
+	StoredAt        time.Time           `json:"storedAt"`
+	Headers         map[string][]string `json:"headers,omitempty"`
+	Payload         string              `json:"payload,omitempty"`
+}
+
+type ReplayFailure struct {
+	Sequence uint64 `json:"sequence"`
+	Error    string `json:"error"`
+}
+
+type ReplayResult struct {
+	Replayed []uint64        `json:"replayed"`
+	Failed   []ReplayFailure `json:"failed"`
+}
+
+type PurgeResult struct {
+	Purged int `json:"purged"`
+}
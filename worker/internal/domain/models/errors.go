@@ -0,0 +1,14 @@
+package models
+
+import "fmt"
+
+// ValidationError reports an article the worker won't store as it is, so
+// consumers can tell it from failures worth retrying.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
@@ -0,0 +1,10 @@
+package models
+
+import "github.com/ronnyp07/SportStream/pkg/articledb"
+
+// ArticleEvent announces that an article was stored, as created or updated.
+type ArticleEvent = articledb.ArticleEvent[Tag]
+
+// OutboxEvent is an article event waiting in the outbox to be published; the
+// api queues the events of editors' changes alongside.
+type OutboxEvent = articledb.OutboxEvent[Tag]
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// FieldChange is a single field that differs between two article versions.
+// Values are stored as they appear in the article document.
+type FieldChange struct {
+	Field    string      `json:"field" bson:"field"`
+	Previous interface{} `json:"previous" bson:"previous"`
+	Current  interface{} `json:"current" bson:"current"`
+}
+
+// ArticleRevision is a previous version of an article together with the
+// changes that replaced it.
+type ArticleRevision struct {
+	ArticleID  int           `json:"articleID" bson:"articleID"`
+	ExternalID int           `json:"externalID" bson:"externalID"`
+	Revision   int           `json:"revision" bson:"revision"`
+	Article    Article       `json:"article" bson:"article"`
+	Changes    []FieldChange `json:"changes" bson:"changes"`
+	CreatedAt  time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// articleFields lists the content fields compared between versions, keyed by
+// their name in the article document.
+var articleFields = []struct {
+	name  string
+	value func(Article) interface{}
+}{
+	{"title", func(a Article) interface{} { return a.Title }},
+	{"description", func(a Article) interface{} { return a.Description }},
+	{"date", func(a Article) interface{} { return a.Date }},
+	{"body", func(a Article) interface{} { return a.Body }},
+	{"summary", func(a Article) interface{} { return a.Summary }},
+	{"leadmedia", func(a Article) interface{} { return a.LeadMedia }},
+	{"tags", func(a Article) interface{} {
+		if len(a.Tags) == 0 {
+			return []Tag{}
+		}
+		return a.Tags
+	}},
+	{"source", func(a Article) interface{} { return a.Source }},
+}
+
+// ContentHash fingerprints the article content, leaving out its ID, so an
+// unchanged article can be recognised without comparing every field.
+func (a Article) ContentHash() string {
+	content := make([]interface{}, 0, len(articleFields))
+	for _, field := range articleFields {
+		content = append(content, field.value(a))
+	}
+
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff lists the content fields that differ from a to next.
+func (a Article) Diff(next Article) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range articleFields {
+		previous, current := field.value(a), field.value(next)
+		if !reflect.DeepEqual(previous, current) {
+			changes = append(changes, FieldChange{Field: field.name, Previous: previous, Current: current})
+		}
+	}
+	return changes
+}
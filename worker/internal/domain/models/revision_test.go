@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestArticle_ContentHash(t *testing.T) {
+	article := Article{ID: 1, Title: "Final", Body: "Report", Tags: []Tag{}}
+
+	moved := article
+	moved.ID = 2
+	if article.ContentHash() != moved.ContentHash() {
+		t.Error("expected the hash to ignore the article ID")
+	}
+
+	untagged := article
+	untagged.Tags = nil
+	if article.ContentHash() != untagged.ContentHash() {
+		t.Error("expected missing and empty tags to hash the same")
+	}
+
+	edited := article
+	edited.Title = "Final (updated)"
+	if article.ContentHash() == edited.ContentHash() {
+		t.Error("expected a changed title to change the hash")
+	}
+}
+
+func TestArticle_Diff(t *testing.T) {
+	previous := Article{Title: "Final", Summary: "Short", Tags: []Tag{{ID: 1, Label: "Cricket"}}}
+	next := Article{Title: "Final (updated)", Summary: "Short", Tags: []Tag{{ID: 1, Label: "Cricket"}, {ID: 2, Label: "ECB"}}}
+
+	changes := previous.Diff(next)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Field != "title" || changes[0].Previous != "Final" || changes[0].Current != "Final (updated)" {
+		t.Errorf("unexpected title change %+v", changes[0])
+	}
+	if changes[1].Field != "tags" {
+		t.Errorf("expected a tags change, got %+v", changes[1])
+	}
+
+	if changes := previous.Diff(previous); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
@@ -12,4 +12,9 @@ type MetricsHandler interface {
 	RegisterMetrics()
 	DBCall(source string)
 	DBErrorInc(source string, errMsg string)
+	MessageRejectedInc(msgType string, version string, reason string)
+	MessageOutcomeInc(outcome string)
+	EventPublishedInc(eventType string, status string)
+	SetConsumerQueueDepth(depth int)
+	SetConsumerInFlight(count int)
 }
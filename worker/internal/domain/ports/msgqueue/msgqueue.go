@@ -12,3 +12,8 @@ type MsgQueue interface {
 	IsConnected() bool
 	PublishMessage(ctx context.Context, msgType msgtype.MessageType, data []byte) error
 }
+
+// EventPublisher publishes a message with a deduplication ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, subject string, msgID string, data []byte) error
+}
@@ -7,5 +7,6 @@ import (
 )
 
 type IArticlesRepos interface {
-	UpsertByExternalID(ctx context.Context, article models.UpsertArticle) (models.Article, error)
+	UpsertByExternalID(ctx context.Context, article models.UpsertArticle) (models.UpsertResult, error)
+	BulkUpsertByExternalID(ctx context.Context, articles []models.UpsertArticle) ([]models.UpsertResult, error)
 }
@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+type IDeadLetterRepos interface {
+	List(ctx context.Context) ([]models.DeadLetter, error)
+	Get(ctx context.Context, sequence uint64) (models.DeadLetter, error)
+	Publish(ctx context.Context, subject string, headers map[string][]string, payload []byte) error
+	Delete(ctx context.Context, sequence uint64) error
+}
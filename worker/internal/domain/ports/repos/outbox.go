@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+type IOutboxRepos interface {
+	Pending(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id string, reason string, nextAttemptAt time.Time) error
+}
@@ -8,5 +8,5 @@ import (
 
 type IArticlesService interface {
 	UpsertByExternalID(ctx context.Context,
-		articles []models.UpsertArticle) (models.Article, error)
+		articles []models.UpsertArticle) ([]models.UpsertResult, error)
 }
@@ -0,0 +1,7 @@
+package services
+
+import "time"
+
+type IDateParser interface {
+	Parse(source string, value string) (time.Time, error)
+}
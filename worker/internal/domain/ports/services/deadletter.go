@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+type IDeadLetterService interface {
+	List(ctx context.Context, limit int) ([]models.DeadLetter, error)
+	Get(ctx context.Context, sequence uint64) (models.DeadLetter, error)
+	Replay(ctx context.Context, sequences []uint64) (models.ReplayResult, error)
+	ReplayAll(ctx context.Context) (models.ReplayResult, error)
+	Purge(ctx context.Context, olderThan time.Duration) (models.PurgeResult, error)
+}
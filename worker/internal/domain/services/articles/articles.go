@@ -2,33 +2,83 @@ package articles
 
 import (
 	"context"
+	"fmt"
 
 	"emperror.dev/errors"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/repos"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
 )
 
 type articles struct {
-	repo repos.IArticlesRepos
+	repo  repos.IArticlesRepos
+	dates services.IDateParser
 }
 
-func NewArticlesService(repo repos.IArticlesRepos) *articles {
+func NewArticlesService(repo repos.IArticlesRepos, dates services.IDateParser) *articles {
 	return &articles{
-		repo: repo,
+		repo:  repo,
+		dates: dates,
 	}
 }
 
+// UpsertByExternalID stores the valid articles in a single bulk write and
+// retries the articles that failed in it one at a time. The error reports the
+// invalid articles and those that still failed.
 func (a articles) UpsertByExternalID(ctx context.Context,
-	articles []models.UpsertArticle) (models.Article, error) {
-	var result models.Article
+	articles []models.UpsertArticle) ([]models.UpsertResult, error) {
+	var failed error
+	invalid := []models.UpsertResult{}
+	valid := make([]models.UpsertArticle, 0, len(articles))
+	for _, article := range articles {
+		if err := article.Validate(); err != nil {
+			invalid = append(invalid, models.UpsertResult{ExternalID: article.ID, Err: err})
+			failed = errors.Append(failed, errors.WithMessagef(err, "unable to upsert article %d", article.ID))
+			continue
+		}
+		valid = append(valid, article)
+	}
+	if len(valid) == 0 {
+		return invalid, failed
+	}
+	articles = valid
 
+	for i := range articles {
+		articles[i].ExternalID = articles[i].ID
+
+		// An unreadable date still leaves the article worth storing, it is
+		// listed after the dated ones.
+		publishedAt, err := a.dates.Parse(articles[i].Source, articles[i].Date)
+		if err != nil {
+			log.Logger().Error(ctx, fmt.Sprintf("article %d: %v", articles[i].ID, err))
+		}
+		articles[i].PublishedAt = publishedAt
+	}
+
+	results, err := a.repo.BulkUpsertByExternalID(ctx, articles)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to bulk upsert articles")
+	}
+
+	byExternalID := make(map[int]models.UpsertArticle, len(articles))
 	for _, article := range articles {
-		article.ExternalID = article.ID
-		result, err := a.repo.UpsertByExternalID(ctx, article)
+		byExternalID[article.ExternalID] = article
+	}
+
+	for i, result := range results {
+		if result.Err == nil {
+			continue
+		}
+
+		retried, err := a.repo.UpsertByExternalID(ctx, byExternalID[result.ExternalID])
 		if err != nil {
-			return result, errors.Wrap(err, "unable to upsert article")
+			results[i].Err = err
+			failed = errors.Append(failed, errors.Wrapf(err, "unable to upsert article %d", result.ExternalID))
+			continue
 		}
+		results[i] = retried
 	}
 
-	return result, nil
+	return append(results, invalid...), failed
 }
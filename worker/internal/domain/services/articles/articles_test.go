@@ -0,0 +1,127 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/dates"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+)
+
+type fakeRepo struct {
+	bulk       [][]models.UpsertArticle
+	bulkFailed map[int]bool
+	single     []int
+	singleErr  error
+}
+
+func (f *fakeRepo) BulkUpsertByExternalID(_ context.Context, articles []models.UpsertArticle) ([]models.UpsertResult, error) {
+	f.bulk = append(f.bulk, articles)
+	results := make([]models.UpsertResult, 0, len(articles))
+	for i, article := range articles {
+		result := models.UpsertResult{ExternalID: article.ExternalID, ID: i + 1, Inserted: true}
+		if f.bulkFailed[article.ExternalID] {
+			result = models.UpsertResult{ExternalID: article.ExternalID, Err: errors.New("write conflict")}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (f *fakeRepo) UpsertByExternalID(_ context.Context, article models.UpsertArticle) (models.UpsertResult, error) {
+	f.single = append(f.single, article.ExternalID)
+	if f.singleErr != nil {
+		return models.UpsertResult{}, f.singleErr
+	}
+	return models.UpsertResult{ExternalID: article.ExternalID, ID: 99}, nil
+}
+
+func upserts(ids ...int) []models.UpsertArticle {
+	articles := make([]models.UpsertArticle, 0, len(ids))
+	for _, id := range ids {
+		articles = append(articles, models.UpsertArticle{Article: models.Article{ID: id}})
+	}
+	return articles
+}
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("WorkerTest"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestArticles_UpsertByExternalID(t *testing.T) {
+	repo := &fakeRepo{bulkFailed: map[int]bool{20: true}}
+	service := NewArticlesService(repo, dates.NewParser(dates.SourceFormats{}, nil))
+
+	results, err := service.UpsertByExternalID(context.Background(), upserts(10, 20, 30))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(repo.bulk) != 1 || len(repo.bulk[0]) != 3 || repo.bulk[0][1].ExternalID != 20 {
+		t.Fatalf("expected one bulk write keyed by external ID, got %+v", repo.bulk)
+	}
+	if len(repo.single) != 1 || repo.single[0] != 20 {
+		t.Errorf("expected only article 20 to be retried, got %v", repo.single)
+	}
+	if results[1].Err != nil || results[1].ID != 99 {
+		t.Errorf("expected the retried article to succeed, got %+v", results[1])
+	}
+}
+
+func TestArticles_UpsertByExternalIDReportsRetryFailures(t *testing.T) {
+	repo := &fakeRepo{bulkFailed: map[int]bool{20: true}, singleErr: errors.New("timeout")}
+	service := NewArticlesService(repo, dates.NewParser(dates.SourceFormats{}, nil))
+
+	results, err := service.UpsertByExternalID(context.Background(), upserts(10, 20))
+	if err == nil {
+		t.Fatal("expected an error for the article that kept failing")
+	}
+	if results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("expected only article 20 to fail, got %+v", results)
+	}
+}
+
+func TestArticles_UpsertByExternalIDRejectsInvalidArticles(t *testing.T) {
+	repo := &fakeRepo{}
+	service := NewArticlesService(repo, dates.NewParser(dates.SourceFormats{}, nil))
+
+	results, err := service.UpsertByExternalID(context.Background(), upserts(10, 0))
+
+	if !errors.As(err, &models.ValidationError{}) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(repo.bulk) != 1 || len(repo.bulk[0]) != 1 || repo.bulk[0][0].ExternalID != 10 {
+		t.Errorf("expected only the valid article written, got %+v", repo.bulk)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("expected only the article without an ID to fail, got %+v", results)
+	}
+}
+
+func TestArticles_UpsertByExternalIDParsesDates(t *testing.T) {
+	repo := &fakeRepo{}
+	service := NewArticlesService(repo, dates.NewParser(dates.SourceFormats{}, nil))
+
+	articles := upserts(10, 20)
+	articles[0].Date = "2024-05-01T10:30:00+02:00"
+	articles[1].Date = "yesterday"
+
+	if _, err := service.UpsertByExternalID(context.Background(), articles); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	stored := repo.bulk[0]
+	if want := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC); !stored[0].PublishedAt.Equal(want) {
+		t.Errorf("expected publishedAt %v, got %v", want, stored[0].PublishedAt)
+	}
+	if !stored[1].PublishedAt.IsZero() {
+		t.Errorf("expected an unparsed date to leave publishedAt unset, got %v", stored[1].PublishedAt)
+	}
+}
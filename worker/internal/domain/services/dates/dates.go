@@ -0,0 +1,77 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultFormats are tried for sources without formats of their own when no
+// defaults are configured.
+var defaultFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// SourceFormats are the date layouts a source uses, in the order they are
+// tried, and the location of dates that carry no offset.
+type SourceFormats struct {
+	Formats  []string
+	Location *time.Location
+}
+
+type parser struct {
+	defaults SourceFormats
+	sources  map[string]SourceFormats
+}
+
+// NewParser parses dates with the formats of their source, falling back to
+// the defaults. Source names are matched case-insensitively.
+func NewParser(defaults SourceFormats, sources map[string]SourceFormats) *parser {
+	if len(defaults.Formats) == 0 {
+		defaults.Formats = defaultFormats
+	}
+	if defaults.Location == nil {
+		defaults.Location = time.UTC
+	}
+
+	bySource := make(map[string]SourceFormats, len(sources))
+	for name, formats := range sources {
+		if len(formats.Formats) == 0 {
+			formats.Formats = defaults.Formats
+		}
+		if formats.Location == nil {
+			formats.Location = defaults.Location
+		}
+		bySource[strings.ToLower(name)] = formats
+	}
+
+	return &parser{
+		defaults: defaults,
+		sources:  bySource,
+	}
+}
+
+// Parse reads an upstream date with the first matching format of the source
+// and returns it in UTC.
+func (p *parser) Parse(source string, value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	formats, ok := p.sources[strings.ToLower(source)]
+	if !ok {
+		formats = p.defaults
+	}
+
+	for _, layout := range formats.Formats {
+		if t, err := time.ParseInLocation(layout, value, formats.Location); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q of source %q matches none of its formats", value, source)
+}
@@ -0,0 +1,51 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParser_Parse(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	parser := NewParser(SourceFormats{}, map[string]SourceFormats{
+		"Archive": {Formats: []string{time.RFC1123Z}},
+		"local":   {Formats: []string{"2006-01-02 15:04"}, Location: london},
+	})
+
+	tests := []struct {
+		name    string
+		source  string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "default RFC 3339", source: "poller", value: "2024-05-01T10:30:00+02:00",
+			want: time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)},
+		{name: "default offset without colon", source: "poller", value: "2024-05-01T10:30:00+0100",
+			want: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "default without offset is UTC", source: "", value: " 2024-05-01T10:30:00 ",
+			want: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "source formats match case-insensitively", source: "archive", value: "Wed, 01 May 2024 10:30:00 -0400",
+			want: time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC)},
+		{name: "source location applies without offset", source: "local", value: "2024-07-01 10:30",
+			want: time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "source formats replace the defaults", source: "archive", value: "2024-05-01T10:30:00Z", wantErr: true},
+		{name: "empty", source: "poller", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(tt.source, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !got.Equal(tt.want) || (!tt.wantErr && got.Location() != time.UTC) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/repos"
+)
+
+// msgIDHeader is the JetStream deduplication header.
+const msgIDHeader = "Nats-Msg-Id"
+
+var deadLetterHeaders = []string{
+	models.DeadLetterReasonHeader,
+	models.DeadLetterSubjectHeader,
+	models.DeadLetterDeliveredHeader,
+	models.DeadLetterFailedAtHeader,
+}
+
+type deadLetters struct {
+	repo          repos.IDeadLetterRepos
+	replaySubject string
+	now           func() time.Time
+}
+
+func NewDeadLetterService(repo repos.IDeadLetterRepos, replaySubject string) *deadLetters {
+	return &deadLetters{
+		repo:          repo,
+		replaySubject: replaySubject,
+		now:           time.Now,
+	}
+}
+
+// List returns the oldest dead letters first, without their payload and
+// headers. A limit of zero returns all of them.
+func (d deadLetters) List(ctx context.Context, limit int) ([]models.DeadLetter, error) {
+	letters, err := d.repo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list dead letters")
+	}
+
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+	for i := range letters {
+		letters[i].Headers = nil
+		letters[i].Payload = ""
+	}
+
+	return letters, nil
+}
+
+func (d deadLetters) Get(ctx context.Context, sequence uint64) (models.DeadLetter, error) {
+	return d.repo.Get(ctx, sequence)
+}
+
+// Replay republishes the given dead letters to the replay subject and removes
+// them from the dead letter queue. A failing entry does not stop the others.
+func (d deadLetters) Replay(ctx context.Context, sequences []uint64) (models.ReplayResult, error) {
+	result := models.ReplayResult{Replayed: []uint64{}, Failed: []models.ReplayFailure{}}
+
+	for _, sequence := range sequences {
+		letter, err := d.repo.Get(ctx, sequence)
+		if err == nil {
+			err = d.replay(ctx, letter)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, models.ReplayFailure{Sequence: sequence, Error: err.Error()})
+			continue
+		}
+		result.Replayed = append(result.Replayed, sequence)
+	}
+
+	return result, nil
+}
+
+func (d deadLetters) ReplayAll(ctx context.Context) (models.ReplayResult, error) {
+	letters, err := d.repo.List(ctx)
+	if err != nil {
+		return models.ReplayResult{}, errors.Wrap(err, "unable to list dead letters")
+	}
+
+	sequences := make([]uint64, 0, len(letters))
+	for _, letter := range letters {
+		sequences = append(sequences, letter.Sequence)
+	}
+
+	return d.Replay(ctx, sequences)
+}
+
+// Purge deletes the dead letters stored longer than olderThan ago.
+func (d deadLetters) Purge(ctx context.Context, olderThan time.Duration) (models.PurgeResult, error) {
+	var result models.PurgeResult
+	if olderThan <= 0 {
+		return result, models.ErrPurgeAge
+	}
+
+	letters, err := d.repo.List(ctx)
+	if err != nil {
+		return result, errors.Wrap(err, "unable to list dead letters")
+	}
+
+	cutoff := d.now().Add(-olderThan)
+	for _, letter := range letters {
+		if !letter.StoredAt.Before(cutoff) {
+			continue
+		}
+		if err := d.repo.Delete(ctx, letter.Sequence); err != nil {
+			return result, errors.Wrapf(err, "unable to delete dead letter %d", letter.Sequence)
+		}
+		result.Purged++
+	}
+
+	return result, nil
+}
+
+// replay strips the dead letter headers and publishes the original payload
+// under a message ID of its own, so replaying the same entry twice within the
+// deduplication window only delivers it once.
+func (d deadLetters) replay(ctx context.Context, letter models.DeadLetter) error {
+	headers := make(map[string][]string, len(letter.Headers))
+	for key, values := range letter.Headers {
+		headers[key] = values
+	}
+	for _, key := range deadLetterHeaders {
+		delete(headers, key)
+	}
+	headers[msgIDHeader] = []string{fmt.Sprintf("replay-%d", letter.Sequence)}
+
+	if err := d.repo.Publish(ctx, d.replaySubject, headers, []byte(letter.Payload)); err != nil {
+		return errors.Wrap(err, "unable to republish dead letter")
+	}
+
+	return errors.Wrap(d.repo.Delete(ctx, letter.Sequence), "unable to delete replayed dead letter")
+}
@@ -0,0 +1,178 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+type fakePublished struct {
+	subject string
+	headers map[string][]string
+	payload string
+}
+
+type fakeRepo struct {
+	letters    map[uint64]models.DeadLetter
+	published  []fakePublished
+	publishErr error
+}
+
+func newFakeRepo(letters ...models.DeadLetter) *fakeRepo {
+	repo := &fakeRepo{letters: map[uint64]models.DeadLetter{}}
+	for _, letter := range letters {
+		repo.letters[letter.Sequence] = letter
+	}
+	return repo
+}
+
+func (f *fakeRepo) List(context.Context) ([]models.DeadLetter, error) {
+	letters := []models.DeadLetter{}
+	for sequence := uint64(1); sequence <= 10; sequence++ {
+		if letter, ok := f.letters[sequence]; ok {
+			letters = append(letters, letter)
+		}
+	}
+	return letters, nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, sequence uint64) (models.DeadLetter, error) {
+	letter, ok := f.letters[sequence]
+	if !ok {
+		return models.DeadLetter{}, models.ErrDeadLetterNotFound
+	}
+	return letter, nil
+}
+
+func (f *fakeRepo) Publish(_ context.Context, subject string, headers map[string][]string, payload []byte) error {
+	if f.publishErr != nil {
+		return f.publishErr
+	}
+	f.published = append(f.published, fakePublished{subject: subject, headers: headers, payload: string(payload)})
+	return nil
+}
+
+func (f *fakeRepo) Delete(_ context.Context, sequence uint64) error {
+	delete(f.letters, sequence)
+	return nil
+}
+
+func letter(sequence uint64, storedAt time.Time) models.DeadLetter {
+	return models.DeadLetter{
+		Sequence: sequence,
+		Reason:   "decoding articles",
+		StoredAt: storedAt,
+		Payload:  `{"id": 7}`,
+		Headers: map[string][]string{
+			"Nats-Msg-Id":                 {"dlq-ecb-7-abc"},
+			"correlation-id":              {"abc"},
+			models.DeadLetterReasonHeader: {"decoding articles"},
+		},
+	}
+}
+
+func TestDeadLetters_List(t *testing.T) {
+	now := time.Now()
+	service := NewDeadLetterService(newFakeRepo(letter(1, now), letter(2, now), letter(3, now)), "SPORTSTREAM.status.updated")
+
+	letters, err := service.List(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(letters) != 2 || letters[0].Sequence != 1 || letters[1].Sequence != 2 {
+		t.Fatalf("expected the two oldest dead letters, got %+v", letters)
+	}
+	if letters[0].Payload != "" || letters[0].Headers != nil || letters[0].Reason == "" {
+		t.Errorf("expected a summary with the reason only, got %+v", letters[0])
+	}
+}
+
+func TestDeadLetters_Replay(t *testing.T) {
+	repo := newFakeRepo(letter(1, time.Now()), letter(2, time.Now()))
+	service := NewDeadLetterService(repo, "SPORTSTREAM.status.updated")
+
+	result, err := service.Replay(context.Background(), []uint64{1, 9})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result.Replayed) != 1 || result.Replayed[0] != 1 {
+		t.Errorf("expected sequence 1 to be replayed, got %v", result.Replayed)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Sequence != 9 {
+		t.Errorf("expected sequence 9 to fail, got %v", result.Failed)
+	}
+
+	if len(repo.published) != 1 {
+		t.Fatalf("expected one republished message, got %d", len(repo.published))
+	}
+	published := repo.published[0]
+	if published.subject != "SPORTSTREAM.status.updated" || published.payload != `{"id": 7}` {
+		t.Errorf("unexpected republished message %+v", published)
+	}
+	if _, ok := published.headers[models.DeadLetterReasonHeader]; ok {
+		t.Errorf("expected dead letter headers to be stripped, got %v", published.headers)
+	}
+	if published.headers["correlation-id"][0] != "abc" || published.headers["Nats-Msg-Id"][0] != "replay-1" {
+		t.Errorf("unexpected headers %v", published.headers)
+	}
+	if _, ok := repo.letters[1]; ok {
+		t.Error("expected the replayed dead letter to be deleted")
+	}
+	if _, ok := repo.letters[2]; !ok {
+		t.Error("expected the other dead letter to be kept")
+	}
+}
+
+func TestDeadLetters_ReplayKeepsEntryWhenPublishFails(t *testing.T) {
+	repo := newFakeRepo(letter(1, time.Now()))
+	repo.publishErr = errors.New("no responders")
+	service := NewDeadLetterService(repo, "SPORTSTREAM.status.updated")
+
+	result, err := service.ReplayAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(result.Failed) != 1 || len(result.Replayed) != 0 {
+		t.Errorf("expected the replay to fail, got %+v", result)
+	}
+	if _, ok := repo.letters[1]; !ok {
+		t.Error("expected the dead letter to be kept")
+	}
+}
+
+func TestDeadLetters_Purge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeRepo(letter(1, now.Add(-48*time.Hour)), letter(2, now.Add(-25*time.Hour)), letter(3, now.Add(-time.Hour)))
+	service := NewDeadLetterService(repo, "SPORTSTREAM.status.updated")
+	service.now = func() time.Time { return now }
+
+	result, err := service.Purge(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.Purged != 2 {
+		t.Errorf("expected 2 purged dead letters, got %d", result.Purged)
+	}
+	if _, ok := repo.letters[3]; !ok || len(repo.letters) != 1 {
+		t.Errorf("expected only the recent dead letter to be kept, got %v", repo.letters)
+	}
+}
+
+func TestDeadLetters_PurgeNeedsAnAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeRepo(letter(1, now.Add(-48*time.Hour)))
+	service := NewDeadLetterService(repo, "SPORTSTREAM.status.updated")
+	service.now = func() time.Time { return now }
+
+	for _, olderThan := range []time.Duration{0, -time.Hour} {
+		if _, err := service.Purge(context.Background(), olderThan); !errors.Is(err, models.ErrPurgeAge) {
+			t.Errorf("olderThan %v: expected %v, got %v", olderThan, models.ErrPurgeAge, err)
+		}
+	}
+	if len(repo.letters) != 1 {
+		t.Errorf("expected the dead letter kept, got %v", repo.letters)
+	}
+}
@@ -0,0 +1,93 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/msgtype"
+	"github.com/sts-solutions/base-code/cccorrelation"
+)
+
+// Producer identifies this service in the envelopes it publishes.
+const Producer = "worker"
+
+// ErrInvalidPayload is returned for messages that are not valid JSON.
+var ErrInvalidPayload = errors.New("invalid message payload")
+
+// LegacyVersion is the schema version assumed for bare payloads published
+// before messages were wrapped in an envelope.
+const LegacyVersion = 1
+
+// Envelope wraps every message published on the stream so consumers can
+// dispatch on its type and schema version.
+type Envelope struct {
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Producer      string          `json:"producer"`
+	Source        string          `json:"source"`
+	ProducedAt    time.Time       `json:"producedAt"`
+	CorrelationID string          `json:"correlationID"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// New wraps the payload in an envelope, taking the correlation ID from the
+// context or generating one when the context has none.
+func New(ctx context.Context, msgType msgtype.MessageType, schemaVersion int,
+	source string, payload any) (Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	ok, correlationID := cccorrelation.GetCorrelationId(ctx)
+	if !ok || correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+
+	return Envelope{
+		Type:          msgType.Name(),
+		SchemaVersion: schemaVersion,
+		Producer:      Producer,
+		Source:        source,
+		ProducedAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Payload:       data,
+	}, nil
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Decode reads an envelope. Bare article payloads, either a JSON array or a
+// single article, are wrapped as an articles update of LegacyVersion.
+func Decode(data []byte) (Envelope, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	var env Envelope
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &env); err != nil {
+			return Envelope{}, err
+		}
+		if env.Type != "" && env.Payload != nil {
+			return env, nil
+		}
+	}
+
+	if !json.Valid(trimmed) {
+		return Envelope{}, ErrInvalidPayload
+	}
+
+	return Envelope{
+		Type:          msgtype.ArticlesUpdated.Name(),
+		SchemaVersion: LegacyVersion,
+		Payload:       trimmed,
+	}, nil
+}
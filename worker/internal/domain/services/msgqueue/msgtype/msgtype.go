@@ -5,11 +5,17 @@ type MessageType int32
 const (
 	NotSet MessageType = iota
 	ArticlesUpdated
+	// ArticleCreated and ArticleUpdated are the events published once an
+	// article has been stored.
+	ArticleCreated
+	ArticleUpdated
 )
 
 var namesMessageType = map[MessageType]string{
 	NotSet:          `not-set`,
 	ArticlesUpdated: `article-updated`,
+	ArticleCreated:  `article.created`,
+	ArticleUpdated:  `article.updated`,
 }
 
 func FromName(name string) MessageType {
@@ -35,3 +41,11 @@ func Get(id int32) MessageType {
 func (t MessageType) ID() int32 {
 	return int32(t)
 }
+
+func (t MessageType) Name() string {
+	if name, ok := namesMessageType[t]; ok {
+		return name
+	}
+
+	return namesMessageType[NotSet]
+}
@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/msgqueue"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/repos"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/envelope"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/msgtype"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	"github.com/sts-solutions/base-code/cccorrelation"
+)
+
+const (
+	eventSchemaVersion = 1
+
+	publishedStatus = "published"
+	failedStatus    = "failed"
+
+	defaultBackoffBase = time.Second
+	defaultBackoffMax  = 5 * time.Minute
+)
+
+// Relay publishes the events stored in the outbox, oldest first. Events stay
+// in the outbox until the stream acknowledges them, so they survive the
+// message queue being unavailable. An event that fails is retried with
+// exponential backoff while other articles' events go on being published; the
+// later events of its article wait for it, so an article's events go out in
+// order.
+type Relay struct {
+	repo          repos.IOutboxRepos
+	publisher     msgqueue.EventPublisher
+	metrics       metrics.MetricsHandler
+	subjectPrefix string
+	interval      time.Duration
+	batchSize     int
+	backoffBase   time.Duration
+	backoffMax    time.Duration
+	now           func() time.Time
+}
+
+func NewRelay(repo repos.IOutboxRepos, publisher msgqueue.EventPublisher, metrics metrics.MetricsHandler,
+	subjectPrefix string, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		repo:          repo,
+		publisher:     publisher,
+		metrics:       metrics,
+		subjectPrefix: subjectPrefix,
+		interval:      interval,
+		batchSize:     batchSize,
+		backoffBase:   defaultBackoffBase,
+		backoffMax:    defaultBackoffMax,
+		now:           time.Now,
+	}
+}
+
+// WithBackoff sets the delay before the first retry of a failed event, which
+// doubles with every attempt up to max. Zero values keep the defaults.
+func (r *Relay) WithBackoff(base, max time.Duration) *Relay {
+	if base > 0 {
+		r.backoffBase = base
+	}
+	if max > 0 {
+		r.backoffMax = max
+	}
+	return r
+}
+
+// Start polls the outbox every interval until the context is done.
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.PublishPending(ctx); err != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("publishing outbox events: %v", err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PublishPending publishes a batch of the events due. The batch holds at most
+// one event per article, so a failed event is held back until its next attempt
+// along with its article's later events, and the rest of the batch is still
+// published. The failures are returned together.
+func (r *Relay) PublishPending(ctx context.Context) error {
+	now := r.now().UTC()
+	events, err := r.repo.Pending(ctx, now, r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var failures []error
+	for _, event := range events {
+		if err := r.publish(ctx, event); err != nil {
+			r.metrics.EventPublishedInc(event.Type, failedStatus)
+			failures = append(failures, errors.Wrapf(err, "publishing event %s", event.ID))
+			if markErr := r.repo.MarkFailed(ctx, event.ID, err.Error(), now.Add(r.backoff(event.Attempts))); markErr != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("recording outbox failure: %v", markErr))
+			}
+			continue
+		}
+
+		r.metrics.EventPublishedInc(event.Type, publishedStatus)
+		if err := r.repo.MarkPublished(ctx, event.ID, r.now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	return errors.Combine(failures...)
+}
+
+// backoff is the delay before retrying an event that failed attempts times
+// before this failure.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.backoffBase
+	for i := 0; i < attempts && delay < r.backoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, r.backoffMax)
+}
+
+func (r *Relay) publish(ctx context.Context, event models.OutboxEvent) error {
+	if event.CorrelationID != "" {
+		ctx = cccorrelation.WithCorrelationId(ctx, event.CorrelationID)
+	}
+
+	env, err := envelope.New(ctx, msgtype.FromName(event.Type), eventSchemaVersion, event.Event.Source, event.Event)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	return r.publisher.Publish(ctx, r.subjectPrefix+"."+event.Type, event.ID, data)
+}
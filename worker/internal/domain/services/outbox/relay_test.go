@@ -0,0 +1,237 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/envelope"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+)
+
+// fakeRepo keeps the outbox in the order the events were queued and returns
+// them as the repository does: the earliest unpublished event of each article,
+// once it's due.
+type fakeRepo struct {
+	pending   []models.OutboxEvent
+	published []string
+	failed    []string
+	retries   map[string]time.Time
+}
+
+func (f *fakeRepo) Pending(_ context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	events := []models.OutboxEvent{}
+	seen := map[int]bool{}
+	for _, event := range f.pending {
+		if event.PublishedAt != nil || seen[event.Event.ID] {
+			continue
+		}
+		seen[event.Event.ID] = true
+		if event.NextAttemptAt != nil && event.NextAttemptAt.After(now) {
+			continue
+		}
+		if len(events) == limit {
+			break
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (f *fakeRepo) MarkPublished(_ context.Context, id string, publishedAt time.Time) error {
+	f.published = append(f.published, id)
+	if event := f.event(id); event != nil {
+		event.PublishedAt = &publishedAt
+		event.Attempts++
+	}
+	return nil
+}
+
+func (f *fakeRepo) MarkFailed(_ context.Context, id string, _ string, nextAttemptAt time.Time) error {
+	f.failed = append(f.failed, id)
+	if f.retries == nil {
+		f.retries = map[string]time.Time{}
+	}
+	f.retries[id] = nextAttemptAt
+	if event := f.event(id); event != nil {
+		event.NextAttemptAt = &nextAttemptAt
+		event.Attempts++
+	}
+	return nil
+}
+
+func (f *fakeRepo) event(id string) *models.OutboxEvent {
+	for i := range f.pending {
+		if f.pending[i].ID == id {
+			return &f.pending[i]
+		}
+	}
+	return nil
+}
+
+type fakeMessage struct {
+	subject string
+	msgID   string
+	data    []byte
+}
+
+type fakePublisher struct {
+	messages []fakeMessage
+	failOn   string
+}
+
+func (f *fakePublisher) Publish(_ context.Context, subject string, msgID string, data []byte) error {
+	if msgID == f.failOn {
+		return errors.New("no responders available")
+	}
+	f.messages = append(f.messages, fakeMessage{subject: subject, msgID: msgID, data: data})
+	return nil
+}
+
+type fakeMetrics struct{}
+
+func (fakeMetrics) RegisterMetrics()                          {}
+func (fakeMetrics) DBCall(string)                             {}
+func (fakeMetrics) DBErrorInc(string, string)                 {}
+func (fakeMetrics) MessageRejectedInc(string, string, string) {}
+func (fakeMetrics) MessageOutcomeInc(string)                  {}
+func (fakeMetrics) EventPublishedInc(string, string)          {}
+func (fakeMetrics) SetConsumerQueueDepth(int)                 {}
+func (fakeMetrics) SetConsumerInFlight(int)                   {}
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("WorkerTest"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func outboxEvent(id string, eventType string, revision int) models.OutboxEvent {
+	return articleEvent(5, id, eventType, revision)
+}
+
+func articleEvent(articleID int, id string, eventType string, revision int) models.OutboxEvent {
+	return models.OutboxEvent{
+		ID:            id,
+		Type:          eventType,
+		Event:         models.ArticleEvent{ID: articleID, ExternalID: 42, Revision: revision, Source: "ecb"},
+		CorrelationID: "abc",
+	}
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	repo := &fakeRepo{pending: []models.OutboxEvent{
+		outboxEvent("article-5-1", "article.created", 1),
+		articleEvent(6, "article-6-1", "article.created", 1),
+	}}
+	publisher := &fakePublisher{}
+	relay := NewRelay(repo, publisher, fakeMetrics{}, "SPORTSTREAM", time.Second, 10)
+
+	if err := relay.PublishPending(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(publisher.messages) != 2 || len(repo.published) != 2 {
+		t.Fatalf("expected 2 published events, got %d messages and %v", len(publisher.messages), repo.published)
+	}
+	msg := publisher.messages[0]
+	if msg.subject != "SPORTSTREAM.article.created" || msg.msgID != "article-5-1" {
+		t.Errorf("unexpected subject %s or message id %s", msg.subject, msg.msgID)
+	}
+
+	var env envelope.Envelope
+	if err := json.Unmarshal(msg.data, &env); err != nil {
+		t.Fatalf("decoding envelope: %v", err)
+	}
+	if env.Type != "article.created" || env.Producer != envelope.Producer || env.CorrelationID != "abc" {
+		t.Errorf("unexpected envelope %+v", env)
+	}
+
+	var event models.ArticleEvent
+	if err := json.Unmarshal(env.Payload, &event); err != nil || event.ID != 5 || event.ExternalID != 42 {
+		t.Errorf("unexpected event payload %s", env.Payload)
+	}
+}
+
+func TestRelay_PublishPendingSkipsFailures(t *testing.T) {
+	repo := &fakeRepo{pending: []models.OutboxEvent{
+		articleEvent(5, "article-5-1", "article.created", 1),
+		articleEvent(6, "article-6-1", "article.created", 1),
+		articleEvent(5, "article-5-2", "article.updated", 2),
+		articleEvent(7, "article-7-1", "article.created", 1),
+	}}
+	repo.pending[0].Attempts = 2
+	publisher := &fakePublisher{failOn: "article-5-1"}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	relay := NewRelay(repo, publisher, fakeMetrics{}, "SPORTSTREAM", time.Second, 10).
+		WithBackoff(time.Second, time.Minute)
+	relay.now = func() time.Time { return now }
+
+	if err := relay.PublishPending(context.Background()); err == nil {
+		t.Fatal("expected the failed publish to be reported")
+	}
+
+	if len(repo.published) != 2 || repo.published[0] != "article-6-1" || repo.published[1] != "article-7-1" {
+		t.Errorf("expected the other articles' events to be published, got %v", repo.published)
+	}
+	if len(repo.failed) != 1 || repo.failed[0] != "article-5-1" {
+		t.Errorf("expected the failed event to be marked failed, got %v", repo.failed)
+	}
+	if retry := repo.retries["article-5-1"]; !retry.Equal(now.Add(4 * time.Second)) {
+		t.Errorf("expected the third attempt to be retried after 4s, got %v", retry)
+	}
+}
+
+func TestRelay_PublishPendingKeepsArticleOrderAcrossTicks(t *testing.T) {
+	repo := &fakeRepo{pending: []models.OutboxEvent{
+		articleEvent(5, "article-5-1", "article.created", 1),
+		articleEvent(5, "article-5-2", "article.updated", 2),
+	}}
+	publisher := &fakePublisher{failOn: "article-5-1"}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	relay := NewRelay(repo, publisher, fakeMetrics{}, "SPORTSTREAM", time.Second, 10).
+		WithBackoff(time.Second, time.Minute)
+	relay.now = func() time.Time { return now }
+
+	if err := relay.PublishPending(context.Background()); err == nil {
+		t.Fatal("expected the failed publish to be reported")
+	}
+
+	// The stream is back before the failed event is due again; its article's
+	// next event must still wait for it.
+	publisher.failOn = ""
+	if err := relay.PublishPending(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(repo.published) != 0 {
+		t.Fatalf("expected nothing published before the retry, got %v", repo.published)
+	}
+
+	now = now.Add(time.Second)
+	for tick := 0; tick < 2; tick++ {
+		if err := relay.PublishPending(context.Background()); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if len(repo.published) != 2 || repo.published[0] != "article-5-1" || repo.published[1] != "article-5-2" {
+		t.Errorf("expected the article's events to be published in order, got %v", repo.published)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(&fakeRepo{}, &fakePublisher{}, fakeMetrics{}, "SPORTSTREAM", time.Second, 10).
+		WithBackoff(time.Second, time.Minute)
+
+	for attempts, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if got := relay.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+	if got := relay.backoff(40); got != time.Minute {
+		t.Errorf("expected the backoff to be capped at a minute, got %v", got)
+	}
+}
@@ -26,7 +26,7 @@ func (m *metricsHandler) registerDbMetrics() {
 			Name:      "db_error_counter",
 			Help:      "How many unexpected database error",
 		},
-		[]string{"method", "reason"},
+		[]string{"host", "shard", "method", "reason"},
 	)
 }
 
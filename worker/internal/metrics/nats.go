@@ -124,8 +124,8 @@ func LabelsFromError(err error) []string {
 		return []string{
 			Host,
 			function,
-			fmt.Sprint("%s", s[0]),
-			fmt.Sprint("%s", s[0]),
+			fmt.Sprintf("%s", s[0]),
+			fmt.Sprintf("%d", s[0]),
 		}
 	}
 
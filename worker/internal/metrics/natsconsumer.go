@@ -2,10 +2,16 @@ package metrics
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type natsConsumer struct {
-	natsErrorInc *prometheus.CounterVec
+	natsErrorInc       *prometheus.CounterVec
+	messageRejectedInc *prometheus.CounterVec
+	messageOutcomeInc  *prometheus.CounterVec
+	eventPublishedInc  *prometheus.CounterVec
+	queueDepth         *prometheus.GaugeVec
+	inFlight           *prometheus.GaugeVec
 }
 
 func (m *metricsHandler) registerNatsMetrics() {
@@ -18,6 +24,51 @@ func (m *metricsHandler) registerNatsMetrics() {
 		},
 		[]string{"host", "shard", "reason"},
 	)
+
+	m.natsConsumer.messageRejectedInc = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "messages_rejected_total",
+			Help:      "How many messages were rejected because no handler accepts their type and version.",
+		},
+		[]string{"host", "shard", "type", "version", "reason"},
+	)
+
+	m.natsConsumer.messageOutcomeInc = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "messages_settled_total",
+			Help:      "How many messages were acked, retried in place, redelivered or dead-lettered.",
+		},
+		[]string{"host", "shard", "outcome"},
+	)
+
+	m.natsConsumer.eventPublishedInc = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.namespace,
+			Name:      "events_published_total",
+			Help:      "How many article events were published from the outbox, by status.",
+		},
+		[]string{"host", "shard", "type", "status"},
+	)
+
+	m.natsConsumer.queueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.namespace,
+			Name:      "consumer_queue_depth",
+			Help:      "How many received messages are waiting for a worker.",
+		},
+		[]string{"host", "shard"},
+	)
+
+	m.natsConsumer.inFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.namespace,
+			Name:      "consumer_in_flight",
+			Help:      "How many received messages are queued or being handled and not yet settled.",
+		},
+		[]string{"host", "shard"},
+	)
 }
 
 // NatsErrorInc increases error counter when query fails
@@ -25,3 +76,33 @@ func (m *metricsHandler) NatsErrorInc(errMsg string) {
 	labels := m.baseLabelsWithValues(errMsg)
 	m.natsConsumer.natsErrorInc.WithLabelValues(labels...).Inc()
 }
+
+// MessageRejectedInc increases the counter of messages no handler accepts
+func (m *metricsHandler) MessageRejectedInc(msgType string, version string, reason string) {
+	labels := m.baseLabelsWithValues(msgType, version, reason)
+	m.natsConsumer.messageRejectedInc.WithLabelValues(labels...).Inc()
+}
+
+// MessageOutcomeInc increases the counter of how consumed messages were settled
+func (m *metricsHandler) MessageOutcomeInc(outcome string) {
+	labels := m.baseLabelsWithValues(outcome)
+	m.natsConsumer.messageOutcomeInc.WithLabelValues(labels...).Inc()
+}
+
+// EventPublishedInc increases the counter of article events relayed from the outbox
+func (m *metricsHandler) EventPublishedInc(eventType string, status string) {
+	labels := m.baseLabelsWithValues(eventType, status)
+	m.natsConsumer.eventPublishedInc.WithLabelValues(labels...).Inc()
+}
+
+// SetConsumerQueueDepth sets the number of messages waiting for a worker
+func (m *metricsHandler) SetConsumerQueueDepth(depth int) {
+	labels := m.baseLabelsWithValues()
+	m.natsConsumer.queueDepth.WithLabelValues(labels...).Set(float64(depth))
+}
+
+// SetConsumerInFlight sets the number of messages held and not yet settled
+func (m *metricsHandler) SetConsumerInFlight(count int) {
+	labels := m.baseLabelsWithValues()
+	m.natsConsumer.inFlight.WithLabelValues(labels...).Set(float64(count))
+}
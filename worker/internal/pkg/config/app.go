@@ -20,6 +20,29 @@ type AppConfig struct {
 	Env           Environment   `mapstructure:"ENVIRONMENT"`
 	Observability Observability `mapstructure:"OBSERVABILITY"`
 	Nats          Nats          `mapstructure:"NATS"`
+	Http          Http          `mapstructure:"HTTP"`
+	Migrations    Migrations    `mapstructure:"MIGRATIONS"`
+	Dates         Dates         `mapstructure:"DATES"`
+}
+
+// Dates lists the layouts upstream article dates are parsed with. Sources are
+// keyed by the source name articles are published under, the poller job's
+// SOURCENAME or entry name, not by its adapter.
+type Dates struct {
+	Formats  []string               `mapstructure:"FORMATS"`
+	Location string                 `mapstructure:"LOCATION"`
+	Sources  map[string]DateFormats `mapstructure:"SOURCES"`
+}
+
+type DateFormats struct {
+	Formats  []string `mapstructure:"FORMATS"`
+	Location string   `mapstructure:"LOCATION"`
+}
+
+type Migrations struct {
+	DryRun   bool          `mapstructure:"DRY_RUN"`
+	LockTTL  time.Duration `mapstructure:"LOCK_TTL"`
+	LockWait time.Duration `mapstructure:"LOCK_WAIT"`
 }
 
 type Environment struct {
@@ -39,6 +62,33 @@ type MessageQueueProcessor struct {
 type Nats struct {
 	ReconnectWait time.Duration `mapstructure:"RECONNECT_WAIT"`
 	Consumers     Consumers     `mapstructure:"CONSUMERS"`
+	DeadLetters   DeadLetters   `mapstructure:"DEAD_LETTERS"`
+	Events        Events        `mapstructure:"EVENTS"`
+}
+
+type Events struct {
+	SubjectPrefix string        `mapstructure:"SUBJECT_PREFIX"`
+	Interval      time.Duration `mapstructure:"INTERVAL"`
+	BatchSize     int           `mapstructure:"BATCH_SIZE"`
+	// A failed event is retried after BackoffBase, doubling per attempt up
+	// to BackoffMax.
+	BackoffBase time.Duration `mapstructure:"BACKOFF_BASE"`
+	BackoffMax  time.Duration `mapstructure:"BACKOFF_MAX"`
+}
+
+type DeadLetters struct {
+	Stream        string `mapstructure:"STREAM"`
+	Subject       string `mapstructure:"SUBJECT"`
+	ReplaySubject string `mapstructure:"REPLAY_SUBJECT"`
+}
+
+type Http struct {
+	HostAddress  string        `mapstructure:"HOST_ADDRESS"`
+	ReadTimeout  time.Duration `mapstructure:"READ_TIMEOUT"`
+	WriteTimeout time.Duration `mapstructure:"WRITE_TIMEOUT"`
+	// AdminToken is the bearer token the admin API requires. When empty the
+	// admin API only answers requests from localhost.
+	AdminToken string `mapstructure:"ADMIN_TOKEN"`
 }
 
 type Consumers struct {
@@ -50,10 +100,17 @@ type Articles struct {
 }
 
 type ArticleUpdate struct {
-	Subject       string `mapstructure:"SUBJECT"`
-	ConsumerName  string `mapstructure:"CONSUMER_NAME"`
-	ConsumerGroup string `mapstructure:"CONSUMER_GROUP"`
-	Stream        string `mapstructure:"STREAM"`
+	Subject           string        `mapstructure:"SUBJECT"`
+	ConsumerName      string        `mapstructure:"CONSUMER_NAME"`
+	ConsumerGroup     string        `mapstructure:"CONSUMER_GROUP"`
+	Stream            string        `mapstructure:"STREAM"`
+	MaxDeliveries     int           `mapstructure:"MAX_DELIVERIES"`
+	Backoff           time.Duration `mapstructure:"BACKOFF"`
+	MaxBackoff        time.Duration `mapstructure:"MAX_BACKOFF"`
+	RetryInPlace      int           `mapstructure:"RETRY_IN_PLACE"`
+	DeadLetterSubject string        `mapstructure:"DEAD_LETTER_SUBJECT"`
+	Workers           int           `mapstructure:"WORKERS"`
+	MaxInFlight       int           `mapstructure:"MAX_IN_FLIGHT"`
 }
 
 type Observability struct {
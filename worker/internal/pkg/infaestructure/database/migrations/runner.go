@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/ronnyp07/SportStream/pkg/migrate"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migration is one step of the worker's schema, applied by the shared runner.
+type Migration = migrate.Migration
+
+// NewRunner returns the shared migration runner, logging through the worker's
+// logger.
+func NewRunner(db *mongo.Database, component string, migrations []Migration) *migrate.Runner {
+	return migrate.NewRunner(db, component, migrations, logger{})
+}
+
+type logger struct{}
+
+func (logger) Info(ctx context.Context, msg string)  { log.Logger().Info(ctx, msg) }
+func (logger) Error(ctx context.Context, msg string) { log.Logger().Error(ctx, msg) }
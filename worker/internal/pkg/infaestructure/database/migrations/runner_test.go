@@ -0,0 +1,13 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/ronnyp07/SportStream/pkg/migrate"
+)
+
+func TestWorker(t *testing.T) {
+	if err := migrate.Validate(Worker(nil)); err != nil {
+		t.Errorf("expected the worker migrations to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/pkg/articledb"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Component names the worker's migrations in schema_migrations.
+const Component = "worker"
+
+const (
+	articlesCollectionName  = articledb.ArticlesCollection
+	revisionsCollectionName = articledb.RevisionsCollection
+	outboxCollectionName    = articledb.OutboxCollection
+
+	// outboxRetention is how long published events are kept for inspection.
+	// It plays no part in deduplication: an article's queuedRevision stops
+	// its event being queued again once the event has expired.
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Worker lists the migrations of the collections the worker writes. Dates are
+// converted with the same parser the worker stores new articles with.
+func Worker(parseDate services.IDateParser) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "remove duplicate articles per externalID, keeping the latest revision",
+			Up:          dedupeExternalIDs,
+		},
+		{
+			Version:     2,
+			Description: "unique index on articles.externalID",
+			Up: createIndex(articlesCollectionName, mongo.IndexModel{
+				Keys:    bson.D{{Key: "externalID", Value: 1}},
+				Options: options.Index().SetName("externalID_unique").SetUnique(true),
+			}),
+		},
+		{
+			Version:     3,
+			Description: "unique index on articles.id",
+			Up: createIndex(articlesCollectionName, mongo.IndexModel{
+				Keys:    bson.D{{Key: "id", Value: 1}},
+				Options: options.Index().SetName("id_unique").SetUnique(true),
+			}),
+		},
+		{
+			Version:     4,
+			Description: "backfill revision and createdAt on articles stored before revisions",
+			Up:          backfillRevisions,
+		},
+		{
+			Version:     5,
+			Description: "unique index on article_revisions (articleID, revision)",
+			Up: createIndex(revisionsCollectionName, mongo.IndexModel{
+				Keys:    bson.D{{Key: "articleID", Value: 1}, {Key: "revision", Value: -1}},
+				Options: options.Index().SetName("articleID_revision_unique").SetUnique(true),
+			}),
+		},
+		{
+			Version:     6,
+			Description: "expire published article_outbox events",
+			Up: createIndex(outboxCollectionName, mongo.IndexModel{
+				Keys: bson.D{{Key: "publishedAt", Value: 1}},
+				Options: options.Index().SetName("publishedAt_ttl").
+					SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+			}),
+		},
+		{
+			Version:     7,
+			Description: "convert article dates to publishedAt and updatedAt timestamps",
+			Up:          backfillDates(parseDate),
+		},
+		{
+			Version:     8,
+			Description: "limit the articles.externalID unique index to feed articles",
+			Up:          partialExternalIDIndex,
+		},
+		{
+			Version:     9,
+			Description: "backfill queuedRevision on articles whose events were queued with them",
+			Up:          backfillQueuedRevisions,
+		},
+	}
+}
+
+// createIndex returns a step creating the index; creating an index that
+// already exists with the same options is a no-op.
+func createIndex(collection string, index mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, index)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create index on %s", collection)
+		}
+		return nil
+	}
+}
+
+// dedupeExternalIDs removes the copies left by upserts that raced before
+// externalID was unique, so the unique index can be built.
+func dedupeExternalIDs(ctx context.Context, db *mongo.Database) error {
+	articles := db.Collection(articlesCollectionName)
+
+	cursor, err := articles.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "revision", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$externalID"},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return errors.Wrap(err, "failed to find duplicate articles")
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			ExternalID interface{}   `bson:"_id"`
+			IDs        []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return errors.Wrap(err, "failed to decode duplicate articles")
+		}
+
+		res, err := articles.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": group.IDs[1:]}})
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove duplicates of externalID %v", group.ExternalID)
+		}
+		log.Logger().Info(ctx, fmt.Sprintf("removed %d duplicate articles with externalID %v",
+			res.DeletedCount, group.ExternalID))
+	}
+	return errors.Wrap(cursor.Err(), "failed to iterate duplicate articles")
+}
+
+// backfillRevisions gives articles stored before revisions were tracked their
+// first revision, dated from their ObjectID.
+func backfillRevisions(ctx context.Context, db *mongo.Database) error {
+	articles := db.Collection(articlesCollectionName)
+
+	_, err := articles.UpdateMany(ctx,
+		bson.M{"revision": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revision": 1}},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to backfill revision")
+	}
+
+	_, err = articles.UpdateMany(ctx,
+		bson.M{"createdAt": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{
+			{Key: "createdAt", Value: bson.D{{Key: "$toDate", Value: "$_id"}}},
+		}}}},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to backfill createdAt")
+	}
+	return nil
+}
+
+// backfillDates parses the date of articles stored before publishedAt was
+// recorded, and dates their current revision from its creation.
+func backfillDates(parser services.IDateParser) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		articles := db.Collection(articlesCollectionName)
+
+		cursor, err := articles.Find(ctx,
+			bson.M{"publishedAt": bson.M{"$exists": false}},
+			options.Find().SetProjection(bson.M{"date": 1, "source": 1}),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to find undated articles")
+		}
+		defer cursor.Close(ctx)
+
+		var writes []mongo.WriteModel
+		unparsed := 0
+		for cursor.Next(ctx) {
+			var article struct {
+				ID     interface{} `bson:"_id"`
+				Date   string      `bson:"date"`
+				Source string      `bson:"source"`
+			}
+			if err := cursor.Decode(&article); err != nil {
+				return errors.Wrap(err, "failed to decode undated article")
+			}
+
+			publishedAt, err := parser.Parse(article.Source, article.Date)
+			if err != nil {
+				unparsed++
+				continue
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": article.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"publishedAt": publishedAt}}))
+		}
+		if err := cursor.Err(); err != nil {
+			return errors.Wrap(err, "failed to iterate undated articles")
+		}
+
+		if len(writes) > 0 {
+			if _, err := articles.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+				return errors.Wrap(err, "failed to backfill publishedAt")
+			}
+		}
+		if unparsed > 0 {
+			log.Logger().Info(ctx, fmt.Sprintf("left %d articles without publishedAt, their date matches no format", unparsed))
+		}
+
+		_, err = articles.UpdateMany(ctx,
+			bson.M{"updatedAt": bson.M{"$exists": false}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "updatedAt", Value: "$createdAt"}}}}},
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to backfill updatedAt")
+		}
+		return nil
+	}
+}
+
+// partialExternalIDIndex lets the stories editors write through the api,
+// which have no externalID, share the collection: only feed articles need a
+// unique externalID.
+func partialExternalIDIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(articlesCollectionName).Indexes().DropOne(ctx, "externalID_unique")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+		return errors.Wrap(err, "failed to drop the externalID index")
+	}
+
+	return createIndex(articlesCollectionName, mongo.IndexModel{
+		Keys: bson.D{{Key: "externalID", Value: 1}},
+		Options: options.Index().SetName("externalID_unique").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"externalID": bson.M{"$exists": true}}),
+	})(ctx, db)
+}
+
+// backfillQueuedRevisions marks the current revision of every article queued,
+// as the events of articles stored before the marker were written along with
+// them. Without it every such article would queue its event again.
+func backfillQueuedRevisions(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(articlesCollectionName).UpdateMany(ctx,
+		bson.M{"queuedRevision": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{
+			{Key: "queuedRevision", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$revision", 1}}}},
+		}}}},
+	)
+	return errors.Wrap(err, "failed to backfill queuedRevision")
+}
@@ -5,119 +5,556 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/pkg/articledb"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/msgtype"
+	"github.com/sts-solutions/base-code/cccorrelation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const (
-	collectionName = "articles"
-)
-
 type ArticleRepository struct {
 	collection *mongo.Collection
+	revisions  *mongo.Collection
+	outbox     *mongo.Collection
 	metrics    metrics.MetricsHandler
 }
 
 func NewArticleRepository(db *mongo.Database,
 	metrics metrics.MetricsHandler) *ArticleRepository {
 	return &ArticleRepository{
-		collection: db.Collection(collectionName),
+		collection: db.Collection(articledb.ArticlesCollection),
+		revisions:  db.Collection(articledb.RevisionsCollection),
+		outbox:     db.Collection(articledb.OutboxCollection),
 		metrics:    metrics,
 	}
 }
 
-const counterCollectionName = "article_counters"
+// storedArticle is an article document together with the change tracking
+// fields the worker maintains on it.
+type storedArticle struct {
+	models.Article `bson:",inline"`
+	ExternalID     int    `bson:"externalID"`
+	ContentHash    string `bson:"contentHash"`
+	Revision       int    `bson:"revision"`
+	// UpdatedAt is when the current revision was stored.
+	UpdatedAt time.Time `bson:"updatedAt"`
+	// Editorial is written by the api only; upserts keep its overrides.
+	Editorial *editorial `bson:"editorial,omitempty"`
+	// Upstream keeps the feed's values of the overridden fields, so an
+	// editor can revert to them.
+	Upstream map[string]string `bson:"upstream,omitempty"`
+	// QueuedRevision is the latest revision whose event is in the outbox. It's
+	// set once the event is written, so an event lost to a crash in between is
+	// queued by the next upsert of the article. Upserts leave it out.
+	QueuedRevision int `bson:"queuedRevision,omitempty"`
+}
 
-func (r *ArticleRepository) getNextArticleID(ctx context.Context) (int, error) {
-	r.metrics.DBCall("getNextArticleID")
+// editorial is the part of the api's editorial state upserts honour: the
+// fields editors overrode, by their name in the article document.
+type editorial struct {
+	Overrides map[string]string `bson:"overrides,omitempty"`
+}
+
+// editableFields are the fields editors can override.
+var editableFields = map[string]func(*models.Article) *string{
+	"title":       func(a *models.Article) *string { return &a.Title },
+	"description": func(a *models.Article) *string { return &a.Description },
+	"summary":     func(a *models.Article) *string { return &a.Summary },
+	"body":        func(a *models.Article) *string { return &a.Body },
+}
+
+// withOverrides returns the feed's article with the editors' overrides
+// applied, and the feed's values of the fields they replaced.
+func (s *storedArticle) withOverrides(article models.Article) (models.Article, map[string]string) {
+	if s == nil || s.Editorial == nil || len(s.Editorial.Overrides) == 0 {
+		return article, nil
+	}
 
-	counterCollection := r.collection.Database().Collection(counterCollectionName)
+	upstream := make(map[string]string, len(s.Editorial.Overrides))
+	for name, value := range s.Editorial.Overrides {
+		field, ok := editableFields[name]
+		if !ok {
+			continue
+		}
+		upstream[name] = *field(&article)
+		*field(&article) = value
+	}
+	return article, upstream
+}
 
-	filter := bson.M{"_id": "article_id"}
-	update := bson.M{"$inc": bson.M{"seq": 1}}
-	opts := options.FindOneAndUpdate().
-		SetUpsert(true).
-		SetReturnDocument(options.After)
+// hash returns the stored content hash, computing it for articles written
+// before hashes were recorded.
+func (s storedArticle) hash() string {
+	if s.ContentHash != "" {
+		return s.ContentHash
+	}
+	return s.Article.ContentHash()
+}
 
-	var result struct {
-		Seq int `bson:"seq"`
+// revision returns the current revision, articles written before revisions
+// were recorded being on their first one.
+func (s storedArticle) revision() int {
+	if s.Revision < 1 {
+		return 1
 	}
+	return s.Revision
+}
 
-	err := counterCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+// queued reports whether the event of the current revision is in the outbox.
+func (s storedArticle) queued() bool {
+	return s.QueuedRevision >= s.revision()
+}
 
-	if err != nil {
-		r.metrics.DBErrorInc("getNextArticleID", err.Error())
-		return 0, errors.Wrap(err, "failed to get next article ID")
+// upsertPlan is what an incoming article needs written, given its stored
+// version. Content is the article as stored: the feed's article with the
+// editors' overrides applied. Queue is set when the event of the resulting
+// revision still has to be written to the outbox.
+type upsertPlan struct {
+	article          models.UpsertArticle
+	content          models.Article
+	upstream         map[string]string
+	previousRevision int
+	result           models.UpsertResult
+	revision         *models.ArticleRevision
+	queue            bool
+}
+
+func planUpsert(article models.UpsertArticle, previous *storedArticle, now time.Time) upsertPlan {
+	plan := upsertPlan{
+		article: article,
+		result:  models.UpsertResult{ExternalID: article.ExternalID},
+	}
+	plan.content, plan.upstream = previous.withOverrides(article.Article)
+	if previous != nil {
+		plan.previousRevision = previous.Revision
+	}
+
+	switch {
+	case previous == nil:
+		plan.result.Inserted = true
+		plan.result.Revision = 1
+		plan.queue = true
+	case previous.hash() == article.Article.ContentHash():
+		plan.result.ID = previous.ID
+		plan.result.Revision = previous.revision()
+		plan.result.Unchanged = true
+		plan.queue = !previous.queued()
+	default:
+		changes := previous.Article.Diff(plan.content)
+		plan.result.ID = previous.ID
+		if len(changes) == 0 {
+			// Only overridden fields changed: the feed's values are stored, but
+			// what readers see stays the same revision.
+			plan.result.Revision = previous.revision()
+			plan.queue = !previous.queued()
+			break
+		}
+		plan.result.Revision = previous.revision() + 1
+		plan.queue = true
+		for _, change := range changes {
+			plan.result.ChangedFields = append(plan.result.ChangedFields, change.Field)
+		}
+		plan.revision = &models.ArticleRevision{
+			ArticleID:  previous.ID,
+			ExternalID: article.ExternalID,
+			Revision:   previous.revision(),
+			Article:    previous.Article,
+			Changes:    changes,
+			CreatedAt:  now,
+		}
+	}
+
+	return plan
+}
+
+// update builds the article upsert for the plan once its ID is known.
+func (p upsertPlan) update(now time.Time) bson.M {
+	// The hash is the feed's, so an unchanged feed article is still
+	// recognised once it's overridden. Editorial is left out of the update.
+	stored := storedArticle{
+		Article: models.Article{
+			ID:          p.result.ID,
+			Title:       p.content.Title,
+			Description: p.content.Description,
+			Date:        p.content.Date,
+			Body:        p.content.Body,
+			Summary:     p.content.Summary,
+			LeadMedia:   p.content.LeadMedia,
+			Tags:        p.content.Tags,
+			Source:      p.content.Source,
+			PublishedAt: p.content.PublishedAt,
+		},
+		ExternalID:  p.article.ExternalID,
+		ContentHash: p.article.Article.ContentHash(),
+		Revision:    p.result.Revision,
+		UpdatedAt:   now,
+		Upstream:    p.upstream,
+	}
+
+	return bson.M{
+		"$set": stored,
+		"$setOnInsert": bson.M{
+			"createdAt": now,
+		},
+	}
+}
+
+// filter matches the stored version the plan was made from. An article the
+// api edited in the meantime isn't matched, so the upsert collides on the
+// unique externalID instead of overwriting the edit, and the article is
+// retried from its new version.
+func (p upsertPlan) filter() bson.M {
+	filter := bson.M{"externalID": p.article.ExternalID}
+	if p.previousRevision > 0 {
+		filter["revision"] = p.previousRevision
 	}
+	return filter
+}
+
+// outboxUpsert records the event announcing the plan's revision. The event is
+// keyed by article and revision and only inserted when missing, so writing it
+// again after a failure doesn't duplicate it.
+func (p upsertPlan) outboxUpsert(correlationID string, now time.Time) (bson.M, bson.M) {
+	eventType := msgtype.ArticleUpdated
+	if p.result.Revision == 1 {
+		eventType = msgtype.ArticleCreated
+	}
+
+	event := models.OutboxEvent{
+		ID:   articledb.EventID(p.result.ID, p.result.Revision),
+		Type: eventType.Name(),
+		Event: models.ArticleEvent{
+			ID:            p.result.ID,
+			ExternalID:    p.article.ExternalID,
+			Revision:      p.result.Revision,
+			ChangedFields: p.result.ChangedFields,
+			Source:        p.article.Source,
+			Tags:          p.article.Tags,
+		},
+		CorrelationID: correlationID,
+		CreatedAt:     now,
+	}
+
+	return event.Upsert()
+}
+
+// queuedUpdate marks the plan's event as queued on the article.
+func (p upsertPlan) queuedUpdate() (bson.M, bson.M) {
+	return articledb.MarkQueued(p.result.ID, p.result.Revision)
+}
+
+func correlationID(ctx context.Context) string {
+	_, id := cccorrelation.GetCorrelationId(ctx)
+	return id
+}
+
+// revisionUpsert keys the revision by article and revision number, so writing
+// it again after a failed article update does not duplicate it.
+func revisionUpsert(revision *models.ArticleRevision) (bson.M, bson.M) {
+	filter := bson.M{"articleID": revision.ArticleID, "revision": revision.Revision}
+	return filter, bson.M{"$setOnInsert": revision}
+}
+
+func (r *ArticleRepository) getNextArticleID(ctx context.Context) (int, error) {
+	return r.reserveArticleIDs(ctx, 1)
+}
 
-	return result.Seq, nil
+// reserveArticleIDs reserves count consecutive article IDs with a single counter
+// increment and returns the first one.
+func (r *ArticleRepository) reserveArticleIDs(ctx context.Context, count int) (int, error) {
+	r.metrics.DBCall("reserveArticleIDs")
+
+	id, err := articledb.ReserveIDs(ctx, r.collection.Database(), count)
+	if err != nil {
+		r.metrics.DBErrorInc("reserveArticleIDs", err.Error())
+		return 0, err
+	}
+	return id, nil
 }
 
-func (r *ArticleRepository) UpsertByExternalID(ctx context.Context, article models.UpsertArticle) (models.Article, error) {
+// UpsertByExternalID writes a single article. Unchanged articles are skipped;
+// changed ones move their previous version to the revisions collection. The
+// event is queued after the article is written, for new and changed articles
+// and for any whose event was never queued.
+func (r *ArticleRepository) UpsertByExternalID(ctx context.Context, article models.UpsertArticle) (models.UpsertResult, error) {
 	r.metrics.DBCall("UpsertByExternalID")
 	now := time.Now()
-	var updatedArticle models.Article
-	// Check if document exists
-	var existing struct {
-		ID int `bson:"id"`
-	}
-	err := r.collection.FindOne(
-		ctx,
-		bson.M{"externalID": article.ExternalID},
-		options.FindOne().SetProjection(bson.M{"id": 1}),
-	).Decode(&existing)
-
-	var articleID int
-	if err == mongo.ErrNoDocuments {
-		articleID, err = r.getNextArticleID(ctx)
+
+	var previous *storedArticle
+	var existing storedArticle
+	err := r.collection.FindOne(ctx, bson.M{"externalID": article.ExternalID}).Decode(&existing)
+	if err == nil {
+		previous = &existing
+	} else if err != mongo.ErrNoDocuments {
+		r.metrics.DBErrorInc("UpsertByExternalID", "find_error")
+		return models.UpsertResult{}, errors.Wrap(err, "failed to check existing article")
+	}
+
+	plan := planUpsert(article, previous, now)
+	if plan.result.Unchanged {
+		return plan.result, r.queueEvent(ctx, plan, now)
+	}
+
+	if plan.result.Inserted {
+		plan.result.ID, err = r.getNextArticleID(ctx)
 		if err != nil {
 			r.metrics.DBErrorInc("UpsertByExternalID", "sequence_error")
-			return updatedArticle, errors.Wrap(err, "failed to get next article ID")
+			return models.UpsertResult{}, errors.Wrap(err, "failed to get next article ID")
 		}
-	} else if err != nil {
-		r.metrics.DBErrorInc("UpsertByExternalID", "find_error")
-		return updatedArticle, errors.Wrap(err, "failed to check existing article")
-	} else {
-		articleID = existing.ID
-	}
-
-	// Prepare full article
-	fullArticle := models.Article{
-		ID:          articleID,
-		Title:       article.Title,
-		Description: article.Description,
-		Date:        article.Date,
-		Body:        article.Body,
-		Summary:     article.Summary,
-		LeadMedia:   article.LeadMedia,
-		Tags:        article.Tags,
-	}
-
-	opts := options.FindOneAndUpdate().
-		SetUpsert(true).
-		SetReturnDocument(options.After)
-
-	filter := bson.M{"externalID": article.ExternalID}
-	update := bson.M{
-		"$set": fullArticle,
-		"$setOnInsert": bson.M{
-			"createdAt":  now,
-			"externalID": article.ExternalID,
-		},
 	}
 
-	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updatedArticle)
+	if plan.revision != nil {
+		filter, update := revisionUpsert(plan.revision)
+		_, err = r.revisions.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err != nil {
+			r.metrics.DBErrorInc("UpsertByExternalID", "revision_error")
+			return models.UpsertResult{}, errors.Wrap(err, "failed to store article revision")
+		}
+	}
 
+	_, err = r.collection.UpdateOne(ctx,
+		plan.filter(),
+		plan.update(now),
+		options.Update().SetUpsert(true),
+	)
 	if err != nil {
 		r.metrics.DBErrorInc("UpsertByExternalID", "upsert_error")
-		if err == mongo.ErrNoDocuments {
-			return updatedArticle, errors.New("failed to upsert article")
+		return models.UpsertResult{}, errors.Wrap(err, "failed to upsert article")
+	}
+
+	return plan.result, r.queueEvent(ctx, plan, now)
+}
+
+// queueEvent writes the plan's event to the outbox, if it needs one, then
+// marks it queued on the article.
+func (r *ArticleRepository) queueEvent(ctx context.Context, plan upsertPlan, now time.Time) error {
+	if !plan.queue {
+		return nil
+	}
+
+	filter, update := plan.outboxUpsert(correlationID(ctx), now)
+	_, err := r.outbox.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		r.metrics.DBErrorInc("UpsertByExternalID", "outbox_error")
+		return errors.Wrap(err, "failed to write article event to the outbox")
+	}
+
+	filter, update = plan.queuedUpdate()
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		r.metrics.DBErrorInc("UpsertByExternalID", "queued_error")
+		return errors.Wrap(err, "failed to mark the article event queued")
+	}
+	return nil
+}
+
+// existingArticles maps the external IDs already stored to their documents.
+func (r *ArticleRepository) existingArticles(ctx context.Context, externalIDs []int) (map[int]*storedArticle, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"externalID": bson.M{"$in": externalIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []storedArticle
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, err
+	}
+
+	articles := make(map[int]*storedArticle, len(existing))
+	for i := range existing {
+		articles[existing[i].ExternalID] = &existing[i]
+	}
+	return articles, nil
+}
+
+// BulkUpsertByExternalID upserts a batch of articles with one lookup, one
+// counter increment for the new articles and a single unordered BulkWrite.
+// Unchanged articles are skipped and the previous versions of changed ones are
+// written to the revisions collection first. When an external ID appears more
+// than once the last occurrence wins. The returned error covers failures of
+// the whole batch; failures of single articles are reported in their result.
+func (r *ArticleRepository) BulkUpsertByExternalID(ctx context.Context, articles []models.UpsertArticle) ([]models.UpsertResult, error) {
+	r.metrics.DBCall("BulkUpsertByExternalID")
+	now := time.Now()
+
+	batch := make([]models.UpsertArticle, 0, len(articles))
+	positions := make(map[int]int, len(articles))
+	for _, article := range articles {
+		if i, ok := positions[article.ExternalID]; ok {
+			batch[i] = article
+			continue
+		}
+		positions[article.ExternalID] = len(batch)
+		batch = append(batch, article)
+	}
+	if len(batch) == 0 {
+		return []models.UpsertResult{}, nil
+	}
+
+	externalIDs := make([]int, 0, len(batch))
+	for _, article := range batch {
+		externalIDs = append(externalIDs, article.ExternalID)
+	}
+
+	existing, err := r.existingArticles(ctx, externalIDs)
+	if err != nil {
+		r.metrics.DBErrorInc("BulkUpsertByExternalID", "find_error")
+		return nil, errors.Wrap(err, "failed to check existing articles")
+	}
+
+	plans := make([]upsertPlan, len(batch))
+	newArticles := 0
+	for i, article := range batch {
+		plans[i] = planUpsert(article, existing[article.ExternalID], now)
+		if plans[i].result.Inserted {
+			newArticles++
+		}
+	}
+
+	if newArticles > 0 {
+		nextID, err := r.reserveArticleIDs(ctx, newArticles)
+		if err != nil {
+			r.metrics.DBErrorInc("BulkUpsertByExternalID", "sequence_error")
+			return nil, errors.Wrap(err, "failed to reserve article IDs")
+		}
+		for i := range plans {
+			if plans[i].result.Inserted {
+				plans[i].result.ID = nextID
+				nextID++
+			}
+		}
+	}
+
+	if err := r.writeRevisions(ctx, plans); err != nil {
+		return nil, err
+	}
+
+	// writes[j] belongs to plans[planIndex[j]]; unchanged and failed articles are not written.
+	writes := []mongo.WriteModel{}
+	planIndex := []int{}
+	for i, plan := range plans {
+		if plan.result.Unchanged || plan.result.Err != nil {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(plan.filter()).
+			SetUpdate(plan.update(now)).
+			SetUpsert(true))
+		planIndex = append(planIndex, i)
+	}
+
+	if len(writes) > 0 {
+		res, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err := r.bulkWriteErrors(err, plans, planIndex); err != nil {
+			return nil, errors.Wrap(err, "failed to bulk upsert articles")
+		}
+		if res != nil {
+			for j := range res.UpsertedIDs {
+				plans[planIndex[j]].result.Inserted = true
+			}
+		}
+	}
+
+	if err := r.writeOutboxEvents(ctx, plans, now); err != nil {
+		return nil, err
+	}
+	if err := r.markQueued(ctx, plans); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.UpsertResult, len(plans))
+	for i, plan := range plans {
+		results[i] = plan.result
+	}
+	return results, nil
+}
+
+// writeRevisions stores the previous versions of the changed articles in one
+// unordered BulkWrite. Articles whose revision fails are marked as failed so
+// they are not overwritten without their history.
+func (r *ArticleRepository) writeRevisions(ctx context.Context, plans []upsertPlan) error {
+	writes := []mongo.WriteModel{}
+	planIndex := []int{}
+	for i, plan := range plans {
+		if plan.revision == nil {
+			continue
+		}
+		filter, update := revisionUpsert(plan.revision)
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+		planIndex = append(planIndex, i)
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err := r.revisions.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return errors.Wrap(r.bulkWriteErrors(err, plans, planIndex), "failed to store article revisions")
+}
+
+// writeOutboxEvents adds the events the plans queue to the outbox in one
+// unordered BulkWrite. Articles whose event fails are marked as failed so
+// their retry writes it again.
+func (r *ArticleRepository) writeOutboxEvents(ctx context.Context, plans []upsertPlan, now time.Time) error {
+	correlationID := correlationID(ctx)
+
+	writes := []mongo.WriteModel{}
+	planIndex := []int{}
+	for i, plan := range plans {
+		if !plan.queue || plan.result.Err != nil {
+			continue
+		}
+		filter, update := plan.outboxUpsert(correlationID, now)
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+		planIndex = append(planIndex, i)
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err := r.outbox.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return errors.Wrap(r.bulkWriteErrors(err, plans, planIndex), "failed to write article events to the outbox")
+}
+
+// markQueued marks the events written by writeOutboxEvents queued on their
+// articles in one unordered BulkWrite. An article left unmarked has its event
+// written again, harmlessly, by its retry.
+func (r *ArticleRepository) markQueued(ctx context.Context, plans []upsertPlan) error {
+	writes := []mongo.WriteModel{}
+	planIndex := []int{}
+	for i, plan := range plans {
+		if !plan.queue || plan.result.Err != nil {
+			continue
 		}
-		return updatedArticle, err
+		filter, update := plan.queuedUpdate()
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+		planIndex = append(planIndex, i)
+	}
+	if len(writes) == 0 {
+		return nil
 	}
 
-	return updatedArticle, nil
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return errors.Wrap(r.bulkWriteErrors(err, plans, planIndex), "failed to mark article events queued")
+}
+
+// bulkWriteErrors records the write errors of a BulkWrite on the plans the
+// writes belong to, and returns the error when the whole write failed.
+func (r *ArticleRepository) bulkWriteErrors(err error, plans []upsertPlan, planIndex []int) error {
+	if err == nil {
+		return nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		r.metrics.DBErrorInc("BulkUpsertByExternalID", "bulk_write_error")
+		return err
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		r.metrics.DBErrorInc("BulkUpsertByExternalID", "upsert_error")
+		plans[planIndex[writeErr.Index]].result.Err = writeErr
+	}
+	return nil
 }
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+func TestPlanUpsert(t *testing.T) {
+	now := time.Now()
+	stored := &storedArticle{
+		Article:        models.Article{ID: 5, Title: "Final", Body: "Report"},
+		ExternalID:     42,
+		Revision:       3,
+		QueuedRevision: 3,
+	}
+	stored.ContentHash = stored.Article.ContentHash()
+
+	incoming := func(title string) models.UpsertArticle {
+		return models.UpsertArticle{ExternalID: 42, Article: models.Article{ID: 42, Title: title, Body: "Report"}}
+	}
+
+	t.Run("new article", func(t *testing.T) {
+		plan := planUpsert(incoming("Final"), nil, now)
+		if !plan.result.Inserted || plan.result.Revision != 1 || plan.revision != nil || !plan.queue {
+			t.Errorf("unexpected plan %+v", plan)
+		}
+	})
+
+	t.Run("unchanged article", func(t *testing.T) {
+		plan := planUpsert(incoming("Final"), stored, now)
+		if !plan.result.Unchanged || plan.result.ID != 5 || plan.revision != nil {
+			t.Errorf("unexpected plan %+v", plan)
+		}
+		if plan.queue {
+			t.Error("expected the queued event not to be queued again")
+		}
+	})
+
+	t.Run("unchanged article whose event was not queued", func(t *testing.T) {
+		unqueued := *stored
+		unqueued.QueuedRevision = 2
+		plan := planUpsert(incoming("Final"), &unqueued, now)
+		if !plan.result.Unchanged || !plan.queue || plan.result.Revision != 3 {
+			t.Errorf("expected the event of revision 3 to be queued, got %+v", plan)
+		}
+	})
+
+	t.Run("changed article", func(t *testing.T) {
+		plan := planUpsert(incoming("Final (updated)"), stored, now)
+		if plan.result.Unchanged || plan.result.ID != 5 || plan.result.Revision != 4 {
+			t.Fatalf("unexpected result %+v", plan.result)
+		}
+		if len(plan.result.ChangedFields) != 1 || plan.result.ChangedFields[0] != "title" {
+			t.Errorf("expected the title to change, got %v", plan.result.ChangedFields)
+		}
+		if plan.revision == nil || plan.revision.Revision != 3 || plan.revision.Article.Title != "Final" {
+			t.Errorf("expected revision 3 to keep the previous version, got %+v", plan.revision)
+		}
+		if !plan.queue {
+			t.Error("expected the event of revision 4 to be queued")
+		}
+		if set := plan.update(now)["$set"].(storedArticle); set.QueuedRevision != 0 {
+			t.Errorf("expected the upsert to leave the queued revision alone, got %d", set.QueuedRevision)
+		}
+	})
+
+	t.Run("legacy article without hash or revision", func(t *testing.T) {
+		legacy := &storedArticle{Article: stored.Article, ExternalID: 42}
+		if plan := planUpsert(incoming("Final"), legacy, now); !plan.result.Unchanged {
+			t.Errorf("expected the legacy article to be unchanged, got %+v", plan.result)
+		}
+		if plan := planUpsert(incoming("Other"), legacy, now); plan.revision.Revision != 1 || plan.result.Revision != 2 {
+			t.Errorf("expected the legacy article to become revision 2, got %+v", plan.result)
+		}
+	})
+}
+
+func TestPlanUpsert_EditorialOverrides(t *testing.T) {
+	now := time.Now()
+	feed := models.Article{ID: 42, Title: "Final", Summary: "Close game", Body: "Report"}
+	stored := &storedArticle{
+		Article:     models.Article{ID: 5, Title: "Edited", Summary: "Close game", Body: "Report"},
+		ExternalID:  42,
+		ContentHash: feed.ContentHash(),
+		Revision:    3,
+		Editorial:   &editorial{Overrides: map[string]string{"title": "Edited"}},
+	}
+
+	incoming := func(change func(a *models.Article)) models.UpsertArticle {
+		article := feed
+		change(&article)
+		return models.UpsertArticle{ExternalID: 42, Article: article}
+	}
+
+	t.Run("unchanged feed", func(t *testing.T) {
+		plan := planUpsert(incoming(func(*models.Article) {}), stored, now)
+		if !plan.result.Unchanged {
+			t.Errorf("expected the article to be unchanged, got %+v", plan.result)
+		}
+	})
+
+	t.Run("override kept over a changed feed", func(t *testing.T) {
+		plan := planUpsert(incoming(func(a *models.Article) { a.Body = "Full report" }), stored, now)
+		if plan.result.Revision != 4 || len(plan.result.ChangedFields) != 1 || plan.result.ChangedFields[0] != "body" {
+			t.Fatalf("expected only the body to change, got %+v", plan.result)
+		}
+		if plan.content.Title != "Edited" || plan.upstream["title"] != "Final" {
+			t.Errorf("expected the edited title over the feed's, got %q and %v", plan.content.Title, plan.upstream)
+		}
+		set := plan.update(now)["$set"].(storedArticle)
+		if set.Title != "Edited" || set.Editorial != nil || set.ContentHash != incoming(func(a *models.Article) { a.Body = "Full report" }).ContentHash() {
+			t.Errorf("unexpected update %+v", set)
+		}
+	})
+
+	t.Run("only an overridden field changed", func(t *testing.T) {
+		plan := planUpsert(incoming(func(a *models.Article) { a.Title = "Final (updated)" }), stored, now)
+		if plan.result.Unchanged || plan.result.Revision != 3 || plan.revision != nil || len(plan.result.ChangedFields) != 0 {
+			t.Errorf("expected the feed's value stored without a revision, got %+v", plan.result)
+		}
+		if plan.content.Title != "Edited" || plan.upstream["title"] != "Final (updated)" {
+			t.Errorf("expected the edited title over the feed's, got %q and %v", plan.content.Title, plan.upstream)
+		}
+	})
+
+	t.Run("filter guards the stored revision", func(t *testing.T) {
+		plan := planUpsert(incoming(func(a *models.Article) { a.Body = "Full report" }), stored, now)
+		if filter := plan.filter(); filter["externalID"] != 42 || filter["revision"] != 3 {
+			t.Errorf("unexpected filter %v", filter)
+		}
+		if filter := planUpsert(incoming(func(*models.Article) {}), nil, now).filter(); len(filter) != 1 {
+			t.Errorf("expected a new article matched by externalID only, got %v", filter)
+		}
+	})
+}
+
+func TestUpsertPlan_OutboxUpsert(t *testing.T) {
+	now := time.Now()
+	article := models.UpsertArticle{ExternalID: 42, Article: models.Article{ID: 42, Title: "Final", Source: "ecb"}}
+
+	created := planUpsert(article, nil, now)
+	created.result.ID = 5
+	filter, update := created.outboxUpsert("abc", now)
+	event := update["$setOnInsert"].(models.OutboxEvent)
+	if filter["_id"] != "article-5-1" || event.Type != "article.created" || event.CorrelationID != "abc" {
+		t.Errorf("unexpected created event %v %+v", filter, event)
+	}
+
+	stored := &storedArticle{Article: models.Article{ID: 5, Title: "Draft", Source: "ecb"}, ExternalID: 42, Revision: 1}
+	updated := planUpsert(article, stored, now)
+	filter, update = updated.outboxUpsert("", now)
+	event = update["$setOnInsert"].(models.OutboxEvent)
+	if filter["_id"] != "article-5-2" || event.Type != "article.updated" {
+		t.Errorf("unexpected updated event %v %+v", filter, event)
+	}
+	if event.Event.Revision != 2 || len(event.Event.ChangedFields) != 1 || event.Event.ChangedFields[0] != "title" {
+		t.Errorf("unexpected event payload %+v", event.Event)
+	}
+}
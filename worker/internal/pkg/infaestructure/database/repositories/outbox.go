@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ronnyp07/SportStream/pkg/articledb"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	collection *mongo.Collection
+	metrics    metrics.MetricsHandler
+}
+
+func NewOutboxRepository(db *mongo.Database,
+	metrics metrics.MetricsHandler) *OutboxRepository {
+	return &OutboxRepository{
+		collection: db.Collection(articledb.OutboxCollection),
+		metrics:    metrics,
+	}
+}
+
+// Pending returns the unpublished events due by now, oldest first. Only the
+// earliest unpublished event of each article is returned, so a later event
+// never overtakes one still waiting on a retry. Failed events are due again at
+// their next attempt.
+func (r *OutboxRepository) Pending(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	r.metrics.DBCall("OutboxPending")
+
+	cursor, err := r.collection.Aggregate(ctx, pendingPipeline(now, limit), options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		r.metrics.DBErrorInc("OutboxPending", "find_error")
+		return nil, errors.Wrap(err, "failed to find pending events")
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.OutboxEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		r.metrics.DBErrorInc("OutboxPending", "decode_error")
+		return nil, errors.Wrap(err, "failed to decode pending events")
+	}
+
+	return events, nil
+}
+
+// pendingPipeline picks the earliest unpublished event of every article and
+// keeps those due by now.
+func pendingPipeline(now time.Time, limit int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"publishedAt": bson.M{"$exists": false}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$event.id"},
+			{Key: "earliest", Value: bson.M{"$first": "$$ROOT"}},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$earliest"}}},
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"nextAttemptAt": bson.M{"$exists": false}},
+			bson.M{"nextAttemptAt": bson.M{"$lte": now}},
+		}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	r.metrics.DBCall("OutboxMarkPublished")
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"publishedAt": publishedAt}, "$inc": bson.M{"attempts": 1}},
+	)
+	if err != nil {
+		r.metrics.DBErrorInc("OutboxMarkPublished", "update_error")
+		return errors.Wrap(err, "failed to mark event as published")
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, reason string, nextAttemptAt time.Time) error {
+	r.metrics.DBCall("OutboxMarkFailed")
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{"lastError": reason, "nextAttemptAt": nextAttemptAt},
+			"$inc": bson.M{"attempts": 1},
+		},
+	)
+	if err != nil {
+		r.metrics.DBErrorInc("OutboxMarkFailed", "update_error")
+		return errors.Wrap(err, "failed to record event failure")
+	}
+	return nil
+}
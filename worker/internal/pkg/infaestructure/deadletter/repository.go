@@ -0,0 +1,112 @@
+package deadletter
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+)
+
+// repository reads dead letters straight from the stream they were published
+// to, so the queue needs no storage of its own.
+type repository struct {
+	js      jetstream.JetStream
+	stream  string
+	subject string
+}
+
+// NewDeadLetterRepository serves the dead letters stored in stream under
+// subject, which may be a wildcard such as SPORTSTREAM.dlq.>.
+func NewDeadLetterRepository(js jetstream.JetStream, stream string, subject string) *repository {
+	return &repository{
+		js:      js,
+		stream:  stream,
+		subject: subject,
+	}
+}
+
+func (r repository) List(ctx context.Context) ([]models.DeadLetter, error) {
+	stream, err := r.js.Stream(ctx, r.stream)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting stream %s", r.stream)
+	}
+
+	letters := []models.DeadLetter{}
+	for sequence := uint64(1); ; {
+		msg, err := stream.GetMsg(ctx, sequence, jetstream.WithGetMsgSubject(r.subject))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return letters, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading dead letter after sequence %d", sequence)
+		}
+
+		letters = append(letters, toDeadLetter(msg))
+		sequence = msg.Sequence + 1
+	}
+}
+
+func (r repository) Get(ctx context.Context, sequence uint64) (models.DeadLetter, error) {
+	stream, err := r.js.Stream(ctx, r.stream)
+	if err != nil {
+		return models.DeadLetter{}, errors.Wrapf(err, "getting stream %s", r.stream)
+	}
+
+	msg, err := stream.GetMsg(ctx, sequence)
+	if errors.Is(err, jetstream.ErrMsgNotFound) || (err == nil && !r.matches(msg.Subject)) {
+		return models.DeadLetter{}, models.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return models.DeadLetter{}, errors.Wrapf(err, "reading dead letter %d", sequence)
+	}
+
+	return toDeadLetter(msg), nil
+}
+
+func (r repository) Publish(ctx context.Context, subject string, headers map[string][]string, payload []byte) error {
+	_, err := r.js.PublishMsg(ctx, &nats.Msg{
+		Subject: subject,
+		Header:  nats.Header(headers),
+		Data:    payload,
+	})
+	return err
+}
+
+func (r repository) Delete(ctx context.Context, sequence uint64) error {
+	stream, err := r.js.Stream(ctx, r.stream)
+	if err != nil {
+		return errors.Wrapf(err, "getting stream %s", r.stream)
+	}
+
+	err = stream.DeleteMsg(ctx, sequence)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return models.ErrDeadLetterNotFound
+	}
+	return err
+}
+
+// matches reports whether subject falls under the dead letter subject,
+// honouring a trailing > wildcard.
+func (r repository) matches(subject string) bool {
+	if prefix, ok := strings.CutSuffix(r.subject, ">"); ok {
+		return strings.HasPrefix(subject, prefix)
+	}
+	return subject == r.subject
+}
+
+func toDeadLetter(msg *jetstream.RawStreamMsg) models.DeadLetter {
+	return models.DeadLetter{
+		Sequence:        msg.Sequence,
+		Subject:         msg.Subject,
+		OriginalSubject: msg.Header.Get(models.DeadLetterSubjectHeader),
+		Reason:          msg.Header.Get(models.DeadLetterReasonHeader),
+		Deliveries:      msg.Header.Get(models.DeadLetterDeliveredHeader),
+		FailedAt:        msg.Header.Get(models.DeadLetterFailedAtHeader),
+		StoredAt:        msg.Time,
+		Headers:         msg.Header,
+		Payload:         string(msg.Data),
+	}
+}
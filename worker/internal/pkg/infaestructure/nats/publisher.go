@@ -0,0 +1,31 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Publisher publishes messages to JetStream, letting the stream drop copies
+// that share a message ID.
+type Publisher struct {
+	js jetstream.JetStream
+}
+
+func NewPublisher(js jetstream.JetStream) *Publisher {
+	return &Publisher{js: js}
+}
+
+func (p *Publisher) Publish(ctx context.Context, subject string, msgID string, data []byte) error {
+	msg := &nats.Msg{
+		Subject: subject,
+		Header:  nats.Header{},
+		Data:    data,
+	}
+	msg.Header.Set(nats.MsgIdHdr, msgID)
+	SetMsgCorrelationId(ctx, msg)
+
+	_, err := p.js.PublishMsg(ctx, msg)
+	return err
+}
@@ -0,0 +1,153 @@
+package subcriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	cnats "github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/nats"
+	"github.com/sts-solutions/base-code/cccorrelation"
+	"github.com/sts-solutions/base-code/ccmsgqueue"
+	"github.com/sts-solutions/base-code/ccotel/ccotelnats"
+)
+
+// Message is a consumed JetStream message exposing the explicit outcomes the
+// handler chooses between.
+type Message interface {
+	ccmsgqueue.ConsumeMessage
+	NakWithDelay(delay time.Duration) error
+	Term(reason string) error
+	NumDelivered() uint64
+	// InProgress restarts the ack wait while a message is retried.
+	InProgress() error
+}
+
+type jsMessage struct {
+	msg jetstream.Msg
+}
+
+func (m jsMessage) Headers() map[string][]string {
+	return m.msg.Headers()
+}
+
+func (m jsMessage) Subject() string {
+	return m.msg.Subject()
+}
+
+func (m jsMessage) Data() []byte {
+	return m.msg.Data()
+}
+
+func (m jsMessage) Ack() error {
+	return m.msg.Ack()
+}
+
+func (m jsMessage) Nack() error {
+	return m.msg.Nak()
+}
+
+func (m jsMessage) NakWithDelay(delay time.Duration) error {
+	return m.msg.NakWithDelay(delay)
+}
+
+func (m jsMessage) Term(reason string) error {
+	return m.msg.TermWithReason(reason)
+}
+
+func (m jsMessage) InProgress() error {
+	return m.msg.InProgress()
+}
+
+func (m jsMessage) NumDelivered() uint64 {
+	meta, err := m.msg.Metadata()
+	if err != nil {
+		return 1
+	}
+	return meta.NumDelivered
+}
+
+// Consumer pulls messages from a durable JetStream consumer and hands them to
+// the handler, which settles every message explicitly. Messages are handled
+// concurrently by a pool of workers, keeping the order of messages that share
+// an ordering key.
+type Consumer struct {
+	js          jetstream.JetStream
+	stream      string
+	name        string
+	handler     func(ctx context.Context, msg Message)
+	workers     int
+	maxInFlight int
+	key         func(msg Message) []string
+	metrics     metrics.MetricsHandler
+	pool        *Pool
+	consumeCtx  jetstream.ConsumeContext
+}
+
+func NewConsumer(js jetstream.JetStream, stream string, name string,
+	handler func(ctx context.Context, msg Message), metrics metrics.MetricsHandler) *Consumer {
+	return &Consumer{
+		js:      js,
+		stream:  stream,
+		name:    name,
+		handler: handler,
+		workers: 1,
+		key:     func(Message) []string { return nil },
+		metrics: metrics,
+	}
+}
+
+// WithConcurrency handles messages on the given number of workers, holding at
+// most maxInFlight unacknowledged messages; zero takes the consumer's
+// max_ack_pending. Messages sharing a key are handled in order.
+func (c *Consumer) WithConcurrency(workers int, maxInFlight int, key func(msg Message) []string) *Consumer {
+	c.workers = workers
+	c.maxInFlight = maxInFlight
+	c.key = key
+	return c
+}
+
+func (c *Consumer) Consume(ctx context.Context) error {
+	jsConsumer, err := c.js.Consumer(ctx, c.stream, c.name)
+	if err != nil {
+		return fmt.Errorf("getting consumer %s: %w", c.name, err)
+	}
+
+	config := jsConsumer.CachedInfo().Config
+	maxInFlight := inFlightLimit(c.maxInFlight, config.MaxAckPending)
+	c.pool = NewPool(c.workers, maxInFlight, c.key, c.handler, c.metrics).
+		WithKeepAlive(keepAliveInterval(config.AckWait))
+	c.pool.Start()
+
+	// The callback blocks while the pool is full, so buffer no more than it holds.
+	c.consumeCtx, err = jsConsumer.Consume(func(msg jetstream.Msg) {
+		spanCtx, span := ccotelnats.StartSubscriberSpanJetStream(msg, c.name)
+
+		if id := msg.Headers().Get(cnats.EventCorrelationIdKey); id != "" {
+			spanCtx = cccorrelation.WithCorrelationId(spanCtx, id)
+		}
+
+		c.pool.Submit(spanCtx, jsMessage{msg: msg}, func() { span.End() })
+	}, jetstream.PullMaxMessages(maxInFlight))
+	if err != nil {
+		c.pool.Stop()
+		return fmt.Errorf("consuming from %s: %w", c.name, err)
+	}
+
+	log.Logger().Info(ctx, fmt.Sprintf("NATS consumer %s started with %d workers and %d messages in flight",
+		c.name, c.workers, maxInFlight))
+	return nil
+}
+
+func (c *Consumer) Close(ctx context.Context) {
+	if c.consumeCtx != nil {
+		c.consumeCtx.Drain()
+		<-c.consumeCtx.Closed()
+	}
+	if c.pool != nil {
+		c.pool.Stop()
+	}
+	log.Logger().Info(ctx, fmt.Sprintf("NATS consumer %s closed successfully", c.name))
+}
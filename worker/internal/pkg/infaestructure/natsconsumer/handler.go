@@ -1,36 +1,313 @@
 package subcriptions
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/envelope"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/services/msgqueue/msgtype"
 	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
-	"github.com/sts-solutions/base-code/ccmsgqueue"
+	"github.com/sts-solutions/base-code/cccorrelation"
+)
+
+const (
+	rejectedDecodeError = "decode_error"
+	rejectedUnknownType = "unknown_type"
+
+	outcomeAck        = "ack"
+	outcomeNak        = "nak"
+	outcomeRetry      = "retry"
+	outcomeDeadLetter = "dead_letter"
+
+	// inProgressInterval is how often a message being retried restarts its
+	// ack wait, well within JetStream's default of 30s.
+	inProgressInterval = 10 * time.Second
 )
 
+// permanentError marks failures that redelivery cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// MessageHandler processes the envelope of one message type and schema version.
+type MessageHandler func(ctx context.Context, env envelope.Envelope) error
+
+type handlerKey struct {
+	msgType string
+	version int
+}
+
 type Handler struct {
 	services *Service
+	handlers map[handlerKey]MessageHandler
+	// wait pauses before a retry; it reports false if the context ended.
+	wait func(ctx context.Context, msg Message, delay time.Duration) bool
 }
 
 func NewHandler(services *Service) *Handler {
-	return &Handler{
+	h := &Handler{
 		services: services,
+		handlers: make(map[handlerKey]MessageHandler),
+		wait:     waitInProgress,
 	}
+
+	h.Register(msgtype.ArticlesUpdated, 1, h.handleArticlesUpdated)
+
+	return h
+}
+
+// Register routes envelopes of the given type and schema version to handler.
+func (h *Handler) Register(msgType msgtype.MessageType, version int, handler MessageHandler) {
+	h.handlers[handlerKey{msgType: msgType.Name(), version: version}] = handler
 }
 
-func (h Handler) HandleMessage(ctx context.Context, msg ccmsgqueue.ConsumeMessage) {
+// HandleMessage processes a message and settles it: acked on success, and
+// dead-lettered on permanent errors or once the delivery limit is reached.
+// Other failures are retried with backoff in place up to RetryInPlace times,
+// holding back the messages queued behind it, so a newer version of an
+// article isn't stored before an older one that failed briefly. Each retry
+// counts as a delivery. A message still failing after them, or when the
+// context ends, is nak'ed for redelivery after the backoff.
+func (h *Handler) HandleMessage(ctx context.Context, msg Message) {
 	log.Logger().Info(ctx, fmt.Sprintf("received message on subject: '%s': %s", msg.Subject(), string(msg.Data())))
 
-	var articles []models.UpsertArticle
+	for retries := 0; ; retries++ {
+		attempt := msg.NumDelivered() + uint64(retries)
+		err := h.dispatch(ctx, msg)
+		switch {
+		case err == nil:
+			h.ack(ctx, msg)
+			return
+		case errors.As(err, &permanentError{}):
+			log.Logger().Error(ctx, fmt.Sprintf("permanent failure processing message: %v", err))
+			h.deadLetter(ctx, msg, err)
+			return
+		case attempt >= uint64(h.services.Delivery.MaxDeliveries):
+			log.Logger().Error(ctx, fmt.Sprintf("giving up on message after %d deliveries: %v", attempt, err))
+			h.deadLetter(ctx, msg, err)
+			return
+		}
+
+		delay := h.backoff(attempt)
+		if retries >= h.services.Delivery.RetryInPlace {
+			log.Logger().Error(ctx, fmt.Sprintf("redelivering the message in %v: %v", delay, err))
+			h.nak(ctx, msg, delay)
+			return
+		}
+		log.Logger().Error(ctx, fmt.Sprintf("retrying the message in %v: %v", delay, err))
+		h.services.Metrics.MessageOutcomeInc(outcomeRetry)
+		if !h.wait(ctx, msg, delay) {
+			h.nak(ctx, msg, delay)
+			return
+		}
+	}
+}
+
+// waitInProgress waits for the delay, keeping the message from being
+// redelivered meanwhile.
+func waitInProgress(ctx context.Context, msg Message, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	ticker := time.NewTicker(inProgressInterval)
+	defer ticker.Stop()
+
+	if err := msg.InProgress(); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("extending the ack wait: %v", err))
+	}
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case <-ticker.C:
+			if err := msg.InProgress(); err != nil {
+				log.Logger().Error(ctx, fmt.Sprintf("extending the ack wait: %v", err))
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (h *Handler) dispatch(ctx context.Context, msg Message) error {
+	env, err := envelope.Decode(msg.Data())
+	if err != nil {
+		h.services.Metrics.MessageRejectedInc("", "", rejectedDecodeError)
+		return permanent(fmt.Errorf("error copying message: %w", err))
+	}
+
+	handler, ok := h.handlers[handlerKey{msgType: env.Type, version: env.SchemaVersion}]
+	if !ok {
+		h.services.Metrics.MessageRejectedInc(env.Type, strconv.Itoa(env.SchemaVersion), rejectedUnknownType)
+		return permanent(fmt.Errorf("unknown message type %s version %d from %s",
+			env.Type, env.SchemaVersion, env.Producer))
+	}
+
+	if env.CorrelationID != "" {
+		ctx = cccorrelation.WithCorrelationId(ctx, env.CorrelationID)
+	}
+
+	return handler(ctx, env)
+}
+
+func (h *Handler) ack(ctx context.Context, msg Message) {
+	h.services.Metrics.MessageOutcomeInc(outcomeAck)
+	if err := msg.Ack(); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("acking message: %v", err))
+	}
+}
+
+func (h *Handler) nak(ctx context.Context, msg Message, delay time.Duration) {
+	h.services.Metrics.MessageOutcomeInc(outcomeNak)
+	if err := msg.NakWithDelay(delay); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("naking message: %v", err))
+	}
+}
 
-	err := json.Unmarshal(msg.Data(), &articles)
+// backoff doubles the redelivery delay with every delivery, up to MaxBackoff.
+func (h *Handler) backoff(delivered uint64) time.Duration {
+	delay := h.services.Delivery.Backoff
+	for i := uint64(1); i < delivered && delay < h.services.Delivery.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if h.services.Delivery.MaxBackoff > 0 && delay > h.services.Delivery.MaxBackoff {
+		delay = h.services.Delivery.MaxBackoff
+	}
+	return delay
+}
+
+// deadLetter republishes the message with its original headers and the
+// failure reason to the dead letter subject, then terminates it. When the
+// dead letter cannot be published the message is redelivered instead.
+func (h *Handler) deadLetter(ctx context.Context, msg Message, cause error) {
+	header := nats.Header{}
+	for key, values := range msg.Headers() {
+		header[key] = append([]string(nil), values...)
+	}
+	// Keep broker deduplication from dropping the dead letter as a copy of the original.
+	if msgID := header.Get(nats.MsgIdHdr); msgID != "" {
+		header.Set(nats.MsgIdHdr, "dlq-"+msgID)
+	}
+	header.Set(models.DeadLetterReasonHeader, cause.Error())
+	header.Set(models.DeadLetterSubjectHeader, msg.Subject())
+	header.Set(models.DeadLetterDeliveredHeader, strconv.FormatUint(msg.NumDelivered(), 10))
+	header.Set(models.DeadLetterFailedAtHeader, time.Now().UTC().Format(time.RFC3339))
+
+	_, err := h.services.Publisher.PublishMsg(ctx, &nats.Msg{
+		Subject: h.services.Delivery.DeadLetterSubject,
+		Header:  header,
+		Data:    msg.Data(),
+	})
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("error copying message: %v", err))
+		log.Logger().Error(ctx, fmt.Sprintf("publishing dead letter: %v", err))
+		h.nak(ctx, msg, h.backoff(msg.NumDelivered()))
+		return
+	}
+
+	h.services.Metrics.MessageOutcomeInc(outcomeDeadLetter)
+	if err := msg.Term(cause.Error()); err != nil {
+		log.Logger().Error(ctx, fmt.Sprintf("terminating message: %v", err))
+	}
+}
+
+func (h *Handler) handleArticlesUpdated(ctx context.Context, env envelope.Envelope) error {
+	articles, err := decodeArticles(env.Payload)
+	if err != nil {
+		return permanent(fmt.Errorf("decoding articles: %w", err))
+	}
+
+	for i := range articles {
+		if articles[i].Source == "" {
+			articles[i].Source = env.Source
+		}
 	}
+
 	_, err = h.services.ArticleServ.UpsertByExternalID(ctx, articles)
+	if err != nil && unrecoverable(err) {
+		return permanent(err)
+	}
+	return err
+}
+
+// documentValidationFailure is the code of writes Mongo's schema validation
+// rejects.
+const documentValidationFailure = 121
+
+// unrecoverable reports whether every failure in err is one storing the
+// articles again can't fix: an article that isn't valid, a document the
+// schema rejects, or one still colliding with a unique index after the
+// article was retried from its stored version.
+func unrecoverable(err error) bool {
+	for _, err := range errors.GetErrors(err) {
+		var serverErr mongo.ServerError
+		switch {
+		case errors.As(err, &models.ValidationError{}):
+		case mongo.IsDuplicateKeyError(err):
+		case errors.As(err, &serverErr) && serverErr.HasErrorCode(documentValidationFailure):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// OrderingKeys keys a message by the feed ID of every article it carries, so
+// updates to one article are handled in order, whether they come alone or in
+// a legacy page. Messages that cannot be decoded have no key; the handler
+// dead-letters them anyway.
+func OrderingKeys(msg Message) []string {
+	env, err := envelope.Decode(msg.Data())
 	if err != nil {
-		log.Logger().Error(ctx, fmt.Sprintf("patching the message: %v", err))
+		return nil
+	}
+
+	articles, err := decodeArticles(env.Payload)
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(articles))
+	for _, article := range articles {
+		keys = append(keys, strconv.Itoa(article.ID))
+	}
+	return keys
+}
+
+// decodeArticles accepts a single article per message as well as the legacy
+// format carrying a whole page as a JSON array.
+func decodeArticles(data []byte) ([]models.UpsertArticle, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var articles []models.UpsertArticle
+		if err := json.Unmarshal(trimmed, &articles); err != nil {
+			return nil, err
+		}
+		return articles, nil
+	}
+
+	var article models.UpsertArticle
+	if err := json.Unmarshal(trimmed, &article); err != nil {
+		return nil, err
 	}
+	return []models.UpsertArticle{article}, nil
 }
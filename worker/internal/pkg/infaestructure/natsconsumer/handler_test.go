@@ -0,0 +1,414 @@
+package subcriptions
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/models"
+	"github.com/ronnyp07/SportStream/worker/internal/pkg/infaestructure/log"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeMessage struct {
+	data      []byte
+	headers   map[string][]string
+	delivered uint64
+
+	outcome    string
+	delay      time.Duration
+	inProgress atomic.Int32
+}
+
+func (m *fakeMessage) Headers() map[string][]string { return m.headers }
+func (m *fakeMessage) Subject() string              { return "SPORTSTREAM.status.updated" }
+func (m *fakeMessage) Data() []byte                 { return m.data }
+func (m *fakeMessage) Ack() error                   { m.outcome = outcomeAck; return nil }
+func (m *fakeMessage) Nack() error                  { m.outcome = outcomeNak; return nil }
+func (m *fakeMessage) Term(string) error            { m.outcome = outcomeDeadLetter; return nil }
+func (m *fakeMessage) NumDelivered() uint64         { return m.delivered }
+func (m *fakeMessage) InProgress() error            { m.inProgress.Add(1); return nil }
+func (m *fakeMessage) NakWithDelay(delay time.Duration) error {
+	m.outcome, m.delay = outcomeNak, delay
+	return nil
+}
+
+// fakeArticlesService fails its first failures calls with err, or every
+// call when failures is zero.
+type fakeArticlesService struct {
+	upserted []models.UpsertArticle
+	err      error
+	failures int
+	calls    int
+}
+
+func (f *fakeArticlesService) UpsertByExternalID(_ context.Context, articles []models.UpsertArticle) ([]models.UpsertResult, error) {
+	f.calls++
+	if f.err != nil && (f.failures == 0 || f.calls <= f.failures) {
+		return nil, f.err
+	}
+	f.upserted = append(f.upserted, articles...)
+	return []models.UpsertResult{}, nil
+}
+
+type fakePublisher struct {
+	published []*nats.Msg
+	err       error
+}
+
+func (f *fakePublisher) PublishMsg(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.published = append(f.published, msg)
+	return &jetstream.PubAck{}, nil
+}
+
+type fakeMetrics struct {
+	rejected []string
+}
+
+func (f *fakeMetrics) RegisterMetrics()                 {}
+func (f *fakeMetrics) DBCall(string)                    {}
+func (f *fakeMetrics) DBErrorInc(string, string)        {}
+func (f *fakeMetrics) MessageOutcomeInc(string)         {}
+func (f *fakeMetrics) EventPublishedInc(string, string) {}
+func (f *fakeMetrics) SetConsumerQueueDepth(int)        {}
+func (f *fakeMetrics) SetConsumerInFlight(int)          {}
+func (f *fakeMetrics) MessageRejectedInc(msgType string, version string, reason string) {
+	f.rejected = append(f.rejected, msgType+"/"+version+"/"+reason)
+}
+
+var testDelivery = DeliveryPolicy{
+	MaxDeliveries:     3,
+	Backoff:           time.Second,
+	MaxBackoff:        3 * time.Second,
+	RetryInPlace:      2,
+	DeadLetterSubject: "SPORTSTREAM.dlq.status.updated",
+}
+
+func TestMain(m *testing.M) {
+	if err := log.SetupLogger("WorkerTest"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestDecodeArticles(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantIDs []int
+		wantErr bool
+	}{
+		{name: "single article", data: `{"id": 7, "title": "one"}`, wantIDs: []int{7}},
+		{name: "legacy array", data: ` [{"id": 1}, {"id": 2}]`, wantIDs: []int{1, 2}},
+		{name: "malformed", data: `{"id": `, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := decodeArticles([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(articles) != len(tt.wantIDs) {
+				t.Fatalf("expected %d articles, got %d", len(tt.wantIDs), len(articles))
+			}
+			for i, article := range articles {
+				if article.ID != tt.wantIDs[i] {
+					t.Errorf("expected id %d, got %d", tt.wantIDs[i], article.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_HandleMessage(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantUpserted []int
+		wantRejected []string
+	}{
+		{
+			name: "envelope",
+			data: `{"type": "article-updated", "schemaVersion": 1, "producer": "poller", "source": "ecb",
+				"correlationID": "abc", "payload": {"id": 7}}`,
+			wantUpserted: []int{7},
+		},
+		{
+			name:         "legacy array",
+			data:         `[{"id": 1}, {"id": 2}]`,
+			wantUpserted: []int{1, 2},
+		},
+		{
+			name:         "unknown version",
+			data:         `{"type": "article-updated", "schemaVersion": 9, "payload": {"id": 7}}`,
+			wantRejected: []string{"article-updated/9/unknown_type"},
+		},
+		{
+			name:         "unknown type",
+			data:         `{"type": "article-deleted", "schemaVersion": 1, "payload": {"id": 7}}`,
+			wantRejected: []string{"article-deleted/1/unknown_type"},
+		},
+		{
+			name:         "undecodable",
+			data:         `not json`,
+			wantRejected: []string{"//decode_error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles := &fakeArticlesService{}
+			metrics := &fakeMetrics{}
+			handler := NewHandler(&Service{
+				ArticleServ: articles,
+				Metrics:     metrics,
+				Publisher:   &fakePublisher{},
+				Delivery:    testDelivery,
+			})
+
+			handler.HandleMessage(context.Background(), &fakeMessage{data: []byte(tt.data), delivered: 1})
+
+			if len(articles.upserted) != len(tt.wantUpserted) {
+				t.Fatalf("expected %d upserted articles, got %d", len(tt.wantUpserted), len(articles.upserted))
+			}
+			for i, article := range articles.upserted {
+				if article.ID != tt.wantUpserted[i] {
+					t.Errorf("expected id %d, got %d", tt.wantUpserted[i], article.ID)
+				}
+			}
+			if len(metrics.rejected) != len(tt.wantRejected) || (len(tt.wantRejected) > 0 && metrics.rejected[0] != tt.wantRejected[0]) {
+				t.Errorf("expected rejections %v, got %v", tt.wantRejected, metrics.rejected)
+			}
+		})
+	}
+}
+
+func writeError(code int) error {
+	return errors.Wrap(mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: code, Message: "write failed"}}},
+		"unable to upsert article 7")
+}
+
+func TestHandler_SettlesMessages(t *testing.T) {
+	valid := `{"type": "article-updated", "schemaVersion": 1, "payload": {"id": 7}}`
+	duplicateKey := writeError(11000)
+	invalid := errors.WithMessage(models.ValidationError{Field: "id", Reason: "0 is not a feed ID"},
+		"unable to upsert article 0")
+	tests := []struct {
+		name         string
+		data         string
+		delivered    uint64
+		upsertErr    error
+		failures     int
+		stopped      bool
+		publishErr   error
+		wantOutcome  string
+		wantWaits    []time.Duration
+		wantDelay    time.Duration
+		wantDeadLtrs int
+	}{
+		{name: "success acks", data: valid, delivered: 1, wantOutcome: outcomeAck},
+		{name: "transient error is retried in place", data: valid, delivered: 2, upsertErr: errors.New("timeout"),
+			failures: 1, wantOutcome: outcomeAck, wantWaits: []time.Duration{2 * time.Second}},
+		{name: "retries until the delivery limit", data: valid, delivered: 1, upsertErr: errors.New("timeout"),
+			wantOutcome: outcomeDeadLetter, wantWaits: []time.Duration{time.Second, 2 * time.Second}, wantDeadLtrs: 1},
+		{name: "delivery limit dead-letters", data: valid, delivered: 3, upsertErr: errors.New("timeout"),
+			wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "stopping redelivers", data: valid, delivered: 1, upsertErr: errors.New("timeout"), stopped: true,
+			wantOutcome: outcomeNak, wantWaits: []time.Duration{time.Second}, wantDelay: time.Second},
+		{name: "duplicate key dead-letters", data: valid, delivered: 1, upsertErr: duplicateKey,
+			wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "invalid article dead-letters", data: valid, delivered: 1, upsertErr: invalid,
+			wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "schema rejection dead-letters", data: valid, delivered: 1, upsertErr: writeError(121),
+			wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "permanent failures together dead-letter", data: valid, delivered: 1,
+			upsertErr: errors.Append(duplicateKey, invalid), wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "a transient failure among them is retried", data: valid, delivered: 1,
+			upsertErr: errors.Append(duplicateKey, errors.New("timeout")), failures: 1,
+			wantOutcome: outcomeAck, wantWaits: []time.Duration{time.Second}},
+		{name: "decode error dead-letters", data: `not json`, delivered: 1,
+			wantOutcome: outcomeDeadLetter, wantDeadLtrs: 1},
+		{name: "failed dead letter is redelivered", data: `not json`, delivered: 1, publishErr: errors.New("no stream"),
+			wantOutcome: outcomeNak, wantDelay: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &fakePublisher{err: tt.publishErr}
+			handler := NewHandler(&Service{
+				ArticleServ: &fakeArticlesService{err: tt.upsertErr, failures: tt.failures},
+				Metrics:     &fakeMetrics{},
+				Publisher:   publisher,
+				Delivery:    testDelivery,
+			})
+			var waits []time.Duration
+			handler.wait = func(_ context.Context, _ Message, delay time.Duration) bool {
+				waits = append(waits, delay)
+				return !tt.stopped
+			}
+			msg := &fakeMessage{
+				data:      []byte(tt.data),
+				delivered: tt.delivered,
+				headers:   map[string][]string{nats.MsgIdHdr: {"ecb-7-abc"}, "correlation-id": {"abc"}},
+			}
+
+			handler.HandleMessage(context.Background(), msg)
+
+			if msg.outcome != tt.wantOutcome {
+				t.Fatalf("expected outcome %s, got %s", tt.wantOutcome, msg.outcome)
+			}
+			if msg.delay != tt.wantDelay {
+				t.Errorf("expected delay %v, got %v", tt.wantDelay, msg.delay)
+			}
+			if !slices.Equal(waits, tt.wantWaits) {
+				t.Errorf("expected retries after %v, got %v", tt.wantWaits, waits)
+			}
+			if len(publisher.published) != tt.wantDeadLtrs {
+				t.Fatalf("expected %d dead letters, got %d", tt.wantDeadLtrs, len(publisher.published))
+			}
+			if tt.wantDeadLtrs == 0 {
+				return
+			}
+
+			dead := publisher.published[0]
+			if dead.Subject != testDelivery.DeadLetterSubject {
+				t.Errorf("expected subject %s, got %s", testDelivery.DeadLetterSubject, dead.Subject)
+			}
+			if dead.Header.Get("correlation-id") != "abc" {
+				t.Errorf("expected original headers to be kept, got %v", dead.Header)
+			}
+			if dead.Header.Get(nats.MsgIdHdr) != "dlq-ecb-7-abc" {
+				t.Errorf("expected a distinct message id, got %s", dead.Header.Get(nats.MsgIdHdr))
+			}
+			if dead.Header.Get(models.DeadLetterReasonHeader) == "" || dead.Header.Get(models.DeadLetterSubjectHeader) != msg.Subject() {
+				t.Errorf("expected the failure reason and original subject, got %v", dead.Header)
+			}
+			if string(dead.Data) != tt.data {
+				t.Errorf("expected original payload, got %s", dead.Data)
+			}
+		})
+	}
+}
+
+func TestHandler_RetryKeepsArticleOrder(t *testing.T) {
+	articles := &fakeArticlesService{err: errors.New("timeout"), failures: 1}
+	handler := NewHandler(&Service{
+		ArticleServ: articles,
+		Metrics:     &fakeMetrics{},
+		Publisher:   &fakePublisher{},
+		Delivery:    testDelivery,
+	})
+	handler.wait = func(context.Context, Message, time.Duration) bool { return true }
+
+	pool := NewPool(4, 8, OrderingKeys, handler.HandleMessage, &fakeMetrics{})
+	pool.Start()
+
+	edit := &fakeMessage{data: []byte(`{"type": "article-updated", "schemaVersion": 1, "payload": {"id": 7, "title": "Draft"}}`), delivered: 1}
+	newer := &fakeMessage{data: []byte(`{"type": "article-updated", "schemaVersion": 1, "payload": {"id": 7, "title": "Final"}}`), delivered: 1}
+	pool.Submit(context.Background(), edit, nil)
+	pool.Submit(context.Background(), newer, nil)
+	pool.Stop()
+
+	if len(articles.upserted) != 2 || articles.upserted[0].Title != "Draft" || articles.upserted[1].Title != "Final" {
+		t.Fatalf("expected the failed edit stored before the newer one, got %+v", articles.upserted)
+	}
+	if edit.outcome != outcomeAck || newer.outcome != outcomeAck {
+		t.Errorf("expected both messages acked, got %s and %s", edit.outcome, newer.outcome)
+	}
+}
+
+func TestOrderingKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{name: "single article", data: `{"type": "article-updated", "schemaVersion": 1, "payload": {"id": 7}}`, want: []string{"7"}},
+		{name: "legacy array", data: `[{"id": 1}, {"id": 2}, {"id": 3}]`, want: []string{"1", "2", "3"}},
+		{name: "undecodable", data: `not json`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OrderingKeys(&fakeMessage{data: []byte(tt.data)}); !slices.Equal(got, tt.want) {
+				t.Errorf("expected keys %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandler_Backoff(t *testing.T) {
+	handler := NewHandler(&Service{Delivery: testDelivery})
+
+	for delivered, want := range map[uint64]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 10: 3 * time.Second} {
+		if got := handler.backoff(delivered); got != want {
+			t.Errorf("delivery %d: expected %v, got %v", delivered, want, got)
+		}
+	}
+}
+
+func TestHandler_NaksOnceInPlaceRetriesRunOut(t *testing.T) {
+	delivery := testDelivery
+	delivery.MaxDeliveries = 10
+	delivery.RetryInPlace = 1
+	handler := NewHandler(&Service{
+		ArticleServ: &fakeArticlesService{err: errors.New("timeout")},
+		Metrics:     &fakeMetrics{},
+		Publisher:   &fakePublisher{},
+		Delivery:    delivery,
+	})
+	var waits []time.Duration
+	handler.wait = func(_ context.Context, _ Message, delay time.Duration) bool {
+		waits = append(waits, delay)
+		return true
+	}
+	msg := &fakeMessage{data: []byte(`{"type": "article-updated", "schemaVersion": 1, "payload": {"id": 7}}`), delivered: 1}
+
+	handler.HandleMessage(context.Background(), msg)
+
+	if !slices.Equal(waits, []time.Duration{time.Second}) {
+		t.Errorf("expected one retry in place, got %v", waits)
+	}
+	if msg.outcome != outcomeNak || msg.delay != 2*time.Second {
+		t.Errorf("expected a nak with the second delivery's backoff, got %s after %v", msg.outcome, msg.delay)
+	}
+}
+
+func TestDeliveryPolicy_Validate(t *testing.T) {
+	with := func(change func(*DeliveryPolicy)) DeliveryPolicy {
+		policy := testDelivery
+		change(&policy)
+		return policy
+	}
+	tests := []struct {
+		name    string
+		policy  DeliveryPolicy
+		wantErr bool
+	}{
+		{name: "valid", policy: testDelivery},
+		{name: "no in-place retries", policy: with(func(p *DeliveryPolicy) { p.RetryInPlace = 0 })},
+		{name: "unlimited deliveries", policy: with(func(p *DeliveryPolicy) { p.MaxDeliveries = 0 }), wantErr: true},
+		{name: "no backoff", policy: with(func(p *DeliveryPolicy) { p.Backoff = 0 }), wantErr: true},
+		{name: "max backoff below backoff", policy: with(func(p *DeliveryPolicy) { p.MaxBackoff = time.Millisecond }), wantErr: true},
+		{name: "negative in-place retries", policy: with(func(p *DeliveryPolicy) { p.RetryInPlace = -1 }), wantErr: true},
+		{name: "no dead letter subject", policy: with(func(p *DeliveryPolicy) { p.DeadLetterSubject = "" }), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,9 +1,54 @@
 package subcriptions
 
 import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
 	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/services"
 )
 
 type Service struct {
 	ArticleServ services.IArticlesService
+	Metrics     metrics.MetricsHandler
+	Publisher   Publisher
+	Delivery    DeliveryPolicy
+}
+
+// Publisher sends dead-lettered messages back to JetStream.
+type Publisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// DeliveryPolicy decides how failed messages are redelivered and when they
+// are dead-lettered.
+type DeliveryPolicy struct {
+	MaxDeliveries int
+	Backoff       time.Duration
+	MaxBackoff    time.Duration
+	// RetryInPlace is how many times a failing message is retried before it's
+	// nak'ed for redelivery with backoff, releasing the messages behind it.
+	RetryInPlace      int
+	DeadLetterSubject string
+}
+
+// Validate rejects policies that would retry a failing message forever or
+// without pause.
+func (p DeliveryPolicy) Validate() error {
+	switch {
+	case p.MaxDeliveries <= 0:
+		return errors.Errorf("max deliveries must be positive, got %d", p.MaxDeliveries)
+	case p.Backoff <= 0:
+		return errors.Errorf("backoff must be positive, got %v", p.Backoff)
+	case p.MaxBackoff < p.Backoff:
+		return errors.Errorf("max backoff %v is below the backoff %v", p.MaxBackoff, p.Backoff)
+	case p.RetryInPlace < 0:
+		return errors.Errorf("in-place retries must not be negative, got %d", p.RetryInPlace)
+	case p.DeadLetterSubject == "":
+		return errors.New("the dead letter subject is missing")
+	}
+	return nil
 }
@@ -0,0 +1,247 @@
+package subcriptions
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ronnyp07/SportStream/worker/internal/domain/ports/metrics"
+)
+
+// defaultAckWait is JetStream's ack wait for consumers that don't set one.
+const defaultAckWait = 30 * time.Second
+
+// defaultMaxInFlight bounds the pool when neither the configuration nor the
+// consumer's max_ack_pending does.
+const defaultMaxInFlight = 256
+
+type poolItem struct {
+	id   uint64
+	ctx  context.Context
+	msg  Message
+	done func()
+	// barrier is shared by the lanes of a message whose keys are on several
+	// of them.
+	barrier *barrier
+}
+
+// barrier holds the lanes of a message until all of them reach it; the last
+// one handles the message and releases the others.
+type barrier struct {
+	waiting atomic.Int32
+	handled chan struct{}
+}
+
+// Pool handles messages on a fixed number of workers. Messages sharing an
+// ordering key always go to the same worker, so they are handled in the order
+// they were received, and at most maxInFlight messages are held at once. A
+// message with several keys, such as a legacy page of articles, is queued on
+// the lane of each and handled once it heads all of them, so it keeps its
+// place among the messages of every key. Messages waiting behind a busy
+// worker are kept in progress so their ack wait doesn't run out.
+type Pool struct {
+	lanes    []chan poolItem
+	submit   sync.Mutex
+	slots    chan struct{}
+	key      func(msg Message) []string
+	handler  func(ctx context.Context, msg Message)
+	metrics  metrics.MetricsHandler
+	queued   atomic.Int64
+	inFlight atomic.Int64
+	wg       sync.WaitGroup
+
+	keepAlive time.Duration
+	nextID    uint64
+	waitingMu sync.Mutex
+	waiting   map[uint64]Message
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+func NewPool(workers int, maxInFlight int, key func(msg Message) []string,
+	handler func(ctx context.Context, msg Message), metrics metrics.MetricsHandler) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	p := &Pool{
+		lanes:   make([]chan poolItem, workers),
+		slots:   make(chan struct{}, maxInFlight),
+		key:     key,
+		handler: handler,
+		metrics: metrics,
+		waiting: make(map[uint64]Message),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for i := range p.lanes {
+		p.lanes[i] = make(chan poolItem, maxInFlight)
+	}
+
+	return p
+}
+
+// WithKeepAlive marks the messages waiting in a lane as in progress every
+// interval, which should be well within the consumer's ack wait. Zero turns
+// it off.
+func (p *Pool) WithKeepAlive(interval time.Duration) *Pool {
+	p.keepAlive = interval
+	return p
+}
+
+func (p *Pool) Start() {
+	for _, lane := range p.lanes {
+		p.wg.Add(1)
+		go p.work(lane)
+	}
+	go p.keepWaitingAlive()
+}
+
+// Submit queues the message on the workers owning its keys, blocking while
+// maxInFlight messages are already held. done runs once the message is handled.
+func (p *Pool) Submit(ctx context.Context, msg Message, done func()) {
+	p.slots <- struct{}{}
+	p.metrics.SetConsumerInFlight(int(p.inFlight.Add(1)))
+	p.metrics.SetConsumerQueueDepth(int(p.queued.Add(1)))
+
+	lanes := p.lanesOf(p.key(msg))
+	item := poolItem{ctx: ctx, msg: msg, done: done}
+	if len(lanes) > 1 {
+		item.barrier = &barrier{handled: make(chan struct{})}
+		item.barrier.waiting.Store(int32(len(lanes)))
+	}
+
+	// Every lane sees messages in the order they were submitted, so lanes
+	// held at a barrier never wait on each other. A lane holds at most
+	// maxInFlight messages, which its buffer fits.
+	p.submit.Lock()
+	defer p.submit.Unlock()
+	p.waitingMu.Lock()
+	p.nextID++
+	item.id = p.nextID
+	p.waiting[item.id] = msg
+	p.waitingMu.Unlock()
+	for _, lane := range lanes {
+		p.lanes[lane] <- item
+	}
+}
+
+// Stop waits for the queued messages to be handled. No message may be
+// submitted once Stop is called.
+func (p *Pool) Stop() {
+	for _, lane := range p.lanes {
+		close(lane)
+	}
+	p.wg.Wait()
+	close(p.stop)
+	<-p.stopped
+}
+
+// keepWaitingAlive restarts the ack wait of the messages not yet handled
+// until the pool stops, so a message queued behind a slow one, such as one
+// being retried in place, isn't redelivered while it waits.
+func (p *Pool) keepWaitingAlive() {
+	defer close(p.stopped)
+	if p.keepAlive <= 0 {
+		<-p.stop
+		return
+	}
+
+	ticker := time.NewTicker(p.keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.waitingMu.Lock()
+			msgs := make([]Message, 0, len(p.waiting))
+			for _, msg := range p.waiting {
+				msgs = append(msgs, msg)
+			}
+			p.waitingMu.Unlock()
+
+			for _, msg := range msgs {
+				// A failure leaves the message to be redelivered, which the
+				// handler copes with.
+				_ = msg.InProgress()
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Pool) work(lane chan poolItem) {
+	defer p.wg.Done()
+
+	for item := range lane {
+		if item.barrier != nil && item.barrier.waiting.Add(-1) > 0 {
+			<-item.barrier.handled
+			continue
+		}
+
+		p.waitingMu.Lock()
+		delete(p.waiting, item.id)
+		p.waitingMu.Unlock()
+		p.metrics.SetConsumerQueueDepth(int(p.queued.Add(-1)))
+
+		p.handler(item.ctx, item.msg)
+		if item.done != nil {
+			item.done()
+		}
+		if item.barrier != nil {
+			close(item.barrier.handled)
+		}
+
+		p.metrics.SetConsumerInFlight(int(p.inFlight.Add(-1)))
+		<-p.slots
+	}
+}
+
+// lanesOf returns the lanes of the keys, in order and without repeats. A
+// message without keys goes to the lane of the empty key.
+func (p *Pool) lanesOf(keys []string) []int {
+	if len(keys) == 0 {
+		return []int{p.lane("")}
+	}
+
+	lanes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		lanes = append(lanes, p.lane(key))
+	}
+	slices.Sort(lanes)
+	return slices.Compact(lanes)
+}
+
+func (p *Pool) lane(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.lanes)))
+}
+
+// inFlightLimit caps the configured limit at the consumer's max_ack_pending,
+// since JetStream stops delivering once that many messages are unacknowledged.
+// A configured limit of zero takes the consumer's.
+func inFlightLimit(configured int, maxAckPending int) int {
+	limit := configured
+	if limit <= 0 {
+		limit = maxAckPending
+	}
+	if maxAckPending > 0 && limit > maxAckPending {
+		limit = maxAckPending
+	}
+	if limit <= 0 {
+		limit = defaultMaxInFlight
+	}
+	return limit
+}
+
+// keepAliveInterval restarts the ack wait of waiting messages twice within it.
+func keepAliveInterval(ackWait time.Duration) time.Duration {
+	if ackWait <= 0 {
+		ackWait = defaultAckWait
+	}
+	return ackWait / 2
+}
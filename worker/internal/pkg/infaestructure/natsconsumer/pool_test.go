@@ -0,0 +1,175 @@
+package subcriptions
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_KeepsOrderPerKey(t *testing.T) {
+	const keys, perKey, maxInFlight = 5, 20, 4
+
+	var (
+		mu       sync.Mutex
+		handled  = make(map[string][]int)
+		inFlight atomic.Int64
+		peak     atomic.Int64
+	)
+	handler := func(_ context.Context, msg Message) {
+		current := inFlight.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		m := msg.(*fakeMessage)
+		mu.Lock()
+		handled[m.headers["key"][0]] = append(handled[m.headers["key"][0]], int(m.delivered))
+		mu.Unlock()
+		inFlight.Add(-1)
+	}
+
+	pool := NewPool(3, maxInFlight, func(msg Message) []string { return msg.Headers()["key"] },
+		handler, &fakeMetrics{})
+	pool.Start()
+
+	var done atomic.Int64
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			msg := &fakeMessage{headers: map[string][]string{"key": {strconv.Itoa(k)}}, delivered: uint64(i)}
+			pool.Submit(context.Background(), msg, func() { done.Add(1) })
+		}
+	}
+	pool.Stop()
+
+	if done.Load() != keys*perKey {
+		t.Fatalf("expected %d messages done, got %d", keys*perKey, done.Load())
+	}
+	if peak.Load() > maxInFlight {
+		t.Errorf("expected at most %d messages in flight, got %d", maxInFlight, peak.Load())
+	}
+	for key, order := range handled {
+		for i, seq := range order {
+			if seq != i {
+				t.Fatalf("key %s handled out of order: %v", key, order)
+			}
+		}
+	}
+}
+
+func TestPool_KeepsOrderAcrossKeys(t *testing.T) {
+	// Messages with several keys, like legacy pages, keep their place among
+	// the messages of each of their keys.
+	keySets := [][]string{{"a"}, {"a", "b"}, {"b"}, {"a", "b", "c"}, {"c"}, {"b", "c"}, {"a"}, {"c", "a"}}
+
+	var (
+		mu      sync.Mutex
+		handled = make(map[string][]int)
+	)
+	handler := func(_ context.Context, msg Message) {
+		time.Sleep(time.Millisecond)
+		m := msg.(*fakeMessage)
+		mu.Lock()
+		for _, key := range m.headers["key"] {
+			handled[key] = append(handled[key], int(m.delivered))
+		}
+		mu.Unlock()
+	}
+
+	pool := NewPool(3, 4, func(msg Message) []string { return msg.Headers()["key"] }, handler, &fakeMetrics{})
+	pool.Start()
+
+	for i := 0; i < 10*len(keySets); i++ {
+		msg := &fakeMessage{headers: map[string][]string{"key": keySets[i%len(keySets)]}, delivered: uint64(i)}
+		pool.Submit(context.Background(), msg, nil)
+	}
+	pool.Stop()
+
+	for key, order := range handled {
+		for i := 1; i < len(order); i++ {
+			if order[i] < order[i-1] {
+				t.Fatalf("key %s handled out of order: %v", key, order)
+			}
+		}
+	}
+	if len(handled["a"]) != 50 {
+		t.Errorf("expected 50 messages with key a, got %d", len(handled["a"]))
+	}
+}
+
+func TestPool_KeepsWaitingMessagesAlive(t *testing.T) {
+	// The first message blocks its lane, as one retried in place does; the
+	// messages queued behind it must not run out their ack wait meanwhile.
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := func(_ context.Context, msg Message) {
+		if msg.NumDelivered() == 0 {
+			close(started)
+			<-release
+		}
+	}
+
+	pool := NewPool(1, 4, func(Message) []string { return nil }, handler, &fakeMetrics{}).
+		WithKeepAlive(time.Millisecond)
+	pool.Start()
+
+	msgs := []*fakeMessage{{delivered: 0}, {delivered: 1}, {delivered: 2}}
+	for _, msg := range msgs {
+		pool.Submit(context.Background(), msg, nil)
+	}
+	<-started
+
+	deadline := time.Now().Add(time.Second)
+	for msgs[1].inProgress.Load() < 2 || msgs[2].inProgress.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the waiting messages to be kept in progress, got %d and %d",
+				msgs[1].inProgress.Load(), msgs[2].inProgress.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	pool.Stop()
+
+	if msgs[0].inProgress.Load() != 0 {
+		t.Errorf("expected the message being handled to be left to its handler, got %d calls",
+			msgs[0].inProgress.Load())
+	}
+}
+
+func TestKeepAliveInterval(t *testing.T) {
+	if got := keepAliveInterval(30 * time.Second); got != 15*time.Second {
+		t.Errorf("expected 15s, got %v", got)
+	}
+	if got := keepAliveInterval(0); got != defaultAckWait/2 {
+		t.Errorf("expected half the default ack wait, got %v", got)
+	}
+}
+
+func TestInFlightLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		configured    int
+		maxAckPending int
+		want          int
+	}{
+		{name: "takes max_ack_pending", configured: 0, maxAckPending: 1000, want: 1000},
+		{name: "below max_ack_pending", configured: 64, maxAckPending: 1000, want: 64},
+		{name: "capped at max_ack_pending", configured: 5000, maxAckPending: 1000, want: 1000},
+		{name: "unlimited max_ack_pending", configured: 64, maxAckPending: -1, want: 64},
+		{name: "nothing set", configured: 0, maxAckPending: -1, want: defaultMaxInFlight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inFlightLimit(tt.configured, tt.maxAckPending); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}